@@ -0,0 +1,270 @@
+// Package agent runs tool-calling loops on top of a prompts.Prompter.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+// DefaultMaxSteps is the default maximum number of model turns of a run.
+const DefaultMaxSteps = 10
+
+// ErrMaxSteps is returned when a run did not finish within the maximum number of steps.
+var ErrMaxSteps = errors.New("agent: maximum number of steps exceeded")
+
+// Handler executes a function call with the raw JSON arguments and returns its output.
+type Handler func(ctx context.Context, arguments string) (string, error)
+
+// Tool is a function tool that can be called by the model.
+type Tool struct {
+	// Definition is the function definition sent to the model.
+	Definition openai.ResponseFunctionDefinition
+	// Handler executes the function call.
+	Handler Handler
+}
+
+// Opt is a function type for configuring the Agent.
+type Opt func(*Agent)
+
+// Agent runs a tool-calling loop. It sends the request, executes the
+// function calls of the response with the registered tools, appends their
+// outputs to the input and repeats until the model responds with a final
+// message or the maximum number of steps is reached.
+type Agent struct {
+	prompter prompts.Prompter[*openai.ResponseRequest, *openai.Response]
+	maxSteps int
+	parallel bool
+	strict   bool
+
+	mu    sync.RWMutex
+	tools map[string]Tool
+	order []string
+}
+
+// New creates a new Agent around the given prompter.
+//
+//	a := agent.New(perplexity.New(client), agent.WithParallelToolCalls())
+//	agent.Register(a, "get_horoscope", "Get the horoscope for a given zodiac sign.", getHoroscope)
+//
+//	res, err := a.Run(ctx, req)
+func New(prompter prompts.Prompter[*openai.ResponseRequest, *openai.Response], opts ...Opt) *Agent {
+	a := &Agent{
+		prompter: prompter,
+		maxSteps: DefaultMaxSteps,
+		tools:    make(map[string]Tool),
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// WithMaxSteps sets the maximum number of model turns of a run.
+func WithMaxSteps(n int) Opt {
+	return func(a *Agent) {
+		a.maxSteps = max(n, 1)
+	}
+}
+
+// WithParallelToolCalls executes the function calls of a response in parallel.
+func WithParallelToolCalls() Opt {
+	return func(a *Agent) {
+		a.parallel = true
+	}
+}
+
+// WithStrictTools registers the tools with strict parameter schemas.
+func WithStrictTools() Opt {
+	return func(a *Agent) {
+		a.strict = true
+	}
+}
+
+// Add adds the tool to the registry. An existing tool with the same name is replaced.
+func (a *Agent) Add(tool Tool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	name := tool.Definition.Name
+	if _, ok := a.tools[name]; !ok {
+		a.order = append(a.order, name)
+	}
+	a.tools[name] = tool
+}
+
+// Tools returns the registered tools in the order they were added.
+func (a *Agent) Tools() []Tool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(a.order))
+	for _, name := range a.order {
+		tools = append(tools, a.tools[name])
+	}
+
+	return tools
+}
+
+// Register registers a typed Go function as a tool. The parameters are
+// derived from the argument struct T and the result R is sent to the model
+// as JSON, or as is if it is a string.
+func Register[T, R any](a *Agent, name, description string, fn func(context.Context, T) (R, error)) error {
+	def, err := openai.NewFunctionDefinitionFor(name, description, a.strict, fn)
+	if err != nil {
+		return err
+	}
+
+	a.Add(Tool{
+		Definition: def,
+		Handler: func(ctx context.Context, arguments string) (string, error) {
+			var in T
+			if arguments != "" {
+				if err := json.Unmarshal([]byte(arguments), &in); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+
+			out, err := fn(ctx, in)
+			if err != nil {
+				return "", err
+			}
+
+			if s, ok := any(out).(string); ok {
+				return s, nil
+			}
+
+			b, err := json.Marshal(out)
+			if err != nil {
+				return "", err
+			}
+
+			return string(b), nil
+		},
+	})
+
+	return nil
+}
+
+// Run runs the tool-calling loop for the request and returns the final
+// response. The request is not modified and every step sends a new request.
+// A failed response is returned with its error without executing its function
+// calls. If the model still calls functions in the last step, the response
+// is returned with ErrMaxSteps and the calls are not executed.
+func (a *Agent) Run(ctx context.Context, req *openai.ResponseRequest) (*openai.Response, error) {
+	tools := a.withTools(req.Tools)
+	input := append([]openai.ResponseInput{}, req.Input...)
+
+	for step := 1; ; step++ {
+		body := *req
+		body.Input = append([]openai.ResponseInput{}, input...)
+		body.Tools = tools
+
+		res, err := a.prompter.Respond(ctx, &body)
+		if err != nil {
+			return res, err
+		}
+
+		if err := res.Err(); err != nil {
+			return res, err
+		}
+
+		calls := res.FunctionCalls()
+		if len(calls) == 0 {
+			return res, nil
+		}
+
+		if step >= a.maxSteps {
+			return res, ErrMaxSteps
+		}
+
+		for _, output := range res.Output {
+			if item, ok := openai.NewInputItem(output); ok {
+				input = append(input, item)
+			}
+		}
+
+		input = append(input, a.call(ctx, calls)...)
+	}
+}
+
+// withTools returns the tools of the request with the registered tools
+// that are not yet declared.
+func (a *Agent) withTools(tools []openai.ResponseTool) []openai.ResponseTool {
+	declared := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		if fn, ok := t.Tool.(openai.ResponseFunctionTool); ok {
+			declared[fn.Function.Name] = true
+		}
+	}
+
+	all := append([]openai.ResponseTool{}, tools...)
+	for _, t := range a.Tools() {
+		if !declared[t.Definition.Name] {
+			all = append(all, openai.ResponseTool{Tool: openai.ResponseFunctionTool{Function: t.Definition}})
+		}
+	}
+
+	return all
+}
+
+// call executes the function calls and returns their outputs in order.
+func (a *Agent) call(ctx context.Context, calls []openai.ResponseOutputFunctionCall) []openai.ResponseInput {
+	outputs := make([]openai.ResponseInput, len(calls))
+
+	if !a.parallel {
+		for i, c := range calls {
+			outputs[i] = a.execute(ctx, c)
+		}
+
+		return outputs
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range calls {
+		wg.Go(func() {
+			outputs[i] = a.execute(ctx, c)
+		})
+	}
+	wg.Wait()
+
+	return outputs
+}
+
+// execute executes a single function call. Errors and panics of the handler
+// are reported back to the model as the output of the call.
+func (a *Agent) execute(ctx context.Context, call openai.ResponseOutputFunctionCall) (input openai.ResponseInput) {
+	a.mu.RLock()
+	tool, ok := a.tools[call.Name]
+	a.mu.RUnlock()
+
+	if !ok {
+		return openai.NewFunctionCallOutput(call.CallID, errorOutput(fmt.Errorf("unknown tool %q", call.Name)))
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			input = openai.NewFunctionCallOutput(call.CallID, errorOutput(fmt.Errorf("tool %q panicked: %v", call.Name, r)))
+		}
+	}()
+
+	out, err := tool.Handler(ctx, call.Arguments)
+	if err != nil {
+		return openai.NewFunctionCallOutput(call.CallID, errorOutput(err))
+	}
+
+	return openai.NewFunctionCallOutput(call.CallID, out)
+}
+
+// errorOutput returns the JSON encoded error output of a function call.
+func errorOutput(err error) string {
+	b, _ := json.Marshal(map[string]string{"error": err.Error()})
+	return string(b)
+}
@@ -0,0 +1,168 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/katallaxie/prompts/agent"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+type responderFunc func(ctx context.Context, req *openai.ResponseRequest) (*openai.Response, error)
+
+func (f responderFunc) Respond(ctx context.Context, req *openai.ResponseRequest) (*openai.Response, error) {
+	return f(ctx, req)
+}
+
+type HoroscopeArgs struct {
+	Sign string `json:"sign"`
+}
+
+func response(t *testing.T, data string) *openai.Response {
+	t.Helper()
+
+	res := &openai.Response{}
+	require.NoError(t, json.Unmarshal([]byte(data), res))
+
+	return res
+}
+
+func TestAgentRun(t *testing.T) {
+	var requests []*openai.ResponseRequest
+
+	p := responderFunc(func(_ context.Context, req *openai.ResponseRequest) (*openai.Response, error) {
+		requests = append(requests, req)
+
+		if len(requests) == 1 {
+			return response(t, `{"output":[
+				{"type":"function_call","call_id":"call_1","name":"get_horoscope","arguments":"{\"sign\":\"leo\"}"},
+				{"type":"function_call","call_id":"call_2","name":"get_horoscope","arguments":"{\"sign\":\"cat\"}"},
+				{"type":"function_call","call_id":"call_3","name":"unknown","arguments":"{}"}
+			]}`), nil
+		}
+
+		return response(t, `{"output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Stars align."}]}]}`), nil
+	})
+
+	a := agent.New(p, agent.WithParallelToolCalls())
+	err := agent.Register(a, "get_horoscope", "Get the horoscope.", func(_ context.Context, args HoroscopeArgs) (string, error) {
+		if args.Sign != "leo" {
+			return "", errors.New("unknown sign")
+		}
+
+		return "Good day, " + args.Sign, nil
+	})
+	require.NoError(t, err)
+
+	req := openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "My horoscope?")))
+
+	res, err := a.Run(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "Stars align.", res.OutputText())
+
+	require.Len(t, requests, 2)
+	require.Len(t, requests[0].Tools, 1)
+	require.Len(t, requests[0].Input, 1)
+	require.Len(t, req.Input, 1)
+
+	input := requests[1].Input
+	require.Len(t, input, 7)
+
+	want := []string{
+		"Good day, leo",
+		`{"error":"unknown sign"}`,
+		`{"error":"unknown tool \"unknown\""}`,
+	}
+
+	for i, w := range want {
+		output, ok := input[4+i].GetFunctionCallOutput()
+		require.True(t, ok)
+		require.Equal(t, w, output.Output)
+	}
+}
+
+func TestAgentRunMaxSteps(t *testing.T) {
+	const maxSteps = 3
+
+	var requests int
+
+	p := responderFunc(func(_ context.Context, _ *openai.ResponseRequest) (*openai.Response, error) {
+		requests++
+		return response(t, `{"output":[{"type":"function_call","call_id":"call_1","name":"noop","arguments":"{}"}]}`), nil
+	})
+
+	var calls int
+
+	a := agent.New(p, agent.WithMaxSteps(maxSteps))
+	a.Add(agent.Tool{
+		Definition: openai.ResponseFunctionDefinition{Name: "noop"},
+		Handler: func(context.Context, string) (string, error) {
+			calls++
+			return "ok", nil
+		},
+	})
+
+	res, err := a.Run(context.Background(), openai.NewResponseRequest())
+	require.ErrorIs(t, err, agent.ErrMaxSteps)
+	require.NotNil(t, res)
+	require.Len(t, res.FunctionCalls(), 1)
+	require.Equal(t, maxSteps, requests)
+	require.Equal(t, maxSteps-1, calls)
+}
+
+func TestAgentRunFailedResponse(t *testing.T) {
+	p := responderFunc(func(_ context.Context, _ *openai.ResponseRequest) (*openai.Response, error) {
+		return response(t, `{"status":"failed","error":{"code":"server_error","message":"boom"},"output":[
+			{"type":"function_call","call_id":"call_1","name":"noop","arguments":"{}"}
+		]}`), nil
+	})
+
+	called := false
+
+	a := agent.New(p)
+	a.Add(agent.Tool{
+		Definition: openai.ResponseFunctionDefinition{Name: "noop"},
+		Handler: func(context.Context, string) (string, error) {
+			called = true
+			return "ok", nil
+		},
+	})
+
+	res, err := a.Run(context.Background(), openai.NewResponseRequest())
+	require.EqualError(t, err, res.Err().Error())
+	require.False(t, called)
+}
+
+func TestAgentRunPanic(t *testing.T) {
+	var requests []*openai.ResponseRequest
+
+	p := responderFunc(func(_ context.Context, req *openai.ResponseRequest) (*openai.Response, error) {
+		requests = append(requests, req)
+
+		if len(requests) == 1 {
+			return response(t, `{"output":[{"type":"function_call","call_id":"call_1","name":"explode","arguments":"{}"}]}`), nil
+		}
+
+		return response(t, `{"output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Sorry."}]}]}`), nil
+	})
+
+	a := agent.New(p, agent.WithParallelToolCalls())
+	a.Add(agent.Tool{
+		Definition: openai.ResponseFunctionDefinition{Name: "explode"},
+		Handler: func(context.Context, string) (string, error) {
+			panic("boom")
+		},
+	})
+
+	res, err := a.Run(context.Background(), openai.NewResponseRequest())
+	require.NoError(t, err)
+	require.Equal(t, "Sorry.", res.OutputText())
+
+	require.Len(t, requests, 2)
+	output, ok := requests[1].Input[1].GetFunctionCallOutput()
+	require.True(t, ok)
+	require.Equal(t, `{"error":"tool \"explode\" panicked: boom"}`, output.Output)
+}
@@ -0,0 +1,413 @@
+// Package anthropic implements a Prompter for the Anthropic Messages API.
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+type (
+	ResponseRequest = openai.ResponseRequest
+	Response        = openai.Response
+	ResponseInput   = openai.ResponseInput
+	ResponseTool    = openai.ResponseTool
+)
+
+// DefaultURL is the default endpoint for the Anthropic API.
+const DefaultURL = "https://api.anthropic.com/v1/"
+
+// DefaultModel is the default model for the Anthropic API.
+const DefaultModel = "claude-opus-4-6"
+
+// DefaultVersion is the default anthropic-version header.
+const DefaultVersion = "2023-06-01"
+
+// DefaultMaxTokens is the maximum number of tokens of requests without a limit.
+const DefaultMaxTokens = 4096
+
+const (
+	apiKeyHeader  = "X-Api-Key"
+	versionHeader = "Anthropic-Version"
+	betaHeader    = "Anthropic-Beta"
+)
+
+// providerName is the name of the provider reported to middleware.
+const providerName = "anthropic"
+
+// ErrUnsupported is returned when a request can not be translated to the Messages API.
+var ErrUnsupported = errors.New("anthropic: unsupported")
+
+// Anthropic is a struct that implements the Prompter interface for the Anthropic API.
+type Anthropic[I *ResponseRequest, O *Response] struct {
+	client    *prompts.Client
+	maxTokens int
+}
+
+var _ prompts.Prompter[*ResponseRequest, *Response] = (*Anthropic[*ResponseRequest, *Response])(nil)
+
+// Opts are the options of the Anthropic provider.
+type Opts struct {
+	// BaseURL is the endpoint of the API.
+	BaseURL string
+	// APIKey is sent as the x-api-key header.
+	APIKey string
+	// Version is the anthropic-version header.
+	Version string
+	// Betas are the beta features sent as the anthropic-beta header.
+	Betas []string
+	// MaxTokens is the maximum number of tokens of requests without a limit.
+	MaxTokens int
+}
+
+// Opt is a function type for configuring the Anthropic provider.
+type Opt func(*Opts)
+
+// WithBaseURL sets the endpoint of the API.
+func WithBaseURL(u string) Opt {
+	return func(o *Opts) {
+		o.BaseURL = u
+	}
+}
+
+// WithAPIKey sets the x-api-key header.
+func WithAPIKey(key string) Opt {
+	return func(o *Opts) {
+		o.APIKey = key
+	}
+}
+
+// WithVersion sets the anthropic-version header.
+func WithVersion(version string) Opt {
+	return func(o *Opts) {
+		o.Version = version
+	}
+}
+
+// WithBetas enables the given beta features.
+func WithBetas(betas ...string) Opt {
+	return func(o *Opts) {
+		o.Betas = append(o.Betas, betas...)
+	}
+}
+
+// WithMaxTokens sets the maximum number of tokens of requests without a limit.
+func WithMaxTokens(n int) Opt {
+	return func(o *Opts) {
+		o.MaxTokens = n
+	}
+}
+
+// New creates a new Anthropic with the given client.
+//
+//	prompt := anthropic.New(prompts.NewClient(), anthropic.WithAPIKey(os.Getenv("ANTHROPIC_API_KEY")))
+func New(client *prompts.Client, opts ...Opt) prompts.Prompter[*ResponseRequest, *Response] {
+	o := &Opts{
+		BaseURL:   DefaultURL,
+		Version:   DefaultVersion,
+		MaxTokens: DefaultMaxTokens,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base := client.New().Base(withTrailingSlash(o.BaseURL)).Set(versionHeader, o.Version)
+
+	if o.APIKey != "" {
+		base.Set(apiKeyHeader, o.APIKey)
+	}
+
+	if len(o.Betas) > 0 {
+		base.Set(betaHeader, strings.Join(o.Betas, ","))
+	}
+
+	return &Anthropic[*ResponseRequest, *Response]{client: base, maxTokens: o.MaxTokens}
+}
+
+// ProviderName returns the name of the provider.
+func (p *Anthropic[I, O]) ProviderName() string {
+	return providerName
+}
+
+// Respond translates the request into a Messages API request, sends it and
+// maps the message back into a response.
+func (p *Anthropic[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	body, err := NewMessageRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if body.MaxTokens == 0 {
+		body.MaxTokens = p.maxTokens
+	}
+
+	msg := &MessageResponse{}
+
+	_, err = p.client.New().Post("messages").BodyJSON(body).ReceiveSuccess(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(msg), nil
+}
+
+// NewMessageRequest translates a response request into a Messages API request.
+// Instructions and system or developer messages become the system prompt,
+// function calls and their outputs become tool_use and tool_result blocks.
+func NewMessageRequest(req *ResponseRequest) (*MessageRequest, error) {
+	body := &MessageRequest{
+		Model:       req.Model,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		TopK:        req.TopK,
+	}
+
+	if req.MaxTokens != nil {
+		body.MaxTokens = *req.MaxTokens
+	}
+
+	system := []string{}
+	if req.Instructions != "" {
+		system = append(system, req.Instructions)
+	}
+
+	for _, input := range req.Input {
+		if input.Item == nil && (input.Role == openai.RoleSystem || input.Role == openai.RoleDeveloper) {
+			for _, c := range input.Content {
+				if text, ok := c.GetText(); ok {
+					system = append(system, text.Text)
+				}
+			}
+
+			continue
+		}
+
+		role, blocks, err := newContentBlocks(input)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// consecutive messages of the same role are merged, e.g. the
+		// outputs of parallel function calls.
+		if n := len(body.Messages); n > 0 && body.Messages[n-1].Role == role {
+			body.Messages[n-1].Content = append(body.Messages[n-1].Content, blocks...)
+			continue
+		}
+
+		body.Messages = append(body.Messages, Message{Role: role, Content: blocks})
+	}
+
+	body.System = strings.Join(system, "\n\n")
+
+	for _, t := range req.Tools {
+		tool, err := newTool(t)
+		if err != nil {
+			return nil, err
+		}
+
+		body.Tools = append(body.Tools, tool)
+	}
+
+	switch req.ToolChoice {
+	case openai.ToolChoiceAuto:
+		body.ToolChoice = &ToolChoice{Type: "auto"}
+	case openai.ToolChoiceNone:
+		body.ToolChoice = &ToolChoice{Type: "none"}
+	case openai.ToolChoiceRequired:
+		body.ToolChoice = &ToolChoice{Type: "any"}
+	default:
+	}
+
+	return body, nil
+}
+
+// newContentBlocks translates an input into the content blocks of a message with the returned role.
+func newContentBlocks(input ResponseInput) (Role, []ContentBlock, error) {
+	switch item := input.Item.(type) {
+	case nil:
+	case openai.ResponseInputFunctionCallOutput:
+		return RoleUser, []ContentBlock{{Type: BlockTypeToolResult, ToolUseID: item.CallID, Content: item.Output}}, nil
+	case openai.ResponseOutputFunctionCall:
+		args := json.RawMessage(item.Arguments)
+		if len(strings.TrimSpace(item.Arguments)) == 0 {
+			args = json.RawMessage("{}")
+		}
+
+		return RoleAssistant, []ContentBlock{{Type: BlockTypeToolUse, ID: item.CallID, Name: item.Name, Input: args}}, nil
+	case openai.ResponseOutputMessage:
+		blocks := []ContentBlock{}
+		if text := item.Text(); text != "" {
+			blocks = append(blocks, ContentBlock{Type: BlockTypeText, Text: text})
+		}
+
+		return RoleAssistant, blocks, nil
+	case openai.ResponseOutputReasoning:
+		if item.EncryptedContent == "" {
+			return RoleAssistant, nil, nil
+		}
+
+		var sb strings.Builder
+		for _, c := range item.Content {
+			sb.WriteString(c.Text)
+		}
+
+		return RoleAssistant, []ContentBlock{{Type: BlockTypeThinking, Thinking: sb.String(), Signature: item.EncryptedContent}}, nil
+	default:
+		return "", nil, fmt.Errorf("%w: input item %T", ErrUnsupported, item)
+	}
+
+	role := RoleUser
+	if input.Role == openai.RoleAssistant {
+		role = RoleAssistant
+	}
+
+	blocks := make([]ContentBlock, 0, len(input.Content))
+	for _, c := range input.Content {
+		block, err := newContentBlock(c)
+		if err != nil {
+			return "", nil, err
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return role, blocks, nil
+}
+
+// newContentBlock translates the content of a message into a content block.
+func newContentBlock(c openai.ResponseMessageContent) (ContentBlock, error) {
+	switch content := c.Content.(type) {
+	case openai.ResponseMessageContentText:
+		return ContentBlock{Type: BlockTypeText, Text: content.Text}, nil
+	case openai.ResponseMessageContentImage:
+		return ContentBlock{Type: BlockTypeImage, Source: newSource(content.Image.URL, content.Image.FileID, content.Image.MIMEType, content.Image.Base64)}, nil
+	case openai.ResponseMessageContentFile:
+		return ContentBlock{Type: BlockTypeDocument, Title: content.File.Name, Source: newSource(content.File.URL, content.File.FileID, content.File.MIMEType, content.File.Base64)}, nil
+	default:
+		return ContentBlock{}, fmt.Errorf("%w: content %T", ErrUnsupported, content)
+	}
+}
+
+// newSource returns the source of an image or document.
+func newSource(url, fileID, mediaType, data string) *Source {
+	switch {
+	case data != "":
+		return &Source{Type: "base64", MediaType: mediaType, Data: data}
+	case fileID != "":
+		return &Source{Type: "file", FileID: fileID}
+	default:
+		return &Source{Type: "url", URL: url}
+	}
+}
+
+// newTool translates a function tool into a Messages API tool.
+func newTool(t ResponseTool) (Tool, error) {
+	fn, ok := t.Tool.(openai.ResponseFunctionTool)
+	if !ok {
+		return Tool{}, fmt.Errorf("%w: tool %T", ErrUnsupported, t.Tool)
+	}
+
+	schema, err := json.Marshal(fn.Function.Parameters)
+	if err != nil {
+		return Tool{}, err
+	}
+
+	return Tool{
+		Name:        fn.Function.Name,
+		Description: fn.Function.Description,
+		InputSchema: schema,
+	}, nil
+}
+
+// NewResponse maps a Messages API message into a response. Consecutive text
+// blocks become a message, tool_use blocks become function calls and
+// thinking blocks become reasoning items.
+func NewResponse(msg *MessageResponse) *Response {
+	res := &Response{
+		ID:     msg.ID,
+		Object: "response",
+		Model:  msg.Model,
+		Status: openai.ResponseStatusCompleted,
+		Usage: &openai.ResponseUsage{
+			InputTokens:        msg.Usage.InputTokens + msg.Usage.CacheCreationInputTokens + msg.Usage.CacheReadInputTokens,
+			InputTokensDetails: openai.ResponseInputTokensDetails{CachedTokens: msg.Usage.CacheReadInputTokens},
+			OutputTokens:       msg.Usage.OutputTokens,
+		},
+	}
+	res.Usage.TotalTokens = res.Usage.InputTokens + res.Usage.OutputTokens
+
+	var message *openai.ResponseOutputMessage
+
+	flush := func() {
+		if message != nil {
+			res.Output = append(res.Output, openai.ResponseOutput{Output: *message})
+			message = nil
+		}
+	}
+
+	for _, block := range msg.Content {
+		switch block.Type {
+		case BlockTypeText:
+			if message == nil {
+				message = &openai.ResponseOutputMessage{Role: openai.RoleAssistant, Status: openai.ResponseStatusCompleted}
+			}
+
+			message.ResponseOutputMessageContent = append(message.ResponseOutputMessageContent, openai.ResponseOutputMessageContent{
+				Content: openai.ResponseOutputMessageContentText{Text: block.Text},
+			})
+		case BlockTypeToolUse:
+			flush()
+			res.Output = append(res.Output, openai.ResponseOutput{Output: openai.ResponseOutputFunctionCall{
+				ID:        block.ID,
+				CallID:    block.ID,
+				Status:    openai.ResponseStatusCompleted,
+				Name:      block.Name,
+				Arguments: string(block.Input),
+			}})
+		case BlockTypeThinking:
+			flush()
+			res.Output = append(res.Output, openai.ResponseOutput{Output: openai.ResponseOutputReasoning{
+				Summary:          []openai.ResponseOutputReasoningText{},
+				Content:          []openai.ResponseOutputReasoningText{{Type: "reasoning_text", Text: block.Thinking}},
+				EncryptedContent: block.Signature,
+			}})
+		}
+	}
+
+	if msg.StopReason == StopReasonRefusal {
+		if message == nil {
+			message = &openai.ResponseOutputMessage{Role: openai.RoleAssistant, Status: openai.ResponseStatusCompleted}
+		}
+
+		message.ResponseOutputMessageContent = append(message.ResponseOutputMessageContent, openai.ResponseOutputMessageContent{
+			Content: openai.ResponseOutputMessageContentRefusal{Refusal: "The model refused to respond."},
+		})
+	}
+
+	flush()
+
+	if msg.StopReason == StopReasonMaxTokens {
+		res.Status = openai.ResponseStatusIncomplete
+		res.IncompleteDetails = &openai.ResponseIncompleteDetails{Reason: openai.IncompleteReasonMaxOutputTokens}
+	}
+
+	return res
+}
+
+func withTrailingSlash(u string) string {
+	if strings.HasSuffix(u, "/") {
+		return u
+	}
+
+	return u + "/"
+}
@@ -0,0 +1,100 @@
+package anthropic_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/anthropic"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageRequest(t *testing.T) {
+	req := openai.NewResponseRequest(
+		openai.WithInstructions("Be brief."),
+		openai.WithInput(
+			openai.NewInputMessage(openai.RoleSystem, "Answer in English."),
+			openai.NewInputMessage(openai.RoleUser, "Weather in Berlin and Paris?"),
+			openai.ResponseInput{Item: openai.ResponseOutputFunctionCall{CallID: "toolu_1", Name: "weather", Arguments: `{"city":"Berlin"}`}},
+			openai.ResponseInput{Item: openai.ResponseOutputFunctionCall{CallID: "toolu_2", Name: "weather", Arguments: `{"city":"Paris"}`}},
+			openai.NewFunctionCallOutput("toolu_1", "sunny"),
+			openai.NewFunctionCallOutput("toolu_2", "rainy"),
+		),
+		openai.WithTools(openai.ResponseTool{Tool: openai.ResponseFunctionTool{
+			Function: openai.ResponseFunctionDefinition{Name: "weather", Description: "Get the weather."},
+		}}),
+	)
+	req.Model = anthropic.DefaultModel
+	req.ToolChoice = openai.ToolChoiceRequired
+	req.MaxTokens = cast.Ptr(1024)
+
+	body, err := anthropic.NewMessageRequest(req)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"model": "claude-opus-4-6",
+		"max_tokens": 1024,
+		"system": "Be brief.\n\nAnswer in English.",
+		"messages": [
+			{"role": "user", "content": [{"type": "text", "text": "Weather in Berlin and Paris?"}]},
+			{"role": "assistant", "content": [
+				{"type": "tool_use", "id": "toolu_1", "name": "weather", "input": {"city": "Berlin"}},
+				{"type": "tool_use", "id": "toolu_2", "name": "weather", "input": {"city": "Paris"}}
+			]},
+			{"role": "user", "content": [
+				{"type": "tool_result", "tool_use_id": "toolu_1", "content": "sunny"},
+				{"type": "tool_result", "tool_use_id": "toolu_2", "content": "rainy"}
+			]}
+		],
+		"tools": [{"name": "weather", "description": "Get the weather.", "input_schema": {"type": "object"}}],
+		"tool_choice": {"type": "any"}
+	}`, string(b))
+}
+
+func TestRespond(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/messages", r.URL.Path)
+		require.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		require.Equal(t, anthropic.DefaultVersion, r.Header.Get("Anthropic-Version"))
+
+		body := &anthropic.MessageRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(body))
+		require.Equal(t, anthropic.DefaultMaxTokens, body.MaxTokens)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "msg_1",
+			"type": "message",
+			"role": "assistant",
+			"model": "claude-opus-4-6",
+			"content": [
+				{"type": "text", "text": "Let me check."},
+				{"type": "tool_use", "id": "toolu_1", "name": "weather", "input": {"city": "Berlin"}}
+			],
+			"stop_reason": "tool_use",
+			"usage": {"input_tokens": 10, "output_tokens": 5, "cache_read_input_tokens": 20}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := anthropic.New(prompts.NewClient(), anthropic.WithBaseURL(srv.URL+"/v1"), anthropic.WithAPIKey("secret"))
+
+	res, err := p.Respond(context.Background(), openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "Weather?"))))
+	require.NoError(t, err)
+
+	require.Equal(t, "msg_1", res.ID)
+	require.Equal(t, "Let me check.", res.OutputText())
+	require.Equal(t, []openai.ResponseOutputFunctionCall{
+		{ID: "toolu_1", CallID: "toolu_1", Status: openai.ResponseStatusCompleted, Name: "weather", Arguments: `{"city": "Berlin"}`},
+	}, res.FunctionCalls())
+	require.Equal(t, 30, res.Usage.InputTokens)
+	require.Equal(t, 20, res.Usage.InputTokensDetails.CachedTokens)
+	require.Equal(t, 35, res.Usage.TotalTokens)
+}
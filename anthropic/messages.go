@@ -0,0 +1,176 @@
+package anthropic
+
+import "encoding/json"
+
+// Available content block types.
+const (
+	// BlockTypeText is the type of a text block.
+	BlockTypeText = "text"
+	// BlockTypeImage is the type of an image block.
+	BlockTypeImage = "image"
+	// BlockTypeDocument is the type of a document block.
+	BlockTypeDocument = "document"
+	// BlockTypeToolUse is the type of a tool use block.
+	BlockTypeToolUse = "tool_use"
+	// BlockTypeToolResult is the type of a tool result block.
+	BlockTypeToolResult = "tool_result"
+	// BlockTypeThinking is the type of a thinking block.
+	BlockTypeThinking = "thinking"
+	// BlockTypeRedactedThinking is the type of a redacted thinking block.
+	BlockTypeRedactedThinking = "redacted_thinking"
+)
+
+// Role is the role of a message in the Messages API.
+type Role string
+
+// Available roles.
+const (
+	// RoleUser is the user role.
+	RoleUser Role = "user"
+	// RoleAssistant is the assistant role.
+	RoleAssistant Role = "assistant"
+)
+
+// StopReason is the reason why the model stopped generating.
+type StopReason string
+
+const (
+	// StopReasonEndTurn indicates that the model reached a natural stopping point.
+	StopReasonEndTurn StopReason = "end_turn"
+	// StopReasonMaxTokens indicates that the maximum number of tokens was reached.
+	StopReasonMaxTokens StopReason = "max_tokens"
+	// StopReasonStopSequence indicates that a stop sequence was generated.
+	StopReasonStopSequence StopReason = "stop_sequence"
+	// StopReasonToolUse indicates that the model wants to use a tool.
+	StopReasonToolUse StopReason = "tool_use"
+	// StopReasonPauseTurn indicates that a long running turn was paused.
+	StopReasonPauseTurn StopReason = "pause_turn"
+	// StopReasonRefusal indicates that the model refused to respond.
+	StopReasonRefusal StopReason = "refusal"
+)
+
+// MessageRequest is the request of the Messages API.
+type MessageRequest struct {
+	// Model is the model to use.
+	Model string `json:"model"`
+	// MaxTokens is the maximum number of tokens to generate.
+	MaxTokens int `json:"max_tokens"`
+	// System is the system prompt.
+	System string `json:"system,omitempty"`
+	// Messages is the list of messages of the conversation.
+	Messages []Message `json:"messages"`
+	// Tools is the list of tools the model may use.
+	Tools []Tool `json:"tools,omitempty"`
+	// ToolChoice is how the model should use the tools.
+	ToolChoice *ToolChoice `json:"tool_choice,omitempty"`
+	// Temperature is the sampling temperature.
+	Temperature *float32 `json:"temperature,omitempty"`
+	// TopP is the nucleus sampling parameter.
+	TopP *float64 `json:"top_p,omitempty"`
+	// TopK is the number of top tokens to sample from.
+	TopK *int `json:"top_k,omitempty"`
+	// Stream is a flag to enable streaming.
+	Stream bool `json:"stream,omitempty"`
+}
+
+// Message is a message of the conversation.
+type Message struct {
+	// Role is the role of the message.
+	Role Role `json:"role"`
+	// Content is the list of content blocks of the message.
+	Content []ContentBlock `json:"content"`
+}
+
+// ContentBlock is a content block of a message.
+type ContentBlock struct {
+	// Type is the type of the content block.
+	Type string `json:"type"`
+	// Text is the text of a text block.
+	Text string `json:"text,omitempty"`
+	// Source is the source of an image or document block.
+	Source *Source `json:"source,omitempty"`
+	// Title is the title of a document block.
+	Title string `json:"title,omitempty"`
+	// ID is the unique identifier of a tool use block.
+	ID string `json:"id,omitempty"`
+	// Name is the name of the tool of a tool use block.
+	Name string `json:"name,omitempty"`
+	// Input is the input of a tool use block.
+	Input json.RawMessage `json:"input,omitempty"`
+	// ToolUseID is the identifier of the tool use of a tool result block.
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	// Content is the content of a tool result block.
+	Content string `json:"content,omitempty"`
+	// IsError is a flag to indicate that a tool result is an error.
+	IsError bool `json:"is_error,omitempty"`
+	// Thinking is the text of a thinking block.
+	Thinking string `json:"thinking,omitempty"`
+	// Signature is the signature of a thinking block.
+	Signature string `json:"signature,omitempty"`
+	// Data is the encrypted data of a redacted thinking block.
+	Data string `json:"data,omitempty"`
+}
+
+// Source is the source of an image or document block.
+type Source struct {
+	// Type is the type of the source (e.g. "base64", "url" or "file").
+	Type string `json:"type"`
+	// MediaType is the media type of base64 encoded data.
+	MediaType string `json:"media_type,omitempty"`
+	// Data is the base64 encoded data.
+	Data string `json:"data,omitempty"`
+	// URL is the URL of the source.
+	URL string `json:"url,omitempty"`
+	// FileID is the ID of an uploaded file.
+	FileID string `json:"file_id,omitempty"`
+}
+
+// Tool is a tool the model may use.
+type Tool struct {
+	// Name is the name of the tool.
+	Name string `json:"name"`
+	// Description is the description of the tool.
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON Schema of the tool input.
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// ToolChoice is how the model should use the tools.
+type ToolChoice struct {
+	// Type is the type of the tool choice ("auto", "any", "tool" or "none").
+	Type string `json:"type"`
+	// Name is the name of the tool to use if the type is "tool".
+	Name string `json:"name,omitempty"`
+}
+
+// MessageResponse is the response of the Messages API.
+type MessageResponse struct {
+	// ID is the unique identifier of the message.
+	ID string `json:"id"`
+	// Type is the type of the object.
+	Type string `json:"type"`
+	// Role is the role of the message.
+	Role Role `json:"role"`
+	// Model is the model that generated the message.
+	Model string `json:"model"`
+	// Content is the list of content blocks generated by the model.
+	Content []ContentBlock `json:"content"`
+	// StopReason is the reason why the model stopped generating.
+	StopReason StopReason `json:"stop_reason"`
+	// StopSequence is the stop sequence that was generated.
+	StopSequence string `json:"stop_sequence,omitempty"`
+	// Usage is the token usage of the message.
+	Usage Usage `json:"usage"`
+}
+
+// Usage is the token usage of a message.
+type Usage struct {
+	// InputTokens is the number of uncached input tokens.
+	InputTokens int `json:"input_tokens"`
+	// OutputTokens is the number of output tokens.
+	OutputTokens int `json:"output_tokens"`
+	// CacheCreationInputTokens is the number of input tokens written to the cache.
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	// CacheReadInputTokens is the number of input tokens read from the cache.
+	CacheReadInputTokens int `json:"cache_read_input_tokens,omitempty"`
+}
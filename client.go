@@ -3,6 +3,7 @@ package prompts
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
@@ -186,6 +187,13 @@ func (s *Client) Set(key, value string) *Client {
 	return s
 }
 
+// Del deletes the values associated with key from Headers.
+// Header keys are canonicalized.
+func (s *Client) Del(key string) *Client {
+	s.header.Del(key)
+	return s
+}
+
 // APIKey sets the Authorization header to use the provided API key with the Bearer scheme.
 func (s *Client) APIKey(apiKey string) *Client {
 	return s.Set("Authorization", "Bearer "+apiKey)
@@ -365,6 +373,7 @@ func (s *Client) ResponseDecoder(decoder ResponseDecoder) *Client {
 
 // ReceiveSuccess creates a new HTTP request and returns the response. Success
 // responses (2XX) are JSON decoded into the value pointed to by successV.
+// Other responses are returned as a *PromptError.
 // Any error creating the request, sending it, or decoding a 2XX response
 // is returned.
 func (s *Client) ReceiveSuccess(ctx context.Context, successV interface{}) (*http.Response, error) {
@@ -373,7 +382,8 @@ func (s *Client) ReceiveSuccess(ctx context.Context, successV interface{}) (*htt
 
 // Receive creates a new HTTP request and returns the response. Success
 // responses (2XX) are JSON decoded into the value pointed to by successV and
-// other responses are JSON decoded into the value pointed to by failureV.
+// other responses are JSON decoded into the value pointed to by failureV
+// and returned as a *PromptError.
 // If the status code of response is 204(no content) or the Content-Length is 0,
 // decoding is skipped. Any error creating the request, sending it, or decoding
 // the response is returned.
@@ -389,7 +399,8 @@ func (s *Client) Receive(ctx context.Context, successV, failureV interface{}) (*
 
 // Do sends an HTTP request and returns the response. Success responses (2XX)
 // are JSON decoded into the value pointed to by successV and other responses
-// are JSON decoded into the value pointed to by failureV.
+// are JSON decoded into the value pointed to by failureV and returned as
+// a *PromptError.
 // If the status code of response is 204(no content) or the Content-Length is 0,
 // decoding is skipped. Any error sending the request or decoding the response
 // is returned.
@@ -408,33 +419,40 @@ func (s *Client) Do(req *http.Request, successV, failureV interface{}) (*http.Re
 	// See: https://golang.org/pkg/net/http/#Response
 	defer io.Copy(io.Discard, resp.Body)
 
+	if !isSuccess(resp.StatusCode) {
+		return resp, decodeError(resp, s.responseDecoder, failureV)
+	}
+
 	// Don't try to decode on 204s or Content-Length is 0
 	if resp.StatusCode == http.StatusNoContent || resp.ContentLength == 0 {
 		return resp, nil
 	}
 
 	// Decode from json
-	if successV != nil || failureV != nil {
-		err = decodeResponse(resp, s.responseDecoder, successV, failureV)
+	if successV != nil {
+		err = s.responseDecoder.Decode(resp, successV)
 	}
 
 	return resp, err
 }
 
-// decodeResponse decodes response Body into the value pointed to by successV
-// if the response is a success (2XX) or into the value pointed to by failureV
-// otherwise. If the successV or failureV argument to decode into is nil,
-// decoding is skipped.
+// isSuccess returns true if the status code is a success (2XX).
+func isSuccess(code int) bool {
+	return 200 <= code && code <= 299
+}
+
+// decodeError decodes a non-2XX response Body into a *PromptError and into
+// the value pointed to by failureV. If the failureV argument is nil,
+// decoding into it is skipped.
 // Caller is responsible for closing the resp.Body.
-func decodeResponse(resp *http.Response, decoder ResponseDecoder, successV, failureV interface{}) error {
-	if code := resp.StatusCode; 200 <= code && code <= 299 {
-		if successV != nil {
-			return decoder.Decode(resp, successV)
-		}
-	} else {
-		if failureV != nil {
-			return decoder.Decode(resp, failureV)
+func decodeError(resp *http.Response, decoder ResponseDecoder, failureV interface{}) error {
+	perr := NewPromptError(resp)
+
+	if failureV != nil && len(perr.Body) > 0 {
+		if err := decoder.Decode(resp, failureV); err != nil {
+			return errors.Join(perr, err)
 		}
 	}
-	return nil
+
+	return perr
 }
@@ -0,0 +1,183 @@
+package compat
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+type (
+	ChatCompletionRequest  = openai.ChatCompletionRequest
+	ChatCompletionResponse = openai.ChatCompletionResponse
+	ChatCompletionChunk    = openai.ChatCompletionChunk
+)
+
+// finishReasonFunctionCall is the finish reason of the functions format.
+const finishReasonFunctionCall openai.FinishReason = "function_call"
+
+// Chat is a struct that implements the Prompter interface for the Chat
+// Completions API of an OpenAI-compatible server.
+type Chat[I *ChatCompletionRequest, O *ChatCompletionResponse] struct {
+	client *prompts.Client
+	quirks Quirks
+	name   string
+}
+
+var _ prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] = (*Chat[*ChatCompletionRequest, *ChatCompletionResponse])(nil)
+
+// NewChatCompletions creates a new Chat with the given client.
+//
+//	prompt := compat.NewChatCompletions(client, compat.WithProfile(compat.LlamaCpp()))
+func NewChatCompletions(client *prompts.Client, opts ...Opt) prompts.Prompter[*ChatCompletionRequest, *ChatCompletionResponse] {
+	return newChat(client, opts...)
+}
+
+// NewChatCompletionsStreamer creates a new Chat with the given client that streams chat completions.
+func NewChatCompletionsStreamer(client *prompts.Client, opts ...Opt) prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] {
+	return newChat(client, opts...)
+}
+
+func newChat(client *prompts.Client, opts ...Opt) *Chat[*ChatCompletionRequest, *ChatCompletionResponse] {
+	o := newOpts(opts...)
+
+	return &Chat[*ChatCompletionRequest, *ChatCompletionResponse]{client: o.base(client), quirks: o.Quirks, name: o.Name}
+}
+
+// ProviderName returns the name of the server. It is empty unless it is set
+// with WithName or WithProfile.
+func (p *Chat[I, O]) ProviderName() string {
+	return p.name
+}
+
+// Respond sends a chat completion request and returns the response.
+func (p *Chat[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	return p.respond(ctx, req, nil)
+}
+
+// respond sends a chat completion request with the top_k parameter of a translated response request.
+func (p *Chat[I, O]) respond(ctx context.Context, req *ChatCompletionRequest, topK *int) (*ChatCompletionResponse, error) {
+	r := *req
+	r.Stream = false
+	r.StreamOptions = nil
+
+	body, err := p.quirks.encode(&r, "messages", topK)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw json.RawMessage
+
+	_, err = p.client.New().Post("chat/completions").BodyJSON(body).ReceiveSuccess(ctx, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ChatCompletionResponse{}
+	if err := json.Unmarshal(raw, res); err != nil {
+		return nil, err
+	}
+
+	if p.quirks.ToolCalls == ToolCallFormatFunctions {
+		if err := legacyResponse(raw, res); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// Stream sends a chat completion request and returns the stream of chunks.
+func (p *Chat[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ChatCompletionChunk, error] {
+	return p.stream(ctx, req, nil)
+}
+
+// stream sends a chat completion request with the top_k parameter of a translated response request.
+func (p *Chat[I, O]) stream(ctx context.Context, req *ChatCompletionRequest, topK *int) iter.Seq2[*ChatCompletionChunk, error] {
+	return func(yield func(*ChatCompletionChunk, error) bool) {
+		r := *req
+		r.Stream = true
+
+		body, err := p.quirks.encode(&r, "messages", topK)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		c := p.client.New().Post("chat/completions").BodyJSON(body)
+
+		var decode func([]byte, *ChatCompletionChunk) error
+		if p.quirks.ToolCalls == ToolCallFormatFunctions {
+			decode = legacyChunk
+		}
+
+		for chunk, err := range openai.DecodeStream(prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder()), decode) {
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// legacyResponse adds the function calls of a response in the functions format as tool calls.
+func legacyResponse(raw json.RawMessage, res *ChatCompletionResponse) error {
+	legacy := legacyChoices{}
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return err
+	}
+
+	for i, c := range legacy.Choices {
+		if i >= len(res.Choices) {
+			break
+		}
+
+		if res.Choices[i].FinishReason == finishReasonFunctionCall {
+			res.Choices[i].FinishReason = openai.FinishReasonToolCalls
+		}
+
+		if fn := c.Message.FunctionCall; fn != nil {
+			res.Choices[i].Message.ToolCalls = append(res.Choices[i].Message.ToolCalls, openai.ChatCompletionMessageToolCall{
+				ToolCall: openai.ChatCompletionMessageFunctionToolCall{ID: legacyFunctionCall(c.Index), Type: "function", Function: *fn},
+			})
+		}
+	}
+
+	return nil
+}
+
+// legacyChunk adds the function call deltas of a chunk in the functions format as tool call deltas.
+func legacyChunk(raw []byte, chunk *ChatCompletionChunk) error {
+	legacy := legacyChoices{}
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return err
+	}
+
+	for i, c := range legacy.Choices {
+		if i >= len(chunk.Choices) {
+			break
+		}
+
+		if chunk.Choices[i].FinishReason == finishReasonFunctionCall {
+			chunk.Choices[i].FinishReason = openai.FinishReasonToolCalls
+		}
+
+		fn := c.Delta.FunctionCall
+		if fn == nil {
+			continue
+		}
+
+		call := openai.ChatCompletionChunkToolCall{Function: *fn}
+
+		// the name is only sent with the first delta of a call.
+		if fn.Name != "" {
+			call.ID = legacyFunctionCall(c.Index)
+			call.Type = "function"
+		}
+
+		chunk.Choices[i].Delta.ToolCalls = append(chunk.Choices[i].Delta.ToolCalls, call)
+	}
+
+	return nil
+}
@@ -0,0 +1,471 @@
+// Package compat implements a Prompter for OpenAI-compatible servers
+// (e.g. vLLM, LM Studio, llama.cpp, LocalAI, Together, Groq or OpenRouter).
+// The quirks of a server describe how it deviates from the OpenAI API, e.g.
+// whether it implements the /responses endpoint or which parameters it rejects.
+package compat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+type (
+	ResponseRequest     = openai.ResponseRequest
+	Response            = openai.Response
+	ResponseInput       = openai.ResponseInput
+	ResponseTool        = openai.ResponseTool
+	ResponseStreamEvent = openai.ResponseStreamEvent
+)
+
+// ErrUnsupported is returned when a request can not be translated to the Chat Completions API.
+var ErrUnsupported = errors.New("compat: unsupported")
+
+// Opts are the options of the compat provider.
+type Opts struct {
+	// BaseURL is the endpoint of the server. If empty, the base URL of the client is used.
+	BaseURL string
+	// Quirks are the quirks of the server.
+	Quirks Quirks
+	// Name is the name of the server reported to middleware (e.g. "vllm").
+	Name string
+}
+
+// Opt is a function type for configuring the compat provider.
+type Opt func(*Opts)
+
+// WithBaseURL sets the endpoint of the server.
+func WithBaseURL(u string) Opt {
+	return func(o *Opts) {
+		o.BaseURL = u
+	}
+}
+
+// WithQuirks sets the quirks of the server.
+func WithQuirks(q Quirks) Opt {
+	return func(o *Opts) {
+		o.Quirks = q
+	}
+}
+
+// WithName sets the name of the server reported to middleware.
+func WithName(name string) Opt {
+	return func(o *Opts) {
+		o.Name = name
+	}
+}
+
+// WithProfile sets the endpoint, the quirks and the name of a known server.
+// The endpoint can be overridden by a later WithBaseURL.
+func WithProfile(p Profile) Opt {
+	return func(o *Opts) {
+		o.BaseURL = p.BaseURL
+		o.Quirks = p.Quirks
+		o.Name = p.Name
+	}
+}
+
+func newOpts(opts ...Opt) *Opts {
+	o := &Opts{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// base returns a copy of the client with the endpoint of the server.
+func (o *Opts) base(client *prompts.Client) *prompts.Client {
+	if o.BaseURL == "" {
+		return client.New()
+	}
+
+	u := o.BaseURL
+	if !strings.HasSuffix(u, "/") {
+		u += "/"
+	}
+
+	return client.New().Base(u)
+}
+
+// Compat is a struct that implements the Prompter interface for an
+// OpenAI-compatible server. Requests are sent to the /responses endpoint if
+// the server implements it, otherwise they are translated to the Chat Completions API.
+type Compat[I *ResponseRequest, O *Response] struct {
+	client *prompts.Client
+	quirks Quirks
+	chat   *Chat[*ChatCompletionRequest, *ChatCompletionResponse]
+}
+
+var _ prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] = (*Compat[*ResponseRequest, *Response])(nil)
+
+// New creates a new Compat with the given client.
+//
+//	prompt := compat.New(prompts.NewClient().APIKey(key), compat.WithProfile(compat.VLLM()), compat.WithBaseURL("https://llm.internal/v1/"))
+func New(client *prompts.Client, opts ...Opt) prompts.Prompter[*ResponseRequest, *Response] {
+	return newCompat(client, opts...)
+}
+
+// NewStreamer creates a new Compat with the given client that streams responses.
+func NewStreamer(client *prompts.Client, opts ...Opt) prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] {
+	return newCompat(client, opts...)
+}
+
+func newCompat(client *prompts.Client, opts ...Opt) *Compat[*ResponseRequest, *Response] {
+	o := newOpts(opts...)
+	base := o.base(client)
+
+	return &Compat[*ResponseRequest, *Response]{
+		client: base,
+		quirks: o.Quirks,
+		chat:   &Chat[*ChatCompletionRequest, *ChatCompletionResponse]{client: base, quirks: o.Quirks, name: o.Name},
+	}
+}
+
+// ProviderName returns the name of the server. It is empty unless it is set
+// with WithName or WithProfile.
+func (p *Compat[I, O]) ProviderName() string {
+	return p.chat.name
+}
+
+// Respond sends a response request and returns the response.
+func (p *Compat[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	if !p.quirks.Responses {
+		body, err := NewChatCompletionRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := p.chat.respond(ctx, body, (*ResponseRequest)(req).TopK)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewResponse(res), nil
+	}
+
+	r := *req
+	r.Stream = false
+
+	body, err := p.quirks.encode(&r, "input", r.TopK)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Response{}
+
+	_, err = p.client.New().Post("responses").BodyJSON(body).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Stream sends a response request and returns the stream of events. For
+// servers without the /responses endpoint the chunks of the chat completion
+// are translated into reasoning and text deltas followed by the completed response.
+func (p *Compat[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ResponseStreamEvent, error] {
+	if p.quirks.Responses {
+		return func(yield func(*ResponseStreamEvent, error) bool) {
+			r := *req
+			r.Stream = true
+
+			body, err := p.quirks.encode(&r, "input", r.TopK)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			c := p.client.New().Post("responses").BodyJSON(body)
+
+			for e, err := range openai.DecodeResponseStream(prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder())) {
+				if !yield(e, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	return func(yield func(*ResponseStreamEvent, error) bool) {
+		body, err := NewChatCompletionRequest(req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		body.StreamOptions = &openai.ChatCompletionStreamOptions{IncludeUsage: true}
+
+		var b openai.ResponseStreamBuilder
+
+		if !yield(b.Created(body.Model), nil) {
+			return
+		}
+
+		var stopped bool
+
+		chunks := func(yieldChunk func(*ChatCompletionChunk, error) bool) {
+			for chunk, err := range p.chat.stream(ctx, body, (*ResponseRequest)(req).TopK) {
+				for _, c := range firstChoices(chunk, err) {
+					if c.Delta.ReasoningContent != "" && !yield(b.ReasoningDelta(c.Delta.ReasoningContent), nil) {
+						stopped = true
+						return
+					}
+
+					if c.Delta.Content != "" && !yield(b.TextDelta(c.Delta.Content), nil) {
+						stopped = true
+						return
+					}
+				}
+
+				if !yieldChunk(chunk, err) {
+					return
+				}
+			}
+		}
+
+		acc, err := openai.AccumulateChatCompletion(chunks)
+		if stopped {
+			return
+		}
+
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		yield(b.Done(NewResponse(acc)), nil)
+	}
+}
+
+// firstChoices returns the deltas of the first choice of a chunk.
+func firstChoices(chunk *ChatCompletionChunk, err error) []openai.ChatCompletionChunkChoice {
+	if err != nil || chunk == nil {
+		return nil
+	}
+
+	choices := make([]openai.ChatCompletionChunkChoice, 0, 1)
+	for _, c := range chunk.Choices {
+		if c.Index == 0 {
+			choices = append(choices, c)
+		}
+	}
+
+	return choices
+}
+
+// NewChatCompletionRequest translates a response request into a chat completion
+// request. Instructions become a system message, function calls are merged
+// into the tool calls of an assistant message and their outputs become tool messages.
+// The state of the Responses API (previous responses, conversations, stored
+// and background responses) can not be translated and returns ErrUnsupported.
+func NewChatCompletionRequest(req *ResponseRequest) (*ChatCompletionRequest, error) {
+	switch {
+	case req.PreviousResponseID != "":
+		return nil, fmt.Errorf("%w: previous response ID", ErrUnsupported)
+	case req.Conversation != "":
+		return nil, fmt.Errorf("%w: conversation", ErrUnsupported)
+	case req.Store != nil && *req.Store:
+		return nil, fmt.Errorf("%w: store", ErrUnsupported)
+	case req.Background:
+		return nil, fmt.Errorf("%w: background", ErrUnsupported)
+	}
+
+	body := &ChatCompletionRequest{
+		Model:               req.Model,
+		ToolChoice:          req.ToolChoice,
+		MaxCompletionTokens: req.MaxTokens,
+		Temperature:         req.Temperature,
+		TopP:                req.TopP,
+	}
+
+	if req.Instructions != "" {
+		body.Messages = append(body.Messages, openai.NewChatCompletionMessage(openai.RoleSystem, req.Instructions))
+	}
+
+	for _, input := range req.Input {
+		msg, err := newMessage(input)
+		if err != nil {
+			return nil, err
+		}
+
+		if msg == nil {
+			continue
+		}
+
+		// the function calls of a response follow the message of the
+		// response and are merged into a single assistant message.
+		if n := len(body.Messages); n > 0 && len(msg.ToolCalls) > 0 && body.Messages[n-1].Role == openai.RoleAssistant {
+			body.Messages[n-1].ToolCalls = append(body.Messages[n-1].ToolCalls, msg.ToolCalls...)
+			continue
+		}
+
+		body.Messages = append(body.Messages, *msg)
+	}
+
+	for _, t := range req.Tools {
+		fn, ok := t.Tool.(openai.ResponseFunctionTool)
+		if !ok {
+			return nil, fmt.Errorf("%w: tool %T", ErrUnsupported, t.Tool)
+		}
+
+		body.Tools = append(body.Tools, openai.NewChatCompletionTool(fn.Function))
+	}
+
+	if req.Text != nil && req.Text.Format != nil && req.Text.Format.Type != openai.ResponseTextFormatText {
+		format := openai.NewChatCompletionResponseFormat(*req.Text.Format)
+		body.ResponseFormat = &format
+	}
+
+	return body, nil
+}
+
+// newMessage translates an input into a chat completion message. It returns nil for inputs without content.
+func newMessage(input ResponseInput) (*openai.ChatCompletionMessage, error) {
+	switch item := input.Item.(type) {
+	case nil:
+	case openai.ResponseInputFunctionCallOutput:
+		return &openai.ChatCompletionMessage{Role: openai.RoleTool, Content: item.Output, ToolCallID: item.CallID}, nil
+	case openai.ResponseOutputFunctionCall:
+		call := openai.ChatCompletionMessageFunctionToolCall{
+			ID:       item.CallID,
+			Type:     "function",
+			Function: openai.ChatCompletionMessageFunction{Name: item.Name, Arguments: item.Arguments},
+		}
+
+		return &openai.ChatCompletionMessage{Role: openai.RoleAssistant, ToolCalls: []openai.ChatCompletionMessageToolCall{{ToolCall: call}}}, nil
+	case openai.ResponseOutputMessage:
+		return &openai.ChatCompletionMessage{Role: openai.RoleAssistant, Content: item.Text()}, nil
+	case openai.ResponseOutputReasoning:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: input item %T", ErrUnsupported, item)
+	}
+
+	msg := &openai.ChatCompletionMessage{Role: input.Role, Name: input.Name}
+
+	parts := make([]openai.ChatCompletionContentPart, 0, len(input.Content))
+	text := true
+
+	for _, c := range input.Content {
+		switch content := c.Content.(type) {
+		case openai.ResponseMessageContentText:
+			parts = append(parts, openai.NewChatCompletionTextPart(content.Text))
+		case openai.ResponseMessageContentImage:
+			parts = append(parts, openai.NewChatCompletionImagePart(content.Image))
+			text = false
+		case openai.ResponseMessageContentFile:
+			file := &openai.ChatCompletionFile{FileID: content.File.FileID, Filename: content.File.Name}
+			if content.File.Base64 != "" {
+				file.FileData = content.File.DataURL()
+			}
+
+			parts = append(parts, openai.ChatCompletionContentPart{Type: "file", File: file})
+			text = false
+		case openai.ResponseMessageContentAudio:
+			parts = append(parts, openai.ChatCompletionContentPart{Type: "input_audio", InputAudio: &content.Audio})
+			text = false
+		default:
+			return nil, fmt.Errorf("%w: content %T", ErrUnsupported, content)
+		}
+	}
+
+	// text only messages are sent as a string, which is understood by all servers.
+	if !text {
+		msg.Parts = parts
+		return msg, nil
+	}
+
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		texts = append(texts, p.Text)
+	}
+	msg.Content = strings.Join(texts, "\n")
+
+	return msg, nil
+}
+
+// NewResponse maps the first choice of a chat completion into a response.
+// The reasoning content becomes a reasoning item, the content and refusal a
+// message and the tool calls function calls.
+func NewResponse(chat *ChatCompletionResponse) *Response {
+	res := &Response{
+		ID:        chat.ID,
+		Object:    "response",
+		CreatedAt: chat.Created,
+		Model:     chat.Model,
+		Status:    openai.ResponseStatusCompleted,
+	}
+
+	if chat.Usage != nil {
+		res.Usage = &openai.ResponseUsage{
+			InputTokens:         chat.Usage.PromptTokens,
+			InputTokensDetails:  chat.Usage.PromptTokensDetails,
+			OutputTokens:        chat.Usage.CompletionTokens,
+			OutputTokensDetails: chat.Usage.CompletionTokensDetails,
+			TotalTokens:         chat.Usage.TotalTokens,
+		}
+	}
+
+	if len(chat.Choices) == 0 {
+		return res
+	}
+
+	choice := chat.Choices[0]
+
+	if choice.Message.ReasoningContent != "" {
+		res.Output = append(res.Output, openai.ResponseOutput{Output: openai.ResponseOutputReasoning{
+			Summary: []openai.ResponseOutputReasoningText{},
+			Content: []openai.ResponseOutputReasoningText{{Type: "reasoning_text", Text: choice.Message.ReasoningContent}},
+		}})
+	}
+
+	message := openai.ResponseOutputMessage{Role: openai.RoleAssistant, Status: openai.ResponseStatusCompleted}
+
+	if choice.Message.Content != "" {
+		message.ResponseOutputMessageContent = append(message.ResponseOutputMessageContent, openai.ResponseOutputMessageContent{
+			Content: openai.ResponseOutputMessageContentText{Text: choice.Message.Content},
+		})
+	}
+
+	if choice.Message.Refusal != "" {
+		message.ResponseOutputMessageContent = append(message.ResponseOutputMessageContent, openai.ResponseOutputMessageContent{
+			Content: openai.ResponseOutputMessageContentRefusal{Refusal: choice.Message.Refusal},
+		})
+	}
+
+	if len(message.ResponseOutputMessageContent) > 0 {
+		res.Output = append(res.Output, openai.ResponseOutput{Output: message})
+	}
+
+	for _, c := range choice.Message.ToolCalls {
+		call, ok := c.GetFunction()
+		if !ok {
+			continue
+		}
+
+		res.Output = append(res.Output, openai.ResponseOutput{Output: openai.ResponseOutputFunctionCall{
+			ID:        call.ID,
+			CallID:    call.ID,
+			Status:    openai.ResponseStatusCompleted,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		}})
+	}
+
+	switch choice.FinishReason {
+	case openai.FinishReasonLength:
+		res.Status = openai.ResponseStatusIncomplete
+		res.IncompleteDetails = &openai.ResponseIncompleteDetails{Reason: openai.IncompleteReasonMaxOutputTokens}
+	case openai.FinishReasonContentFilter:
+		res.Status = openai.ResponseStatusIncomplete
+		res.IncompleteDetails = &openai.ResponseIncompleteDetails{Reason: openai.IncompleteReasonContentFilter}
+	default:
+	}
+
+	return res
+}
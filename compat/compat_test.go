@@ -0,0 +1,273 @@
+package compat_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/compat"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest() *openai.ResponseRequest {
+	req := openai.NewResponseRequest(
+		openai.WithInstructions("Be brief."),
+		openai.WithInput(
+			openai.NewInputMessage(openai.RoleDeveloper, "Use metric units."),
+			openai.NewInputMessage(openai.RoleUser, "Weather in Berlin?"),
+			openai.ResponseInput{Item: openai.ResponseOutputFunctionCall{CallID: "call_1", Name: "weather", Arguments: `{"city":"Berlin"}`}},
+			openai.NewFunctionCallOutput("call_1", "sunny"),
+		),
+		openai.WithTools(openai.ResponseTool{Tool: openai.ResponseFunctionTool{Function: openai.ResponseFunctionDefinition{Name: "weather"}}}),
+	)
+	req.MaxTokens = cast.Ptr(100)
+	req.TopK = cast.Ptr(40)
+	req.ToolChoice = openai.ToolChoiceAuto
+
+	return req
+}
+
+func TestCompat(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []compat.Opt
+		path     string
+		request  string
+		response string
+		expected string
+	}{
+		{
+			name: "chat completions",
+			opts: []compat.Opt{compat.WithProfile(compat.LlamaCpp())},
+			path: "/v1/chat/completions",
+			request: `{
+				"model": "",
+				"messages": [
+					{"role": "system", "content": "Be brief."},
+					{"role": "system", "content": "Use metric units."},
+					{"role": "user", "content": "Weather in Berlin?"},
+					{"role": "assistant", "content": null, "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "weather", "arguments": "{\"city\":\"Berlin\"}"}}]},
+					{"role": "tool", "content": "sunny", "tool_call_id": "call_1"}
+				],
+				"tools": [{"type": "function", "function": {"name": "weather", "parameters": {"type": "object"}}}],
+				"tool_choice": "auto",
+				"max_tokens": 100,
+				"top_k": 40
+			}`,
+			response: `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"It is sunny."},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":4,"total_tokens":14}}`,
+			expected: "It is sunny.",
+		},
+		{
+			name: "functions",
+			opts: []compat.Opt{compat.WithQuirks(compat.Quirks{ToolCalls: compat.ToolCallFormatFunctions})},
+			path: "/v1/chat/completions",
+			request: `{
+				"model": "",
+				"messages": [
+					{"role": "system", "content": "Be brief."},
+					{"role": "developer", "content": "Use metric units."},
+					{"role": "user", "content": "Weather in Berlin?"},
+					{"role": "assistant", "content": null, "function_call": {"name": "weather", "arguments": "{\"city\":\"Berlin\"}"}},
+					{"role": "function", "content": "sunny", "name": "weather"}
+				],
+				"functions": [{"name": "weather", "parameters": {"type": "object"}}],
+				"function_call": "auto",
+				"max_completion_tokens": 100,
+				"top_k": 40
+			}`,
+			response: `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"It is sunny."},"finish_reason":"stop"}]}`,
+			expected: "It is sunny.",
+		},
+		{
+			name: "unsupported top_k",
+			opts: []compat.Opt{compat.WithProfile(compat.Groq())},
+			path: "/v1/chat/completions",
+			request: `{
+				"model": "",
+				"messages": [
+					{"role": "system", "content": "Be brief."},
+					{"role": "system", "content": "Use metric units."},
+					{"role": "user", "content": "Weather in Berlin?"},
+					{"role": "assistant", "content": null, "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "weather", "arguments": "{\"city\":\"Berlin\"}"}}]},
+					{"role": "tool", "content": "sunny", "tool_call_id": "call_1"}
+				],
+				"tools": [{"type": "function", "function": {"name": "weather", "parameters": {"type": "object"}}}],
+				"tool_choice": "auto",
+				"max_completion_tokens": 100
+			}`,
+			response: `{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":"It is sunny."},"finish_reason":"stop"}]}`,
+			expected: "It is sunny.",
+		},
+		{
+			name: "responses",
+			opts: []compat.Opt{compat.WithQuirks(compat.Quirks{Responses: true, Unsupported: []string{"tool_choice"}})},
+			path: "/v1/responses",
+			request: `{
+				"model": "",
+				"instructions": "Be brief.",
+				"input": [
+					{"role": "developer", "content": [{"type": "input_text", "text": "Use metric units."}]},
+					{"role": "user", "content": [{"type": "input_text", "text": "Weather in Berlin?"}]},
+					{"type": "function_call", "call_id": "call_1", "name": "weather", "arguments": "{\"city\":\"Berlin\"}"},
+					{"type": "function_call_output", "call_id": "call_1", "output": "sunny"}
+				],
+				"tools": [{"type": "function", "name": "weather", "parameters": {"type": "object"}}],
+				"max_output_tokens": 100,
+				"top_k": 40
+			}`,
+			response: `{"id":"resp_1","status":"completed","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"It is sunny."}]}]}`,
+			expected: "It is sunny.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tt.path, r.URL.Path)
+
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.request, string(b))
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			opts := append(tt.opts, compat.WithBaseURL(srv.URL+"/v1"))
+
+			res, err := compat.New(prompts.NewClient(), opts...).Respond(context.Background(), newRequest())
+			require.NoError(t, err)
+			require.Equal(t, openai.ResponseStatusCompleted, res.Status)
+
+			msg, ok := res.Output[0].GetMessage()
+			require.True(t, ok)
+			require.Equal(t, tt.expected, msg.Text())
+		})
+	}
+}
+
+func TestCompatFunctionCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":null,"function_call":{"name":"weather","arguments":"{\"city\":\"Berlin\"}"}},"finish_reason":"function_call"}]}`))
+	}))
+	defer srv.Close()
+
+	p := compat.NewChatCompletions(prompts.NewClient().Base(srv.URL+"/"), compat.WithQuirks(compat.Quirks{ToolCalls: compat.ToolCallFormatFunctions}))
+
+	res, err := p.Respond(context.Background(), openai.NewChatCompletionRequest())
+	require.NoError(t, err)
+	require.Equal(t, openai.FinishReasonToolCalls, res.Choices[0].FinishReason)
+
+	calls := res.ToolCalls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "weather", calls[0].Function.Name)
+	require.JSONEq(t, `{"city":"Berlin"}`, calls[0].Function.Arguments)
+}
+
+func TestCompatFunctionCallStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"function_call\":{\"name\":\"weather\",\"arguments\":\"{\\\"city\\\":\"}}}]}\n\n" +
+			"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"function_call\":{\"arguments\":\"\\\"Berlin\\\"}\"}},\"finish_reason\":\"function_call\"}]}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	s := compat.NewChatCompletionsStreamer(prompts.NewClient().Base(srv.URL+"/"), compat.WithQuirks(compat.Quirks{ToolCalls: compat.ToolCallFormatFunctions}))
+
+	res, err := openai.AccumulateChatCompletion(s.Stream(context.Background(), openai.NewChatCompletionRequest()))
+	require.NoError(t, err)
+	require.Equal(t, openai.FinishReasonToolCalls, res.Choices[0].FinishReason)
+
+	calls := res.ToolCalls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "weather", calls[0].Function.Name)
+	require.JSONEq(t, `{"city":"Berlin"}`, calls[0].Function.Arguments)
+}
+
+func TestCompatStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &openai.ChatCompletionRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		require.True(t, req.Stream)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"reasoning_content\":\"Greet.\"}}]}\n\n" +
+			"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+			"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"},\"finish_reason\":\"length\"}]}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	s := compat.NewStreamer(prompts.NewClient(), compat.WithBaseURL(srv.URL))
+
+	types := []openai.ResponseStreamEventType{}
+	var res openai.Response
+
+	for e, err := range s.Stream(context.Background(), openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "Hi")))) {
+		require.NoError(t, err)
+		types = append(types, e.Type)
+
+		if r, ok := e.Event.(openai.ResponseStreamEventResponse); ok {
+			res = r.Response
+		}
+	}
+
+	require.Equal(t, []openai.ResponseStreamEventType{
+		openai.ResponseStreamEventTypeCreated,
+		openai.ResponseStreamEventTypeReasoningTextDelta,
+		openai.ResponseStreamEventTypeOutputTextDelta,
+		openai.ResponseStreamEventTypeOutputTextDelta,
+		openai.ResponseStreamEventTypeIncomplete,
+	}, types)
+	require.True(t, res.Truncated())
+	require.Equal(t, "Hello", res.OutputText())
+}
+
+func TestNewChatCompletionRequestUnsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  openai.RequestOpt
+	}{
+		{name: "previous response", opt: openai.WithPreviousResponseID("resp_1")},
+		{name: "conversation", opt: openai.WithConversation("conv_1")},
+		{name: "store", opt: openai.WithStore(true)},
+		{name: "background", opt: func(req *openai.ResponseRequest) { req.Background = true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compat.NewChatCompletionRequest(openai.NewResponseRequest(tt.opt))
+			require.ErrorIs(t, err, compat.ErrUnsupported)
+		})
+	}
+
+	_, err := compat.NewChatCompletionRequest(openai.NewResponseRequest(openai.WithStore(false)))
+	require.NoError(t, err)
+}
+
+func TestProviderName(t *testing.T) {
+	client := prompts.NewClient()
+
+	require.Equal(t, "vllm", prompts.ProviderName(compat.New(client, compat.WithProfile(compat.VLLM()))))
+	require.Equal(t, "gateway", prompts.ProviderName(compat.NewChatCompletions(client, compat.WithName("gateway"))))
+	require.Empty(t, prompts.ProviderName(compat.New(client)))
+}
+
+func TestProfile(t *testing.T) {
+	p := compat.LlamaCpp()
+	p.Quirks.Roles[openai.RoleDeveloper] = openai.RoleUser
+	p.Quirks.Unsupported = append(p.Quirks.Unsupported, "seed")
+
+	require.Equal(t, openai.RoleSystem, compat.LlamaCpp().Quirks.Roles[openai.RoleDeveloper])
+	require.Equal(t, openai.RoleSystem, compat.Groq().Quirks.Roles[openai.RoleDeveloper])
+	require.Empty(t, compat.LlamaCpp().Quirks.Unsupported)
+}
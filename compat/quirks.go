@@ -0,0 +1,310 @@
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/katallaxie/prompts/openai"
+)
+
+// ToolCallFormat is the format of the tool calls a server understands.
+type ToolCallFormat string
+
+const (
+	// ToolCallFormatTools is the tools and tool_calls format of the OpenAI API.
+	ToolCallFormatTools ToolCallFormat = "tools"
+	// ToolCallFormatFunctions is the deprecated functions and function_call format.
+	ToolCallFormatFunctions ToolCallFormat = "functions"
+	// ToolCallFormatNone is used for servers without tool support. Tools are removed from requests.
+	ToolCallFormatNone ToolCallFormat = "none"
+)
+
+// Quirks describe how a server deviates from the OpenAI API.
+// The zero value describes a server that only implements the Chat Completions API.
+type Quirks struct {
+	// Responses is true if the server implements the /responses endpoint.
+	// Otherwise requests are translated to the Chat Completions API.
+	Responses bool
+	// Unsupported are the JSON names of the request parameters the server
+	// rejects (e.g. "parallel_tool_calls"). They are removed from requests.
+	Unsupported []string
+	// Roles maps roles to the role names the server understands (e.g. developer to system).
+	Roles map[openai.Role]openai.Role
+	// ToolCalls is the format of the tool calls. Defaults to ToolCallFormatTools.
+	ToolCalls ToolCallFormat
+	// LegacyMaxTokens sends max_tokens instead of max_completion_tokens.
+	LegacyMaxTokens bool
+}
+
+// Profile is a known OpenAI-compatible server.
+type Profile struct {
+	// Name is the name of the server.
+	Name string
+	// BaseURL is the default endpoint of the server.
+	BaseURL string
+	// Quirks are the quirks of the server.
+	Quirks Quirks
+}
+
+// developerAsSystem maps the developer role to the system role for servers
+// that predate the developer role.
+func developerAsSystem() map[openai.Role]openai.Role {
+	return map[openai.Role]openai.Role{openai.RoleDeveloper: openai.RoleSystem}
+}
+
+// Known profiles. They are starting points, gateways in front of these servers
+// may need adjusted quirks. Each call returns a new profile that can be changed
+// without affecting other providers.
+
+// VLLM is the OpenAI-compatible server of vLLM.
+func VLLM() Profile {
+	return Profile{
+		Name:    "vllm",
+		BaseURL: "http://localhost:8000/v1/",
+		Quirks:  Quirks{Responses: true},
+	}
+}
+
+// LMStudio is the local server of LM Studio.
+func LMStudio() Profile {
+	return Profile{
+		Name:    "lmstudio",
+		BaseURL: "http://localhost:1234/v1/",
+		Quirks:  Quirks{Responses: true},
+	}
+}
+
+// LlamaCpp is the server of llama.cpp.
+func LlamaCpp() Profile {
+	return Profile{
+		Name:    "llama.cpp",
+		BaseURL: "http://localhost:8080/v1/",
+		Quirks:  Quirks{Roles: developerAsSystem(), LegacyMaxTokens: true},
+	}
+}
+
+// LocalAI is the server of LocalAI.
+func LocalAI() Profile {
+	return Profile{
+		Name:    "localai",
+		BaseURL: "http://localhost:8080/v1/",
+		Quirks: Quirks{
+			Unsupported:     []string{"parallel_tool_calls", "stream_options"},
+			Roles:           developerAsSystem(),
+			LegacyMaxTokens: true,
+		},
+	}
+}
+
+// Together is the API of Together AI.
+func Together() Profile {
+	return Profile{
+		Name:    "together",
+		BaseURL: "https://api.together.xyz/v1/",
+		Quirks:  Quirks{Roles: developerAsSystem(), LegacyMaxTokens: true},
+	}
+}
+
+// Groq is the OpenAI-compatible API of Groq.
+func Groq() Profile {
+	return Profile{
+		Name:    "groq",
+		BaseURL: "https://api.groq.com/openai/v1/",
+		Quirks:  Quirks{Unsupported: []string{"top_k"}, Roles: developerAsSystem()},
+	}
+}
+
+// OpenRouter is the API of OpenRouter.
+func OpenRouter() Profile {
+	return Profile{
+		Name:    "openrouter",
+		BaseURL: "https://openrouter.ai/api/v1/",
+		Quirks:  Quirks{},
+	}
+}
+
+// encode marshals the request body into a JSON object and rewrites it
+// according to the quirks. The messages are the key of the conversation
+// ("messages" or "input"). TopK is not part of the OpenAI API, but most
+// compatible servers sample with it and it is sent as top_k.
+func (q Quirks) encode(body any, messages string, topK *int) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+
+	if topK != nil {
+		obj["top_k"], _ = json.Marshal(*topK)
+	}
+
+	if v, ok := obj["max_completion_tokens"]; ok && q.LegacyMaxTokens {
+		obj["max_tokens"] = v
+		delete(obj, "max_completion_tokens")
+	}
+
+	if msgs, ok := obj[messages]; ok && len(q.Roles) > 0 {
+		obj[messages], err = q.remap(msgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch q.ToolCalls {
+	case ToolCallFormatNone:
+		delete(obj, "tools")
+		delete(obj, "tool_choice")
+		delete(obj, "parallel_tool_calls")
+	case ToolCallFormatFunctions:
+		if messages == "messages" {
+			if err := legacyFunctions(obj); err != nil {
+				return nil, err
+			}
+		}
+	default:
+	}
+
+	for _, p := range q.Unsupported {
+		delete(obj, p)
+	}
+
+	return obj, nil
+}
+
+// remap renames the roles of the messages.
+func (q Quirks) remap(data json.RawMessage) (json.RawMessage, error) {
+	msgs := []map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, err
+	}
+
+	for _, m := range msgs {
+		var role openai.Role
+		if err := json.Unmarshal(m["role"], &role); err != nil {
+			continue
+		}
+
+		if r, ok := q.Roles[role]; ok {
+			m["role"], _ = json.Marshal(r)
+		}
+	}
+
+	return json.Marshal(msgs)
+}
+
+// legacyFunctions rewrites the tools and tool calls of a chat completion
+// request into the functions format. Assistant messages with parallel tool
+// calls are split into one message per call, tool results become function
+// messages.
+func legacyFunctions(obj map[string]json.RawMessage) error {
+	msgs := []map[string]json.RawMessage{}
+	if err := json.Unmarshal(obj["messages"], &msgs); err != nil {
+		return fmt.Errorf("compat: messages: %w", err)
+	}
+
+	names := map[string]string{}
+	out := make([]map[string]json.RawMessage, 0, len(msgs))
+
+	for _, m := range msgs {
+		if raw, ok := m["tool_calls"]; ok {
+			calls := []openai.ChatCompletionMessageToolCall{}
+			if err := json.Unmarshal(raw, &calls); err != nil {
+				return err
+			}
+			delete(m, "tool_calls")
+
+			msg := m
+			for _, c := range calls {
+				fn, ok := c.GetFunction()
+				if !ok {
+					continue
+				}
+				names[fn.ID] = fn.Function.Name
+
+				if msg == nil {
+					msg = map[string]json.RawMessage{"role": m["role"], "content": json.RawMessage("null")}
+				}
+
+				msg["function_call"], _ = json.Marshal(struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				}{fn.Function.Name, fn.Function.Arguments})
+				out = append(out, msg)
+				msg = nil
+			}
+
+			if msg != nil {
+				out = append(out, msg)
+			}
+
+			continue
+		}
+
+		if raw, ok := m["tool_call_id"]; ok {
+			var id string
+			if err := json.Unmarshal(raw, &id); err != nil {
+				return err
+			}
+			delete(m, "tool_call_id")
+
+			m["role"], _ = json.Marshal(openai.RoleFunction)
+			m["name"], _ = json.Marshal(names[id])
+		}
+
+		out = append(out, m)
+	}
+
+	var err error
+	if obj["messages"], err = json.Marshal(out); err != nil {
+		return err
+	}
+
+	if raw, ok := obj["tools"]; ok {
+		tools := []openai.ChatCompletionTool{}
+		if err := json.Unmarshal(raw, &tools); err != nil {
+			return err
+		}
+
+		functions := make([]openai.ResponseFunctionDefinition, 0, len(tools))
+		for _, t := range tools {
+			functions = append(functions, t.Function)
+		}
+
+		obj["functions"], _ = json.Marshal(functions)
+		delete(obj, "tools")
+	}
+
+	if raw, ok := obj["tool_choice"]; ok {
+		var choice openai.ToolChoice
+		if err := json.Unmarshal(raw, &choice); err == nil && (choice == openai.ToolChoiceAuto || choice == openai.ToolChoiceNone) {
+			obj["function_call"] = raw
+		}
+		delete(obj, "tool_choice")
+	}
+
+	delete(obj, "parallel_tool_calls")
+
+	return nil
+}
+
+// legacyFunctionCall returns the ID of the tool call that replaces a function call of the given choice.
+func legacyFunctionCall(index int) string {
+	return fmt.Sprintf("call_%d", index)
+}
+
+// legacyChoices are the function calls of a response or chunk in the functions format.
+type legacyChoices struct {
+	Choices []struct {
+		Index   int `json:"index"`
+		Message struct {
+			FunctionCall *openai.ChatCompletionMessageFunction `json:"function_call"`
+		} `json:"message"`
+		Delta struct {
+			FunctionCall *openai.ChatCompletionMessageFunction `json:"function_call"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
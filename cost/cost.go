@@ -0,0 +1,177 @@
+// Package cost accounts the tokens and the spend of responses. The spend is
+// computed from a price table per provider and model, aggregated per tag
+// (e.g. the tenant in the metadata of a request) and limited by budgets.
+//
+//	ledger := cost.NewLedger()
+//	prompt := cost.NewResponder(perplexity.New(client), prices, ledger,
+//		cost.WithTags("tenant"),
+//		cost.WithBudget("tenant", "acme", 100),
+//	)
+package cost
+
+import (
+	"regexp"
+
+	"github.com/katallaxie/prompts/openai"
+)
+
+// PerMillion is the number of tokens that prices refer to.
+const PerMillion = 1_000_000
+
+// Price is the price of a model per million tokens.
+type Price struct {
+	// Input is the price of input tokens.
+	Input float64 `json:"input"`
+	// CachedInput is the price of input tokens read from the cache (default: Input).
+	CachedInput float64 `json:"cached_input,omitempty"`
+	// Output is the price of output tokens.
+	Output float64 `json:"output"`
+	// Reasoning is the price of reasoning tokens (default: Output).
+	Reasoning float64 `json:"reasoning,omitempty"`
+	// Request is a fixed price per request (e.g. the search fee of Perplexity).
+	Request float64 `json:"request,omitempty"`
+}
+
+// Cost returns the cost of the usage.
+// Cached input tokens and reasoning tokens are part of the input and output tokens.
+func (p Price) Cost(u Usage) float64 {
+	cachedInput := p.CachedInput
+	if cachedInput == 0 {
+		cachedInput = p.Input
+	}
+
+	reasoning := p.Reasoning
+	if reasoning == 0 {
+		reasoning = p.Output
+	}
+
+	cached := min(u.CachedInputTokens, u.InputTokens)
+	reasoned := min(u.ReasoningTokens, u.OutputTokens)
+
+	c := float64(u.InputTokens-cached)*p.Input +
+		float64(cached)*cachedInput +
+		float64(u.OutputTokens-reasoned)*p.Output +
+		float64(reasoned)*reasoning
+
+	return c/PerMillion + p.Request
+}
+
+// PriceTable are the prices of models. The keys are either "provider/model"
+// or "model". Models are matched exactly or as a dated snapshot, so that a
+// price for "gpt-4o" applies to "gpt-4o-2024-08-06" but not to "gpt-4o-mini".
+// Price tables can be loaded from JSON.
+//
+//	prices := cost.PriceTable{
+//		"perplexity/sonar":     {Input: 1, Output: 1, Request: 0.005},
+//		"perplexity/sonar-pro": {Input: 3, Output: 15, Request: 0.006},
+//	}
+type PriceTable map[string]Price
+
+// Lookup returns the price of the model of the provider. Prices for the
+// provider take precedence over prices for the model alone.
+func (t PriceTable) Lookup(provider, model string) (Price, bool) {
+	if provider != "" {
+		if p, ok := t.lookup(provider + "/" + model); ok {
+			return p, true
+		}
+	}
+
+	return t.lookup(model)
+}
+
+// snapshot matches the date suffix of a model snapshot (e.g. "-2024-08-06").
+var snapshot = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}$`)
+
+func (t PriceTable) lookup(model string) (Price, bool) {
+	if p, ok := t[model]; ok {
+		return p, true
+	}
+
+	p, ok := t[snapshot.ReplaceAllString(model, "")]
+
+	return p, ok
+}
+
+// Usage is the token usage of responses.
+type Usage struct {
+	// InputTokens is the number of input tokens.
+	InputTokens int `json:"input_tokens"`
+	// CachedInputTokens is the number of input tokens read from the cache.
+	CachedInputTokens int `json:"cached_input_tokens"`
+	// OutputTokens is the number of output tokens.
+	OutputTokens int `json:"output_tokens"`
+	// ReasoningTokens is the number of output tokens used for reasoning.
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// Add returns the sum of the usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		InputTokens:       u.InputTokens + o.InputTokens,
+		CachedInputTokens: u.CachedInputTokens + o.CachedInputTokens,
+		OutputTokens:      u.OutputTokens + o.OutputTokens,
+		ReasoningTokens:   u.ReasoningTokens + o.ReasoningTokens,
+	}
+}
+
+// NewUsage returns the usage and the model of a response of the Responses or
+// Chat Completions API, of the terminal event of a streamed response or of
+// the last chunk of a streamed chat completion. It returns false if the
+// response carries no usage.
+func NewUsage(res any) (Usage, string, bool) {
+	switch r := res.(type) {
+	case *openai.Response:
+		if r == nil || r.Usage == nil {
+			return Usage{}, "", false
+		}
+
+		return Usage{
+			InputTokens:       r.Usage.InputTokens,
+			CachedInputTokens: r.Usage.InputTokensDetails.CachedTokens,
+			OutputTokens:      r.Usage.OutputTokens,
+			ReasoningTokens:   r.Usage.OutputTokensDetails.ReasoningTokens,
+		}, r.Model, true
+	case *openai.ResponseStreamEvent:
+		if r == nil {
+			return Usage{}, "", false
+		}
+
+		if ev, ok := r.Event.(openai.ResponseStreamEventResponse); ok && ev.Response.Status.Done() {
+			return NewUsage(&ev.Response)
+		}
+
+		return Usage{}, "", false
+	case *openai.ChatCompletionResponse:
+		if r == nil {
+			return Usage{}, "", false
+		}
+
+		return newCompletionUsage(r.Usage, r.Model)
+	case *openai.ChatCompletionChunk:
+		if r == nil {
+			return Usage{}, "", false
+		}
+
+		return newCompletionUsage(r.Usage, r.Model)
+	case openai.ChatCompletionResponseUnwrapper:
+		return NewUsage(r.Unwrap())
+	case openai.ChatCompletionChunkUnwrapper:
+		return NewUsage(r.Unwrap())
+	default:
+		return Usage{}, "", false
+	}
+}
+
+// newCompletionUsage returns the usage of the Chat Completions API.
+func newCompletionUsage(u *openai.CompletionUsage, model string) (Usage, string, bool) {
+	if u == nil {
+		return Usage{}, "", false
+	}
+
+	return Usage{
+		InputTokens:       u.PromptTokens,
+		CachedInputTokens: u.PromptTokensDetails.CachedTokens,
+		OutputTokens:      u.CompletionTokens,
+		ReasoningTokens:   u.CompletionTokensDetails.ReasoningTokens,
+	}, model, true
+}
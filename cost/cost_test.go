@@ -0,0 +1,211 @@
+package cost_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/katallaxie/prompts/cost"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/katallaxie/prompts/perplexity"
+	"github.com/katallaxie/prompts/promptstest"
+	"github.com/stretchr/testify/require"
+)
+
+var prices = cost.PriceTable{
+	"gpt-4o":                 {Input: 2.5, CachedInput: 1.25, Output: 10},
+	"gpt-4o-mini":            {Input: 0.15, Output: 0.6},
+	"perplexity/sonar":       {Input: 1, Output: 1, Request: 0.005},
+	"perplexity/sonar-pro":   {Input: 3, Output: 15, Request: 0.006},
+	"o3":                     {Input: 2, Output: 8, Reasoning: 4},
+	"anthropic/claude-haiku": {Input: 1, Output: 5},
+}
+
+func TestPriceTable_Lookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		model    string
+		want     cost.Price
+		ok       bool
+	}{
+		{name: "model", model: "gpt-4o", want: prices["gpt-4o"], ok: true},
+		{name: "snapshot", model: "gpt-4o-mini-2024-07-18", want: prices["gpt-4o-mini"], ok: true},
+		{name: "mini", model: "o3-mini"},
+		{name: "other model", model: "gpt-4o-audio-preview"},
+		{name: "provider snapshot", provider: "perplexity", model: "sonar-2025-01-01", want: prices["perplexity/sonar"], ok: true},
+		{name: "provider", provider: "perplexity", model: "sonar-pro", want: prices["perplexity/sonar-pro"], ok: true},
+		{name: "other provider", provider: "openai", model: "sonar-pro"},
+		{name: "unknown", model: "llama3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := prices.Lookup(tt.provider, tt.model)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPrice_Cost(t *testing.T) {
+	tests := []struct {
+		name  string
+		price cost.Price
+		usage cost.Usage
+		want  float64
+	}{
+		{
+			name:  "input and output",
+			price: prices["gpt-4o"],
+			usage: cost.Usage{InputTokens: 1_000_000, OutputTokens: 100_000},
+			want:  3.5,
+		},
+		{
+			name:  "cached input",
+			price: prices["gpt-4o"],
+			usage: cost.Usage{InputTokens: 1_000_000, CachedInputTokens: 400_000},
+			want:  0.6*2.5 + 0.4*1.25,
+		},
+		{
+			name:  "reasoning",
+			price: prices["o3"],
+			usage: cost.Usage{OutputTokens: 1_000_000, ReasoningTokens: 500_000},
+			want:  0.5*8 + 0.5*4,
+		},
+		{
+			name:  "request fee",
+			price: prices["perplexity/sonar-pro"],
+			usage: cost.Usage{InputTokens: 1000, OutputTokens: 1000},
+			want:  (3000.0+15000.0)/1_000_000 + 0.006,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.InDelta(t, tt.want, tt.price.Cost(tt.usage), 1e-9)
+		})
+	}
+}
+
+func TestPriceTable_JSON(t *testing.T) {
+	var table cost.PriceTable
+	require.NoError(t, json.Unmarshal([]byte(`{"perplexity/sonar":{"input":1,"output":1,"request":0.005}}`), &table))
+
+	p, ok := table.Lookup("perplexity", "sonar")
+	require.True(t, ok)
+	require.Equal(t, cost.Price{Input: 1, Output: 1, Request: 0.005}, p)
+}
+
+func TestNewUsage(t *testing.T) {
+	res := &perplexity.ChatCompletionResponse{}
+	res.Model = "sonar-pro"
+	res.Usage = &openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 20}
+
+	usage, model, ok := cost.NewUsage(res)
+	require.True(t, ok)
+	require.Equal(t, "sonar-pro", model)
+	require.Equal(t, cost.Usage{InputTokens: 10, OutputTokens: 20}, usage)
+
+	_, _, ok = cost.NewUsage(&openai.Response{})
+	require.False(t, ok)
+}
+
+func reply(model string, input, output int) promptstest.Reply {
+	r := promptstest.Text("ok")
+	r.Response.Model = model
+	r.Response.Usage = &openai.ResponseUsage{InputTokens: input, OutputTokens: output}
+
+	return r
+}
+
+func TestResponder(t *testing.T) {
+	fake := promptstest.NewFake(t).Always(promptstest.Any(), reply("sonar", 500_000, 500_000))
+
+	prompt := cost.NewResponder(fake, prices, nil,
+		cost.WithProvider("perplexity"),
+		cost.WithTags("tenant", "project"),
+		cost.WithBudget("tenant", "acme", 2),
+		cost.WithTagBudget("tenant", 10),
+	)
+
+	acme := openai.NewResponseRequest(openai.WithMetadata(map[string]string{"tenant": "acme", "project": "search"}))
+
+	for range 2 {
+		_, err := prompt.Respond(context.Background(), acme)
+		require.NoError(t, err)
+	}
+
+	_, err := prompt.Respond(context.Background(), acme)
+	require.ErrorIs(t, err, cost.ErrBudgetExceeded)
+
+	ctx := cost.NewContext(context.Background(), map[string]string{"tenant": "globex"})
+	_, err = prompt.Respond(ctx, openai.NewResponseRequest())
+	require.NoError(t, err)
+
+	ledger := prompt.Ledger()
+	require.Len(t, fake.Requests(), 3)
+
+	spend := ledger.Spend("tenant", "acme")
+	require.Equal(t, 2, spend.Requests)
+	require.Equal(t, cost.Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000}, spend.Usage)
+	require.InDelta(t, 2.01, spend.Amount, 1e-9)
+
+	require.Equal(t, 2, ledger.Spend("project", "search").Requests)
+	require.Equal(t, 1, ledger.Spend("tenant", "globex").Requests)
+	require.Equal(t, 3, ledger.Spend(cost.Total.Tag, cost.Total.Value).Requests)
+	require.InDelta(t, 3.015, ledger.Spends()[cost.Total].Amount, 1e-9)
+}
+
+func TestResponder_Unpriced(t *testing.T) {
+	fake := promptstest.NewFake(t).On(promptstest.Any(), reply("llama3", 10, 10))
+
+	prompt := cost.NewResponder(fake, prices, cost.NewLedger(), cost.WithTotalBudget(1))
+
+	_, err := prompt.Respond(context.Background(), openai.NewResponseRequest())
+	require.NoError(t, err)
+
+	spend := prompt.Ledger().Spends()[cost.Total]
+	require.Equal(t, 1, spend.Unpriced)
+	require.Zero(t, spend.Amount)
+}
+
+func TestStreamer(t *testing.T) {
+	srv := promptstest.NewServer(t, reply("gpt-4o", 1_000_000, 100_000))
+
+	s := cost.NewStreamer(openai.NewStreamer(srv.Client()), prices, nil, cost.WithTotalBudget(1))
+	require.Equal(t, "openai", s.ProviderName())
+
+	var events int
+	for _, err := range s.Stream(context.Background(), openai.NewResponseRequest()) {
+		require.NoError(t, err)
+		events++
+	}
+	require.Positive(t, events)
+
+	spend := s.Ledger().Spends()[cost.Total]
+	require.Equal(t, 1, spend.Requests)
+	require.Equal(t, cost.Usage{InputTokens: 1_000_000, OutputTokens: 100_000}, spend.Usage)
+	require.InDelta(t, 3.5, spend.Amount, 1e-9)
+
+	for _, err := range s.Stream(context.Background(), openai.NewResponseRequest()) {
+		require.ErrorIs(t, err, cost.ErrBudgetExceeded)
+	}
+
+	require.Equal(t, 1, s.Ledger().Spends()[cost.Total].Requests)
+}
+
+func TestNewUsage_Chunk(t *testing.T) {
+	chunk := &perplexity.ChatCompletionChunk{}
+	chunk.Model = "sonar"
+
+	_, _, ok := cost.NewUsage(chunk)
+	require.False(t, ok)
+
+	chunk.Usage = &openai.CompletionUsage{PromptTokens: 3, CompletionTokens: 4}
+
+	usage, model, ok := cost.NewUsage(chunk)
+	require.True(t, ok)
+	require.Equal(t, "sonar", model)
+	require.Equal(t, cost.Usage{InputTokens: 3, OutputTokens: 4}, usage)
+}
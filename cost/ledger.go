@@ -0,0 +1,91 @@
+package cost
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// Key identifies the value of a tag (e.g. the tenant "acme").
+type Key struct {
+	// Tag is the name of the tag (e.g. "tenant").
+	Tag string `json:"tag"`
+	// Value is the value of the tag.
+	Value string `json:"value"`
+}
+
+// String returns the key as tag=value or "total" for the total spend.
+func (k Key) String() string {
+	if k == (Key{}) {
+		return "total"
+	}
+
+	return fmt.Sprintf("%s=%q", k.Tag, k.Value)
+}
+
+// Spend is the aggregated spend of a tag value.
+type Spend struct {
+	// Requests is the number of requests.
+	Requests int `json:"requests"`
+	// Unpriced is the number of requests without a price for their model.
+	Unpriced int `json:"unpriced,omitempty"`
+	// Usage is the total token usage.
+	Usage Usage `json:"usage"`
+	// Amount is the total cost.
+	Amount float64 `json:"amount"`
+}
+
+// Ledger aggregates the spend per tag value. It is safe for concurrent use.
+type Ledger struct {
+	mu     sync.Mutex
+	spends map[Key]Spend
+}
+
+// NewLedger creates a new empty Ledger.
+func NewLedger() *Ledger {
+	return &Ledger{spends: map[Key]Spend{}}
+}
+
+// Add records a request with the usage and the cost for the tag values.
+// A request without a price is recorded with ok set to false.
+func (l *Ledger) Add(keys []Key, usage Usage, amount float64, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, k := range keys {
+		s := l.spends[k]
+		s.Requests++
+		s.Usage = s.Usage.Add(usage)
+		s.Amount += amount
+
+		if !ok {
+			s.Unpriced++
+		}
+
+		l.spends[k] = s
+	}
+}
+
+// Spend returns the spend of the tag value.
+func (l *Ledger) Spend(tag, value string) Spend {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.spends[Key{Tag: tag, Value: value}]
+}
+
+// Spends returns a copy of the spends of all tag values.
+func (l *Ledger) Spends() map[Key]Spend {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return maps.Clone(l.spends)
+}
+
+// Reset clears the spends, e.g. at the start of a billing period.
+func (l *Ledger) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	clear(l.spends)
+}
@@ -0,0 +1,288 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+// ErrBudgetExceeded is returned when the spend of a tag value has reached its budget.
+var ErrBudgetExceeded = errors.New("cost: budget exceeded")
+
+// Total is the key of the total spend of all requests.
+var Total = Key{}
+
+// Opts are the options of the Responder and the Streamer.
+type Opts struct {
+	// Provider is the provider of the price table lookups. By default it is
+	// the name reported by the wrapped provider (e.g. "perplexity").
+	Provider string
+	// Tags are the names of the tags whose spend is aggregated.
+	Tags []string
+	// Budgets are the ceilings of the spend of tag values.
+	Budgets map[Key]float64
+	// TagBudgets are the ceilings of the spend of every value of a tag.
+	TagBudgets map[string]float64
+}
+
+// Opt is a function type for configuring the Responder and the Streamer.
+type Opt func(*Opts)
+
+// WithProvider sets the provider of the price table lookups.
+func WithProvider(provider string) Opt {
+	return func(o *Opts) {
+		o.Provider = provider
+	}
+}
+
+// WithTags sets the names of the tags whose spend is aggregated. The values
+// are taken from the metadata of the request and the tags of the context.
+func WithTags(tags ...string) Opt {
+	return func(o *Opts) {
+		o.Tags = append(o.Tags, tags...)
+	}
+}
+
+// WithBudget sets the ceiling of the spend of the tag value.
+func WithBudget(tag, value string, ceiling float64) Opt {
+	return func(o *Opts) {
+		o.Budgets[Key{Tag: tag, Value: value}] = ceiling
+	}
+}
+
+// WithTagBudget sets the ceiling of the spend of every value of the tag
+// that has no budget of its own.
+func WithTagBudget(tag string, ceiling float64) Opt {
+	return func(o *Opts) {
+		o.TagBudgets[tag] = ceiling
+	}
+}
+
+// WithTotalBudget sets the ceiling of the total spend of all requests.
+func WithTotalBudget(ceiling float64) Opt {
+	return func(o *Opts) {
+		o.Budgets[Total] = ceiling
+	}
+}
+
+type tagsKey struct{}
+
+// NewContext returns a new context with tags that are accounted in addition
+// to the metadata of the request (e.g. for APIs without metadata).
+func NewContext(ctx context.Context, tags map[string]string) context.Context {
+	return context.WithValue(ctx, tagsKey{}, tags)
+}
+
+// FromContext returns the tags of the context.
+func FromContext(ctx context.Context) (map[string]string, bool) {
+	tags, ok := ctx.Value(tagsKey{}).(map[string]string)
+	return tags, ok
+}
+
+// accountant accounts the spend of responses in a ledger and checks the budgets.
+type accountant struct {
+	prices PriceTable
+	ledger *Ledger
+	opts   *Opts
+}
+
+func newAccountant(provider any, prices PriceTable, ledger *Ledger, opts ...Opt) *accountant {
+	if ledger == nil {
+		ledger = NewLedger()
+	}
+
+	o := &Opts{
+		Provider:   prompts.ProviderName(provider),
+		Budgets:    map[Key]float64{},
+		TagBudgets: map[string]float64{},
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &accountant{prices: prices, ledger: ledger, opts: o}
+}
+
+// Responder is a Responder that accounts the spend of every response in a
+// Ledger and rejects requests of tag values that have reached their budget.
+// Budgets are checked before a request is sent, so concurrent requests may
+// exceed a budget by the cost of the requests in flight.
+type Responder[I, O any] struct {
+	next prompts.Responder[I, O]
+	*accountant
+}
+
+var _ prompts.Prompter[*openai.ResponseRequest, *openai.Response] = (*Responder[*openai.ResponseRequest, *openai.Response])(nil)
+
+// NewResponder wraps the given Responder with accounting in the ledger. If a
+// nil ledger is given, a new ledger is used.
+func NewResponder[I, O any](next prompts.Responder[I, O], prices PriceTable, ledger *Ledger, opts ...Opt) *Responder[I, O] {
+	return &Responder[I, O]{next: next, accountant: newAccountant(next, prices, ledger, opts...)}
+}
+
+// Respond sends the request with the wrapped Responder and accounts the spend
+// of the response. It returns ErrBudgetExceeded without sending the request if
+// a tag value of the request has reached its budget.
+func (r *Responder[I, O]) Respond(ctx context.Context, in I) (O, error) {
+	keys := r.keys(ctx, in)
+
+	if err := r.check(keys); err != nil {
+		var out O
+		return out, err
+	}
+
+	out, err := r.next.Respond(ctx, in)
+	if err != nil {
+		return out, err
+	}
+
+	if usage, model, ok := NewUsage(out); ok {
+		r.add(keys, in, usage, model)
+	}
+
+	return out, nil
+}
+
+// Streamer is a Streamer that accounts the spend of every stream in a Ledger
+// and rejects requests of tag values that have reached their budget. The
+// spend is taken from the usage of the terminal event of the stream (e.g.
+// response.completed or the last chunk of a chat completion).
+type Streamer[I, E any] struct {
+	next prompts.Streamer[I, E]
+	*accountant
+}
+
+var _ prompts.Streamer[*openai.ResponseRequest, *openai.ResponseStreamEvent] = (*Streamer[*openai.ResponseRequest, *openai.ResponseStreamEvent])(nil)
+
+// NewStreamer wraps the given Streamer with accounting in the ledger. If a
+// nil ledger is given, a new ledger is used.
+//
+//	s := cost.NewStreamer(perplexity.NewStreamer(client), prices, ledger, cost.WithTotalBudget(100))
+func NewStreamer[I, E any](next prompts.Streamer[I, E], prices PriceTable, ledger *Ledger, opts ...Opt) *Streamer[I, E] {
+	return &Streamer[I, E]{next: next, accountant: newAccountant(next, prices, ledger, opts...)}
+}
+
+// Stream streams the request with the wrapped Streamer and accounts the spend
+// when the iteration of the stream stops. It yields ErrBudgetExceeded without
+// sending the request if a tag value of the request has reached its budget.
+func (s *Streamer[I, E]) Stream(ctx context.Context, in I) iter.Seq2[E, error] {
+	return func(yield func(E, error) bool) {
+		keys := s.keys(ctx, in)
+
+		if err := s.check(keys); err != nil {
+			var e E
+			yield(e, err)
+
+			return
+		}
+
+		var (
+			usage Usage
+			model string
+			found bool
+		)
+
+		defer func() {
+			if found {
+				s.add(keys, in, usage, model)
+			}
+		}()
+
+		for e, err := range s.next.Stream(ctx, in) {
+			if err == nil {
+				if u, m, ok := NewUsage(e); ok {
+					usage, model, found = u, m, true
+				}
+			}
+
+			if !yield(e, err) {
+				return
+			}
+		}
+	}
+}
+
+// Ledger returns the ledger of the spend.
+func (a *accountant) Ledger() *Ledger {
+	return a.ledger
+}
+
+// ProviderName returns the provider of the price table lookups.
+func (a *accountant) ProviderName() string {
+	return a.opts.Provider
+}
+
+// add records the usage of a response to the request in the ledger.
+func (a *accountant) add(keys []Key, in any, usage Usage, model string) {
+	if model == "" {
+		model = requestModel(in)
+	}
+
+	price, priced := a.prices.Lookup(a.opts.Provider, model)
+	a.ledger.Add(keys, usage, price.Cost(usage), priced)
+}
+
+// keys returns the total key and the keys of the tag values of the request.
+func (a *accountant) keys(ctx context.Context, in any) []Key {
+	tags := map[string]string{}
+
+	if t, ok := FromContext(ctx); ok {
+		for k, v := range t {
+			tags[k] = v
+		}
+	}
+
+	if req, ok := in.(*openai.ResponseRequest); ok && req != nil {
+		for k, v := range req.Metadata {
+			tags[k] = v
+		}
+	}
+
+	keys := []Key{Total}
+	for _, tag := range a.opts.Tags {
+		if v, ok := tags[tag]; ok {
+			keys = append(keys, Key{Tag: tag, Value: v})
+		}
+	}
+
+	return keys
+}
+
+// check returns an error if one of the keys has reached its budget.
+func (a *accountant) check(keys []Key) error {
+	for _, k := range keys {
+		ceiling, ok := a.opts.Budgets[k]
+		if !ok && k != Total {
+			ceiling, ok = a.opts.TagBudgets[k.Tag]
+		}
+
+		if !ok {
+			continue
+		}
+
+		if spent := a.ledger.Spend(k.Tag, k.Value).Amount; spent >= ceiling {
+			return fmt.Errorf("%w: %s spent %.6f of %.6f", ErrBudgetExceeded, k, spent, ceiling)
+		}
+	}
+
+	return nil
+}
+
+// requestModel returns the model of a request of the Responses or Chat Completions API.
+func requestModel(in any) string {
+	switch req := in.(type) {
+	case *openai.ResponseRequest:
+		return req.Model
+	case *openai.ChatCompletionRequest:
+		return req.Model
+	case openai.ChatCompletionRequestUnwrapper:
+		return requestModel(req.Unwrap())
+	default:
+		return ""
+	}
+}
@@ -1,9 +1,37 @@
 package prompts
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
 
-// PromptError represents an error returned by the API.
+// maxErrorBodySize is the maximum size of an error body that is read.
+const maxErrorBodySize = 1 << 20
+
+// requestIDHeaders are the headers used by providers to carry the request ID.
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Amzn-Requestid"}
+
+// PromptError represents an error returned by the API. Providers return it for
+// non-2XX responses, so that callers can inspect the status with errors.As.
+//
+//	var perr *prompts.PromptError
+//	if errors.As(err, &perr) && perr.StatusCode == http.StatusTooManyRequests {
+//		// back off
+//	}
 type PromptError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+	// RequestID is the request ID sent by the provider.
+	RequestID string `json:"-"`
+	// Body is the raw body of the response.
+	Body []byte `json:"-"`
+	// ErrorCode is the provider error code if it is not numeric (e.g. "invalid_api_key").
+	ErrorCode string `json:"-"`
+
 	JSON struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
@@ -13,26 +41,91 @@ type PromptError struct {
 
 // Error returns the error message.
 func (e *PromptError) Error() string {
-	return e.JSON.Message
+	if e.JSON.Message != "" {
+		return e.JSON.Message
+	}
+
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("unexpected status code: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+
+	return ""
 }
 
 // UnmarshalJSON unmarshals the error from JSON.
+// The error may either be an object or a plain message string.
 func (e *PromptError) UnmarshalJSON(data []byte) error {
 	var err struct {
-		JSON struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-			Type    string `json:"type"`
-		} `json:"error"`
+		JSON json.RawMessage `json:"error"`
 	}
 
 	if err := json.Unmarshal(data, &err); err != nil {
 		return err
 	}
 
-	e.JSON.Code = err.JSON.Code
-	e.JSON.Message = err.JSON.Message
-	e.JSON.Type = err.JSON.Type
+	var msg string
+	if err := json.Unmarshal(err.JSON, &msg); err == nil {
+		e.JSON.Message = msg
+		return nil
+	}
+
+	var obj struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Type    string          `json:"type"`
+	}
+
+	if len(err.JSON) > 0 {
+		if err := json.Unmarshal(err.JSON, &obj); err != nil {
+			return err
+		}
+	}
+
+	e.JSON.Code = 0
+	e.ErrorCode = ""
+
+	var code string
+	if err := json.Unmarshal(obj.Code, &code); err == nil {
+		if n, err := strconv.Atoi(code); err == nil {
+			e.JSON.Code = n
+		} else {
+			e.ErrorCode = code
+		}
+	} else {
+		_ = json.Unmarshal(obj.Code, &e.JSON.Code)
+	}
+
+	e.JSON.Message = obj.Message
+	e.JSON.Type = obj.Type
 
 	return nil
 }
+
+// NewPromptError creates a new PromptError from a non-2XX response.
+// The body is read and replaced, so it can be read again by the caller.
+func NewPromptError(resp *http.Response) *PromptError {
+	e := &PromptError{
+		StatusCode: resp.StatusCode,
+	}
+
+	for _, h := range requestIDHeaders {
+		if id := resp.Header.Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+
+	if resp.Body == nil {
+		return e
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	e.Body = body
+
+	if len(bytes.TrimSpace(body)) > 0 {
+		_ = e.UnmarshalJSON(body)
+	}
+
+	return e
+}
@@ -1,6 +1,10 @@
 package prompts_test
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/katallaxie/prompts"
@@ -48,3 +52,71 @@ func TestPromptErro(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPromptError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    http.Header
+		body      string
+		message   string
+		code      int
+		errorCode string
+		requestID string
+	}{
+		{
+			name:    "empty body",
+			status:  http.StatusTooManyRequests,
+			message: "unexpected status code: 429 Too Many Requests",
+		},
+		{
+			name:      "openai error",
+			status:    http.StatusUnauthorized,
+			header:    http.Header{"X-Request-Id": []string{"req_123"}},
+			body:      `{"error":{"code":"invalid_api_key","message":"Incorrect API key provided","type":"invalid_request_error"}}`,
+			message:   "Incorrect API key provided",
+			errorCode: "invalid_api_key",
+			requestID: "req_123",
+		},
+		{
+			name:    "numeric code",
+			status:  http.StatusBadRequest,
+			body:    `{"error":{"code":400,"message":"Bad Request","type":"invalid_request_error"}}`,
+			message: "Bad Request",
+			code:    400,
+		},
+		{
+			name:    "plain message",
+			status:  http.StatusNotFound,
+			body:    `{"error":"model \"qwen3:8b\" not found"}`,
+			message: `model "qwen3:8b" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+
+			res := map[string]any{}
+			_, err := prompts.NewClient().Base(srv.URL).Post("responses").ReceiveSuccess(context.Background(), &res)
+			require.Error(t, err)
+			require.Empty(t, res)
+
+			var perr *prompts.PromptError
+			require.ErrorAs(t, err, &perr)
+			require.Equal(t, tt.status, perr.StatusCode)
+			require.Equal(t, tt.message, perr.Error())
+			require.Equal(t, tt.code, perr.JSON.Code)
+			require.Equal(t, tt.errorCode, perr.ErrorCode)
+			require.Equal(t, tt.requestID, perr.RequestID)
+			require.Equal(t, tt.body, string(perr.Body))
+		})
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -11,6 +10,12 @@ import (
 	"github.com/katallaxie/prompts/perplexity"
 )
 
+// HoroscopeArgs are the arguments of the get_horoscope tool.
+type HoroscopeArgs struct {
+	// Sign is the zodiac sign.
+	Sign string `json:"sign" jsonschema:"description=The zodiac sign,enum=aries,enum=taurus,enum=gemini,enum=cancer,enum=leo,enum=virgo,enum=libra,enum=scorpio,enum=sagittarius,enum=capricorn,enum=aquarius,enum=pisces"`
+}
+
 // This example demonstrates how to create a completion request with a message
 // It then sends the request to the API and prints the last completion content.
 func main() {
@@ -40,29 +45,18 @@ func main() {
 		},
 	}
 
-	req := openai.NewResponseRequest(openai.WithInput(msgs...))
-	req.Model = perplexity.DefaultModel
-	req.Tools = []perplexity.ResponseTool{
-		{
-			Tool: perplexity.ResponseFunctionTool{
-				Function: perplexity.ResponseFunctionDefinition{
-					Name:        "get_horoscope",
-					Description: "Get the horoscope for a given zodiac sign.",
-					Parameters: perplexity.ResponseFunctionParameters{
-						Properties: perplexity.ResponseFunctionProperties{
-							"sign": json.RawMessage(`{"type": "string", "enum": ["aries", "taurus", "gemini", "cancer", "leo", "virgo", "libra", "scorpio", "sagittarius", "capricorn", "aquarius", "pisces"]}`),
-						},
-						Required: []string{"sign"},
-					},
-				},
-			},
-		},
+	tool, err := openai.NewFunctionTool[HoroscopeArgs]("get_horoscope", "Get the horoscope for a given zodiac sign.", true)
+	if err != nil {
+		panic(err)
 	}
 
+	req := openai.NewResponseRequest(openai.WithInput(msgs...), openai.WithTools(tool))
+	req.Model = perplexity.DefaultModel
+
 	res, err := prompt.Respond(context.Background(), req)
 	if err != nil {
 		panic(err)
 	}
 
-	fmt.Println(res.Output)
+	fmt.Println(res.OutputText())
 }
@@ -0,0 +1,247 @@
+package gemini
+
+import "encoding/json"
+
+// Role is the role of a content in the Gemini API.
+type Role string
+
+// Available roles.
+const (
+	// RoleUser is the user role.
+	RoleUser Role = "user"
+	// RoleModel is the model role.
+	RoleModel Role = "model"
+)
+
+// FinishReason is the reason why the model stopped generating.
+type FinishReason string
+
+const (
+	// FinishReasonStop indicates a natural stop point or a stop sequence.
+	FinishReasonStop FinishReason = "STOP"
+	// FinishReasonMaxTokens indicates that the maximum number of tokens was reached.
+	FinishReasonMaxTokens FinishReason = "MAX_TOKENS"
+	// FinishReasonSafety indicates that the candidate was flagged for safety reasons.
+	FinishReasonSafety FinishReason = "SAFETY"
+	// FinishReasonRecitation indicates that the candidate was flagged for recitation.
+	FinishReasonRecitation FinishReason = "RECITATION"
+	// FinishReasonBlocklist indicates that the candidate contains forbidden terms.
+	FinishReasonBlocklist FinishReason = "BLOCKLIST"
+	// FinishReasonProhibitedContent indicates that the candidate contains prohibited content.
+	FinishReasonProhibitedContent FinishReason = "PROHIBITED_CONTENT"
+	// FinishReasonSPII indicates that the candidate contains sensitive personally identifiable information.
+	FinishReasonSPII FinishReason = "SPII"
+	// FinishReasonMalformedFunctionCall indicates that the model generated an invalid function call.
+	FinishReasonMalformedFunctionCall FinishReason = "MALFORMED_FUNCTION_CALL"
+)
+
+// FunctionCallingMode is the mode of function calling.
+type FunctionCallingMode string
+
+const (
+	// FunctionCallingModeAuto lets the model decide to call functions.
+	FunctionCallingModeAuto FunctionCallingMode = "AUTO"
+	// FunctionCallingModeAny forces the model to call a function.
+	FunctionCallingModeAny FunctionCallingMode = "ANY"
+	// FunctionCallingModeNone disables function calling.
+	FunctionCallingModeNone FunctionCallingMode = "NONE"
+)
+
+// GenerateContentRequest is the request of the generateContent method.
+type GenerateContentRequest struct {
+	// Contents is the conversation with the model.
+	Contents []Content `json:"contents"`
+	// SystemInstruction is the system instruction of the model.
+	SystemInstruction *Content `json:"systemInstruction,omitempty"` //nolint:tagliatelle
+	// Tools is the list of tools the model may use.
+	Tools []Tool `json:"tools,omitempty"`
+	// ToolConfig is the configuration of the tools.
+	ToolConfig *ToolConfig `json:"toolConfig,omitempty"` //nolint:tagliatelle
+	// SafetySettings is the list of safety settings.
+	SafetySettings []SafetySetting `json:"safetySettings,omitempty"` //nolint:tagliatelle
+	// GenerationConfig is the configuration of the generation.
+	GenerationConfig *GenerationConfig `json:"generationConfig,omitempty"` //nolint:tagliatelle
+}
+
+// Content is a multi-part message of the conversation.
+type Content struct {
+	// Role is the role of the content.
+	Role Role `json:"role,omitempty"`
+	// Parts is the list of parts of the content.
+	Parts []Part `json:"parts"`
+}
+
+// Part is a part of a content.
+type Part struct {
+	// Text is the text of the part.
+	Text string `json:"text,omitempty"`
+	// InlineData is the inline data of the part.
+	InlineData *Blob `json:"inlineData,omitempty"` //nolint:tagliatelle
+	// FileData is the reference to a file of the part.
+	FileData *FileData `json:"fileData,omitempty"` //nolint:tagliatelle
+	// FunctionCall is the function call of the part.
+	FunctionCall *FunctionCall `json:"functionCall,omitempty"` //nolint:tagliatelle
+	// FunctionResponse is the function response of the part.
+	FunctionResponse *FunctionResponse `json:"functionResponse,omitempty"` //nolint:tagliatelle
+	// Thought is a flag to indicate that the part is a thought summary.
+	Thought bool `json:"thought,omitempty"`
+	// ThoughtSignature is the opaque signature of the thoughts of the part.
+	ThoughtSignature string `json:"thoughtSignature,omitempty"` //nolint:tagliatelle
+}
+
+// Blob is inline data.
+type Blob struct {
+	// MIMEType is the MIME type of the data.
+	MIMEType string `json:"mimeType"` //nolint:tagliatelle
+	// Data is the base64 encoded data.
+	Data string `json:"data"`
+}
+
+// FileData is a reference to a file.
+type FileData struct {
+	// MIMEType is the MIME type of the file.
+	MIMEType string `json:"mimeType,omitempty"` //nolint:tagliatelle
+	// FileURI is the URI of the file.
+	FileURI string `json:"fileUri"` //nolint:tagliatelle
+}
+
+// FunctionCall is a function call of the model.
+type FunctionCall struct {
+	// ID is the unique identifier of the function call.
+	ID string `json:"id,omitempty"`
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Args is the arguments of the function call.
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+// FunctionResponse is the result of a function call.
+type FunctionResponse struct {
+	// ID is the unique identifier of the function call.
+	ID string `json:"id,omitempty"`
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Response is the JSON object result of the function call.
+	Response json.RawMessage `json:"response"`
+}
+
+// Tool is a set of functions the model may call.
+type Tool struct {
+	// FunctionDeclarations is the list of functions.
+	FunctionDeclarations []FunctionDeclaration `json:"functionDeclarations,omitempty"` //nolint:tagliatelle
+}
+
+// FunctionDeclaration is the declaration of a function.
+type FunctionDeclaration struct {
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Description is the description of the function.
+	Description string `json:"description,omitempty"`
+	// ParametersJSONSchema is the JSON Schema of the function parameters.
+	ParametersJSONSchema json.RawMessage `json:"parametersJsonSchema,omitempty"` //nolint:tagliatelle
+}
+
+// ToolConfig is the configuration of the tools.
+type ToolConfig struct {
+	// FunctionCallingConfig is the configuration of function calling.
+	FunctionCallingConfig FunctionCallingConfig `json:"functionCallingConfig"` //nolint:tagliatelle
+}
+
+// FunctionCallingConfig is the configuration of function calling.
+type FunctionCallingConfig struct {
+	// Mode is the mode of function calling.
+	Mode FunctionCallingMode `json:"mode"`
+}
+
+// SafetySetting is the threshold of blocking a harm category.
+type SafetySetting struct {
+	// Category is the harm category.
+	Category string `json:"category"`
+	// Threshold is the blocking threshold.
+	Threshold string `json:"threshold"`
+}
+
+// GenerationConfig is the configuration of the generation.
+type GenerationConfig struct {
+	// Temperature is the sampling temperature.
+	Temperature *float32 `json:"temperature,omitempty"`
+	// TopP is the nucleus sampling parameter.
+	TopP *float64 `json:"topP,omitempty"` //nolint:tagliatelle
+	// TopK is the number of top tokens to sample from.
+	TopK *int `json:"topK,omitempty"` //nolint:tagliatelle
+	// MaxOutputTokens is the maximum number of tokens to generate.
+	MaxOutputTokens *int `json:"maxOutputTokens,omitempty"` //nolint:tagliatelle
+	// ResponseMIMEType is the MIME type of the generated text (e.g. "application/json").
+	ResponseMIMEType string `json:"responseMimeType,omitempty"` //nolint:tagliatelle
+	// ResponseJSONSchema is the JSON Schema of the generated JSON.
+	ResponseJSONSchema json.RawMessage `json:"responseJsonSchema,omitempty"` //nolint:tagliatelle
+}
+
+// GenerateContentResponse is the response of the generateContent method.
+type GenerateContentResponse struct {
+	// ResponseID is the unique identifier of the response.
+	ResponseID string `json:"responseId,omitempty"` //nolint:tagliatelle
+	// ModelVersion is the model that generated the response.
+	ModelVersion string `json:"modelVersion,omitempty"` //nolint:tagliatelle
+	// Candidates is the list of generated candidates.
+	Candidates []Candidate `json:"candidates,omitempty"`
+	// PromptFeedback is the feedback on the safety of the prompt.
+	PromptFeedback *PromptFeedback `json:"promptFeedback,omitempty"` //nolint:tagliatelle
+	// UsageMetadata is the token usage of the request.
+	UsageMetadata *UsageMetadata `json:"usageMetadata,omitempty"` //nolint:tagliatelle
+}
+
+// Candidate is a generated candidate.
+type Candidate struct {
+	// Content is the generated content.
+	Content Content `json:"content"`
+	// FinishReason is the reason why the model stopped generating.
+	FinishReason FinishReason `json:"finishReason,omitempty"` //nolint:tagliatelle
+	// SafetyRatings is the list of safety ratings of the candidate.
+	SafetyRatings []SafetyRating `json:"safetyRatings,omitempty"` //nolint:tagliatelle
+	// Index is the index of the candidate.
+	Index int `json:"index,omitempty"`
+}
+
+// SafetyRating is the safety rating of a harm category.
+type SafetyRating struct {
+	// Category is the harm category.
+	Category string `json:"category"`
+	// Probability is the harm probability (e.g. "NEGLIGIBLE" or "HIGH").
+	Probability string `json:"probability"`
+	// Blocked is a flag to indicate that the content was blocked.
+	Blocked bool `json:"blocked,omitempty"`
+}
+
+// SafetyRatings are the safety ratings of the candidate or the blocked prompt
+// of a response. They are attached to the response as an extension.
+//
+//	ratings, ok := openai.ExtensionFor[gemini.SafetyRatings](res.Extensions)
+type SafetyRatings []SafetyRating
+
+// Provider returns the name of the provider of the safety ratings.
+func (SafetyRatings) Provider() string {
+	return providerName
+}
+
+// PromptFeedback is the feedback on the safety of the prompt.
+type PromptFeedback struct {
+	// BlockReason is the reason why the prompt was blocked.
+	BlockReason string `json:"blockReason,omitempty"` //nolint:tagliatelle
+	// SafetyRatings is the list of safety ratings of the prompt.
+	SafetyRatings []SafetyRating `json:"safetyRatings,omitempty"` //nolint:tagliatelle
+}
+
+// UsageMetadata is the token usage of a request.
+type UsageMetadata struct {
+	// PromptTokenCount is the number of tokens of the prompt.
+	PromptTokenCount int `json:"promptTokenCount"` //nolint:tagliatelle
+	// CachedContentTokenCount is the number of cached tokens of the prompt.
+	CachedContentTokenCount int `json:"cachedContentTokenCount,omitempty"` //nolint:tagliatelle
+	// CandidatesTokenCount is the number of tokens of the candidates.
+	CandidatesTokenCount int `json:"candidatesTokenCount"` //nolint:tagliatelle
+	// ThoughtsTokenCount is the number of tokens of the thoughts.
+	ThoughtsTokenCount int `json:"thoughtsTokenCount,omitempty"` //nolint:tagliatelle
+	// TotalTokenCount is the total number of tokens.
+	TotalTokenCount int `json:"totalTokenCount"` //nolint:tagliatelle
+}
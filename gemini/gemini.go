@@ -0,0 +1,459 @@
+// Package gemini implements a Prompter for the Google Gemini generateContent API.
+package gemini
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+type (
+	ResponseRequest = openai.ResponseRequest
+	Response        = openai.Response
+	ResponseInput   = openai.ResponseInput
+	ResponseTool    = openai.ResponseTool
+)
+
+// DefaultURL is the default endpoint for the Gemini API.
+const DefaultURL = "https://generativelanguage.googleapis.com/v1beta/"
+
+// DefaultModel is the default model for the Gemini API.
+const DefaultModel = "gemini-2.5-flash"
+
+const apiKeyHeader = "X-Goog-Api-Key"
+
+// providerName is the name of the provider reported to middleware.
+const providerName = "gcp.gemini"
+
+// ErrUnsupported is returned when a request can not be translated to the Gemini API.
+var ErrUnsupported = errors.New("gemini: unsupported")
+
+// Gemini is a struct that implements the Prompter interface for the Gemini API.
+type Gemini[I *ResponseRequest, O *Response] struct {
+	client         *prompts.Client
+	safetySettings []SafetySetting
+}
+
+var _ prompts.Prompter[*ResponseRequest, *Response] = (*Gemini[*ResponseRequest, *Response])(nil)
+
+// Opts are the options of the Gemini provider.
+type Opts struct {
+	// BaseURL is the endpoint of the API.
+	BaseURL string
+	// APIKey is sent as the x-goog-api-key header.
+	APIKey string
+	// SafetySettings are sent with every request.
+	SafetySettings []SafetySetting
+}
+
+// Opt is a function type for configuring the Gemini provider.
+type Opt func(*Opts)
+
+// WithBaseURL sets the endpoint of the API.
+func WithBaseURL(u string) Opt {
+	return func(o *Opts) {
+		o.BaseURL = u
+	}
+}
+
+// WithAPIKey sets the x-goog-api-key header.
+func WithAPIKey(key string) Opt {
+	return func(o *Opts) {
+		o.APIKey = key
+	}
+}
+
+// WithSafetySettings sets the safety settings sent with every request.
+func WithSafetySettings(settings ...SafetySetting) Opt {
+	return func(o *Opts) {
+		o.SafetySettings = append(o.SafetySettings, settings...)
+	}
+}
+
+// New creates a new Gemini with the given client.
+//
+//	prompt := gemini.New(prompts.NewClient(), gemini.WithAPIKey(os.Getenv("GEMINI_API_KEY")))
+func New(client *prompts.Client, opts ...Opt) prompts.Prompter[*ResponseRequest, *Response] {
+	o := &Opts{BaseURL: DefaultURL}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base := client.New().Base(o.BaseURL)
+
+	if o.APIKey != "" {
+		base.Set(apiKeyHeader, o.APIKey)
+	}
+
+	return &Gemini[*ResponseRequest, *Response]{client: base, safetySettings: o.SafetySettings}
+}
+
+// ProviderName returns the name of the provider.
+func (p *Gemini[I, O]) ProviderName() string {
+	return providerName
+}
+
+// Respond translates the request into a generateContent request, sends it
+// and maps the first candidate back into a response.
+func (p *Gemini[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	body, err := NewGenerateContentRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	body.SafetySettings = append(body.SafetySettings, p.safetySettings...)
+
+	res := &GenerateContentResponse{}
+
+	_, err = p.client.New().Post(modelPath(req, "generateContent")).BodyJSON(body).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(res), nil
+}
+
+// modelPath returns the path of the method of the model of the request.
+func modelPath(req *ResponseRequest, method string) string {
+	model := strings.TrimPrefix(req.Model, "models/")
+	if model == "" {
+		model = DefaultModel
+	}
+
+	return "models/" + url.PathEscape(model) + ":" + method
+}
+
+// NewGenerateContentRequest translates a response request into a generateContent request.
+// Instructions and system or developer messages become the system
+// instruction, function calls and their outputs become functionCall and
+// functionResponse parts.
+func NewGenerateContentRequest(req *ResponseRequest) (*GenerateContentRequest, error) {
+	body := &GenerateContentRequest{}
+
+	system := []Part{}
+	if req.Instructions != "" {
+		system = append(system, Part{Text: req.Instructions})
+	}
+
+	// function responses need the name of the function, which is only
+	// part of the function call.
+	names := map[string]string{}
+	for _, input := range req.Input {
+		if call, ok := input.Item.(openai.ResponseOutputFunctionCall); ok {
+			names[call.CallID] = call.Name
+		}
+	}
+
+	var signature string
+
+	for _, input := range req.Input {
+		if input.Item == nil && (input.Role == openai.RoleSystem || input.Role == openai.RoleDeveloper) {
+			for _, c := range input.Content {
+				if text, ok := c.GetText(); ok {
+					system = append(system, Part{Text: text.Text})
+				}
+			}
+
+			continue
+		}
+
+		// the signature of a reasoning item belongs to the next part of the model.
+		if reasoning, ok := input.Item.(openai.ResponseOutputReasoning); ok {
+			signature = reasoning.EncryptedContent
+			continue
+		}
+
+		role, parts, err := newParts(input, names)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(parts) == 0 {
+			continue
+		}
+
+		if role == RoleModel && signature != "" {
+			parts[0].ThoughtSignature = signature
+			signature = ""
+		}
+
+		if n := len(body.Contents); n > 0 && body.Contents[n-1].Role == role {
+			body.Contents[n-1].Parts = append(body.Contents[n-1].Parts, parts...)
+			continue
+		}
+
+		body.Contents = append(body.Contents, Content{Role: role, Parts: parts})
+	}
+
+	if len(system) > 0 {
+		body.SystemInstruction = &Content{Parts: system}
+	}
+
+	declarations := []FunctionDeclaration{}
+	for _, t := range req.Tools {
+		fn, ok := t.Tool.(openai.ResponseFunctionTool)
+		if !ok {
+			return nil, fmt.Errorf("%w: tool %T", ErrUnsupported, t.Tool)
+		}
+
+		schema, err := json.Marshal(fn.Function.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		declarations = append(declarations, FunctionDeclaration{
+			Name:                 fn.Function.Name,
+			Description:          fn.Function.Description,
+			ParametersJSONSchema: schema,
+		})
+	}
+
+	if len(declarations) > 0 {
+		body.Tools = []Tool{{FunctionDeclarations: declarations}}
+	}
+
+	switch req.ToolChoice {
+	case openai.ToolChoiceAuto:
+		body.ToolConfig = &ToolConfig{FunctionCallingConfig: FunctionCallingConfig{Mode: FunctionCallingModeAuto}}
+	case openai.ToolChoiceNone:
+		body.ToolConfig = &ToolConfig{FunctionCallingConfig: FunctionCallingConfig{Mode: FunctionCallingModeNone}}
+	case openai.ToolChoiceRequired:
+		body.ToolConfig = &ToolConfig{FunctionCallingConfig: FunctionCallingConfig{Mode: FunctionCallingModeAny}}
+	default:
+	}
+
+	body.GenerationConfig = newGenerationConfig(req)
+
+	return body, nil
+}
+
+// newGenerationConfig returns the generation config of the request or nil.
+func newGenerationConfig(req *ResponseRequest) *GenerationConfig {
+	cfg := &GenerationConfig{
+		Temperature:     req.Temperature,
+		TopP:            req.TopP,
+		TopK:            req.TopK,
+		MaxOutputTokens: req.MaxTokens,
+	}
+
+	if req.Text != nil && req.Text.Format != nil {
+		switch req.Text.Format.Type {
+		case openai.ResponseTextFormatJSONSchema:
+			cfg.ResponseMIMEType = "application/json"
+			cfg.ResponseJSONSchema = req.Text.Format.Schema
+		case openai.ResponseTextFormatJSONObject:
+			cfg.ResponseMIMEType = "application/json"
+		default:
+		}
+	}
+
+	if cfg.Temperature == nil && cfg.TopP == nil && cfg.TopK == nil && cfg.MaxOutputTokens == nil && cfg.ResponseMIMEType == "" {
+		return nil
+	}
+
+	return cfg
+}
+
+// newParts translates an input into the parts of a content with the returned role.
+func newParts(input ResponseInput, names map[string]string) (Role, []Part, error) {
+	switch item := input.Item.(type) {
+	case nil:
+	case openai.ResponseInputFunctionCallOutput:
+		return RoleUser, []Part{{FunctionResponse: &FunctionResponse{
+			ID:       item.CallID,
+			Name:     names[item.CallID],
+			Response: newFunctionResponse(item.Output),
+		}}}, nil
+	case openai.ResponseOutputFunctionCall:
+		args := json.RawMessage(item.Arguments)
+		if len(strings.TrimSpace(item.Arguments)) == 0 {
+			args = json.RawMessage("{}")
+		}
+
+		return RoleModel, []Part{{FunctionCall: &FunctionCall{ID: item.CallID, Name: item.Name, Args: args}}}, nil
+	case openai.ResponseOutputMessage:
+		parts := []Part{}
+		if text := item.Text(); text != "" {
+			parts = append(parts, Part{Text: text})
+		}
+
+		return RoleModel, parts, nil
+	default:
+		return "", nil, fmt.Errorf("%w: input item %T", ErrUnsupported, item)
+	}
+
+	role := RoleUser
+	if input.Role == openai.RoleAssistant {
+		role = RoleModel
+	}
+
+	parts := make([]Part, 0, len(input.Content))
+	for _, c := range input.Content {
+		switch content := c.Content.(type) {
+		case openai.ResponseMessageContentText:
+			parts = append(parts, Part{Text: content.Text})
+		case openai.ResponseMessageContentImage:
+			parts = append(parts, newDataPart(content.Image.URL, content.Image.FileID, content.Image.MIMEType, content.Image.Base64))
+		case openai.ResponseMessageContentFile:
+			parts = append(parts, newDataPart(content.File.URL, content.File.FileID, content.File.MIMEType, content.File.Base64))
+		case openai.ResponseMessageContentAudio:
+			parts = append(parts, Part{InlineData: &Blob{MIMEType: "audio/" + string(content.Audio.Format), Data: content.Audio.Data}})
+		default:
+			return "", nil, fmt.Errorf("%w: content %T", ErrUnsupported, content)
+		}
+	}
+
+	return role, parts, nil
+}
+
+// newDataPart returns a part with inline data or a reference to a file.
+func newDataPart(uri, fileID, mimeType, data string) Part {
+	if data != "" {
+		return Part{InlineData: &Blob{MIMEType: mimeType, Data: data}}
+	}
+
+	if fileID != "" {
+		uri = fileID
+	}
+
+	return Part{FileData: &FileData{MIMEType: mimeType, FileURI: uri}}
+}
+
+// newFunctionResponse returns the output of a function call as JSON object.
+// Outputs that are no JSON objects are wrapped as {"result": output}.
+func newFunctionResponse(output string) json.RawMessage {
+	trimmed := bytes.TrimSpace([]byte(output))
+	if len(trimmed) > 0 && trimmed[0] == '{' && json.Valid(trimmed) {
+		return trimmed
+	}
+
+	var result any = output
+	if json.Valid(trimmed) {
+		result = json.RawMessage(trimmed)
+	}
+
+	b, _ := json.Marshal(map[string]any{"result": result})
+
+	return b
+}
+
+// NewResponse maps the first candidate of a generateContent response into a
+// response. Text parts become a message, function calls become function call
+// items and thoughts become reasoning items. The safety ratings are attached
+// to the response as SafetyRatings.
+func NewResponse(res *GenerateContentResponse) *Response {
+	r := &Response{
+		ID:     res.ResponseID,
+		Object: "response",
+		Model:  res.ModelVersion,
+		Status: openai.ResponseStatusCompleted,
+	}
+
+	if u := res.UsageMetadata; u != nil {
+		r.Usage = &openai.ResponseUsage{
+			InputTokens:         u.PromptTokenCount,
+			InputTokensDetails:  openai.ResponseInputTokensDetails{CachedTokens: u.CachedContentTokenCount},
+			OutputTokens:        u.CandidatesTokenCount + u.ThoughtsTokenCount,
+			OutputTokensDetails: openai.ResponseOutputTokensDetails{ReasoningTokens: u.ThoughtsTokenCount},
+			TotalTokens:         u.TotalTokenCount,
+		}
+	}
+
+	if f := res.PromptFeedback; f != nil && f.BlockReason != "" {
+		r.Status = openai.ResponseStatusFailed
+		r.Error = &openai.ResponseError{Code: "prompt_blocked", Message: "gemini: prompt blocked: " + f.BlockReason}
+		r.Extensions = safetyRatings(f.SafetyRatings)
+
+		return r
+	}
+
+	if len(res.Candidates) == 0 {
+		return r
+	}
+
+	candidate := res.Candidates[0]
+	r.Extensions = safetyRatings(candidate.SafetyRatings)
+
+	var message *openai.ResponseOutputMessage
+
+	flush := func() {
+		if message != nil {
+			r.Output = append(r.Output, openai.ResponseOutput{Output: *message})
+			message = nil
+		}
+	}
+
+	for i, part := range candidate.Content.Parts {
+		switch {
+		case part.Thought:
+			flush()
+			r.Output = append(r.Output, openai.ResponseOutput{Output: openai.ResponseOutputReasoning{
+				Summary:          []openai.ResponseOutputReasoningText{{Type: "summary_text", Text: part.Text}},
+				EncryptedContent: part.ThoughtSignature,
+			}})
+
+			continue
+		case part.ThoughtSignature != "":
+			flush()
+			r.Output = append(r.Output, openai.ResponseOutput{Output: openai.ResponseOutputReasoning{
+				Summary:          []openai.ResponseOutputReasoningText{},
+				EncryptedContent: part.ThoughtSignature,
+			}})
+		}
+
+		switch {
+		case part.FunctionCall != nil:
+			flush()
+
+			id := part.FunctionCall.ID
+			if id == "" {
+				id = fmt.Sprintf("call_%d", i)
+			}
+
+			r.Output = append(r.Output, openai.ResponseOutput{Output: openai.ResponseOutputFunctionCall{
+				ID:        id,
+				CallID:    id,
+				Status:    openai.ResponseStatusCompleted,
+				Name:      part.FunctionCall.Name,
+				Arguments: string(part.FunctionCall.Args),
+			}})
+		case part.Text != "":
+			if message == nil {
+				message = &openai.ResponseOutputMessage{Role: openai.RoleAssistant, Status: openai.ResponseStatusCompleted}
+			}
+
+			message.ResponseOutputMessageContent = append(message.ResponseOutputMessageContent, openai.ResponseOutputMessageContent{
+				Content: openai.ResponseOutputMessageContentText{Text: part.Text},
+			})
+		}
+	}
+
+	flush()
+
+	switch candidate.FinishReason {
+	case FinishReasonMaxTokens:
+		r.Status = openai.ResponseStatusIncomplete
+		r.IncompleteDetails = &openai.ResponseIncompleteDetails{Reason: openai.IncompleteReasonMaxOutputTokens}
+	case FinishReasonSafety, FinishReasonRecitation, FinishReasonBlocklist, FinishReasonProhibitedContent, FinishReasonSPII:
+		r.Status = openai.ResponseStatusIncomplete
+		r.IncompleteDetails = &openai.ResponseIncompleteDetails{Reason: openai.IncompleteReasonContentFilter}
+	default:
+	}
+
+	return r
+}
+
+// safetyRatings returns the safety ratings as response extensions.
+func safetyRatings(ratings []SafetyRating) []openai.Extension {
+	if len(ratings) == 0 {
+		return nil
+	}
+
+	return []openai.Extension{SafetyRatings(ratings)}
+}
@@ -0,0 +1,94 @@
+package gemini_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/gemini"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGenerateContentRequest(t *testing.T) {
+	req := openai.NewResponseRequest(
+		openai.WithInstructions("Be brief."),
+		openai.WithInput(
+			openai.NewInputMessage(openai.RoleUser, "Weather in Berlin?"),
+			openai.ResponseInput{Item: openai.ResponseOutputReasoning{EncryptedContent: "sig"}},
+			openai.ResponseInput{Item: openai.ResponseOutputFunctionCall{CallID: "call_0", Name: "weather", Arguments: `{"city":"Berlin"}`}},
+			openai.NewFunctionCallOutput("call_0", "sunny"),
+		),
+		openai.WithTools(openai.ResponseTool{Tool: openai.ResponseFunctionTool{
+			Function: openai.ResponseFunctionDefinition{Name: "weather", Description: "Get the weather."},
+		}}),
+	)
+	req.ToolChoice = openai.ToolChoiceAuto
+
+	body, err := gemini.NewGenerateContentRequest(req)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"systemInstruction": {"parts": [{"text": "Be brief."}]},
+		"contents": [
+			{"role": "user", "parts": [{"text": "Weather in Berlin?"}]},
+			{"role": "model", "parts": [{"functionCall": {"id": "call_0", "name": "weather", "args": {"city": "Berlin"}}, "thoughtSignature": "sig"}]},
+			{"role": "user", "parts": [{"functionResponse": {"id": "call_0", "name": "weather", "response": {"result": "sunny"}}}]}
+		],
+		"tools": [{"functionDeclarations": [{"name": "weather", "description": "Get the weather.", "parametersJsonSchema": {"type": "object"}}]}],
+		"toolConfig": {"functionCallingConfig": {"mode": "AUTO"}}
+	}`, string(b))
+}
+
+func TestRespond(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1beta/models/gemini-2.5-pro:generateContent", r.URL.Path)
+		require.Equal(t, "secret", r.Header.Get("X-Goog-Api-Key"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"responseId": "resp_1",
+			"modelVersion": "gemini-2.5-pro",
+			"candidates": [{
+				"content": {"role": "model", "parts": [
+					{"text": "Thinking about it.", "thought": true},
+					{"text": "It is "},
+					{"text": "sunny."},
+					{"functionCall": {"name": "weather", "args": {"city": "Paris"}}, "thoughtSignature": "sig"}
+				]},
+				"finishReason": "SAFETY",
+				"safetyRatings": [{"category": "HARM_CATEGORY_HARASSMENT", "probability": "NEGLIGIBLE"}]
+			}],
+			"usageMetadata": {"promptTokenCount": 10, "candidatesTokenCount": 5, "thoughtsTokenCount": 3, "totalTokenCount": 18}
+		}`))
+	}))
+	defer srv.Close()
+
+	p := gemini.New(prompts.NewClient(), gemini.WithBaseURL(srv.URL+"/v1beta/"), gemini.WithAPIKey("secret"))
+
+	req := openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "Weather?")))
+	req.Model = "models/gemini-2.5-pro"
+
+	res, err := p.Respond(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Equal(t, "resp_1", res.ID)
+	require.Equal(t, "It is sunny.", res.OutputText())
+	require.Len(t, res.Output, 4)
+	require.Equal(t, []openai.ResponseOutputFunctionCall{
+		{ID: "call_3", CallID: "call_3", Status: openai.ResponseStatusCompleted, Name: "weather", Arguments: `{"city": "Paris"}`},
+	}, res.FunctionCalls())
+	require.Equal(t, openai.ResponseStatusIncomplete, res.Status)
+	require.Equal(t, openai.IncompleteReasonContentFilter, res.IncompleteDetails.Reason)
+	ratings, ok := openai.ExtensionFor[gemini.SafetyRatings](res.Extensions)
+	require.True(t, ok)
+	require.Equal(t, gemini.SafetyRatings{{Category: "HARM_CATEGORY_HARASSMENT", Probability: "NEGLIGIBLE"}}, ratings)
+	require.Empty(t, res.Metadata)
+	require.Equal(t, 8, res.Usage.OutputTokens)
+	require.Equal(t, 3, res.Usage.OutputTokensDetails.ReasoningTokens)
+}
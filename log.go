@@ -0,0 +1,369 @@
+package prompts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxPayloadSize is the default maximum number of bytes of a logged payload.
+	DefaultMaxPayloadSize = 4096
+	// Redacted replaces redacted values in logs.
+	Redacted = "[REDACTED]"
+)
+
+// DefaultRedactedHeaders are the headers carrying credentials, e.g. the
+// Authorization header set by Client.APIKey and Client.SetBasicAuth.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Api-Key",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// redactedQuery are the query parameters carrying credentials (e.g. the API key of Gemini).
+var redactedQuery = []string{"key", "api_key", "api-key"}
+
+var (
+	_ Doer                = (*LogDoer)(nil)
+	_ Responder[any, any] = (*LogResponder[any, any])(nil)
+)
+
+// LogOpts are the options of the logging middleware.
+type LogOpts struct {
+	// Logger is the logger (default: slog.Default()).
+	Logger *slog.Logger
+	// Level is the level of successful exchanges (default: slog.LevelDebug).
+	Level slog.Level
+	// ErrorLevel is the level of failed exchanges and non-2XX responses (default: slog.LevelError).
+	ErrorLevel slog.Level
+	// PayloadSampleRate is the fraction of exchanges whose payloads are logged (default: 1).
+	PayloadSampleRate float64
+	// MaxPayloadSize is the maximum number of bytes of a logged payload. Longer payloads are truncated.
+	MaxPayloadSize int
+	// RedactedHeaders are the headers whose values are redacted.
+	RedactedHeaders []string
+	// RedactedPatterns are the patterns of the content that is redacted from payloads (e.g. e-mail addresses).
+	RedactedPatterns []*regexp.Regexp
+}
+
+// LogOpt is a function type for configuring the logging middleware.
+type LogOpt func(*LogOpts)
+
+// WithLogger sets the logger.
+func WithLogger(logger *slog.Logger) LogOpt {
+	return func(o *LogOpts) {
+		if logger != nil {
+			o.Logger = logger
+		}
+	}
+}
+
+// WithLogLevel sets the level of successful and failed exchanges.
+func WithLogLevel(level, errorLevel slog.Level) LogOpt {
+	return func(o *LogOpts) {
+		o.Level = level
+		o.ErrorLevel = errorLevel
+	}
+}
+
+// WithPayloadSampleRate sets the fraction of exchanges whose payloads are
+// logged. A rate of 0 disables logging payloads.
+func WithPayloadSampleRate(rate float64) LogOpt {
+	return func(o *LogOpts) {
+		o.PayloadSampleRate = min(max(rate, 0), 1)
+	}
+}
+
+// WithMaxPayloadSize sets the maximum number of bytes of a logged payload.
+func WithMaxPayloadSize(n int) LogOpt {
+	return func(o *LogOpts) {
+		o.MaxPayloadSize = max(n, 0)
+	}
+}
+
+// WithRedactedHeaders adds headers whose values are redacted.
+func WithRedactedHeaders(headers ...string) LogOpt {
+	return func(o *LogOpts) {
+		o.RedactedHeaders = append(o.RedactedHeaders, headers...)
+	}
+}
+
+// WithRedactedPatterns adds patterns of the content that is redacted from payloads.
+//
+//	prompts.WithRedactedPatterns(regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`))
+func WithRedactedPatterns(patterns ...*regexp.Regexp) LogOpt {
+	return func(o *LogOpts) {
+		o.RedactedPatterns = append(o.RedactedPatterns, patterns...)
+	}
+}
+
+func newLogOpts(opts ...LogOpt) *LogOpts {
+	o := &LogOpts{
+		Logger:            slog.Default(),
+		Level:             slog.LevelDebug,
+		ErrorLevel:        slog.LevelError,
+		PayloadSampleRate: 1,
+		MaxPayloadSize:    DefaultMaxPayloadSize,
+		RedactedHeaders:   append([]string{}, DefaultRedactedHeaders...),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// LogDoer is a Doer that logs requests and responses with log/slog.
+// Credentials are redacted from headers and URLs, and the payloads are
+// sampled, truncated and redacted by pattern. Streamed responses are logged
+// without their payload.
+type LogDoer struct {
+	doer Doer
+	opts *LogOpts
+}
+
+// NewLogDoer wraps the given Doer with logging. If a nil doer is given,
+// the http.DefaultClient will be used.
+//
+//	client := prompts.NewClient().Doer(prompts.NewLogDoer(prompts.DefaultClient))
+func NewLogDoer(doer Doer, opts ...LogOpt) *LogDoer {
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+
+	return &LogDoer{doer: doer, opts: newLogOpts(opts...)}
+}
+
+// Do sends the request with the wrapped Doer and logs the exchange.
+func (l *LogDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if !l.opts.Logger.Enabled(ctx, min(l.opts.Level, l.opts.ErrorLevel)) {
+		return l.doer.Do(req)
+	}
+
+	payloads := l.opts.sample()
+
+	var reqBody []byte
+	if payloads {
+		if err := rewindable(req); err != nil {
+			return nil, err
+		}
+
+		reqBody = readRequestBody(req)
+	}
+
+	start := time.Now()
+	resp, err := l.doer.Do(req)
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Duration("duration", time.Since(start)),
+		slog.Any("request_headers", l.opts.redactHeader(req.Header)),
+	}
+
+	if payloads && len(reqBody) > 0 {
+		attrs = append(attrs, slog.String("request_body", l.opts.payload(reqBody)))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		l.opts.Logger.LogAttrs(ctx, l.opts.ErrorLevel, "prompts: request failed", attrs...)
+
+		return resp, err
+	}
+
+	attrs = append(attrs,
+		slog.Int("status", resp.StatusCode),
+		slog.Any("response_headers", l.opts.redactHeader(resp.Header)),
+	)
+
+	if payloads && !isStream(resp) {
+		b, rerr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(b))
+
+		if rerr != nil {
+			return nil, rerr
+		}
+
+		attrs = append(attrs, slog.String("response_body", l.opts.payload(b)))
+	}
+
+	level := l.opts.Level
+	if resp.StatusCode >= http.StatusBadRequest {
+		level = l.opts.ErrorLevel
+	}
+
+	l.opts.Logger.LogAttrs(ctx, level, "prompts: request", attrs...)
+
+	return resp, nil
+}
+
+// LogResponder is a Responder that logs the requests and responses of the
+// wrapped Responder with log/slog. The payloads are logged as JSON and are
+// sampled, truncated and redacted by pattern.
+type LogResponder[I, O any] struct {
+	next Responder[I, O]
+	opts *LogOpts
+}
+
+// NewLogResponder wraps the given Responder with logging.
+//
+//	prompt := prompts.NewLogResponder(perplexity.New(client), prompts.WithLogLevel(slog.LevelInfo, slog.LevelError))
+func NewLogResponder[I, O any](next Responder[I, O], opts ...LogOpt) *LogResponder[I, O] {
+	return &LogResponder[I, O]{next: next, opts: newLogOpts(opts...)}
+}
+
+// ProviderName returns the name of the provider of the wrapped Responder.
+func (l *LogResponder[I, O]) ProviderName() string {
+	return ProviderName(l.next)
+}
+
+// Respond sends the request with the wrapped Responder and logs the exchange.
+func (l *LogResponder[I, O]) Respond(ctx context.Context, in I) (O, error) {
+	if !l.opts.Logger.Enabled(ctx, min(l.opts.Level, l.opts.ErrorLevel)) {
+		return l.next.Respond(ctx, in)
+	}
+
+	start := time.Now()
+	out, err := l.next.Respond(ctx, in)
+
+	attrs := []slog.Attr{slog.Duration("duration", time.Since(start))}
+
+	payloads := l.opts.sample()
+	if payloads {
+		attrs = append(attrs, slog.String("request", l.opts.jsonPayload(in)))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		l.opts.Logger.LogAttrs(ctx, l.opts.ErrorLevel, "prompts: respond failed", attrs...)
+
+		return out, err
+	}
+
+	if payloads {
+		attrs = append(attrs, slog.String("response", l.opts.jsonPayload(out)))
+	}
+
+	l.opts.Logger.LogAttrs(ctx, l.opts.Level, "prompts: respond", attrs...)
+
+	return out, nil
+}
+
+// sample reports whether the payloads of an exchange are logged.
+func (o *LogOpts) sample() bool {
+	return o.PayloadSampleRate >= 1 || (o.PayloadSampleRate > 0 && rand.Float64() < o.PayloadSampleRate)
+}
+
+// jsonPayload returns the value as redacted and truncated JSON.
+func (o *LogOpts) jsonPayload(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+
+	return o.payload(b)
+}
+
+// payload returns the redacted and truncated payload.
+func (o *LogOpts) payload(b []byte) string {
+	s := string(bytes.TrimSpace(b))
+
+	for _, p := range o.RedactedPatterns {
+		s = p.ReplaceAllString(s, Redacted)
+	}
+
+	if o.MaxPayloadSize > 0 && len(s) > o.MaxPayloadSize {
+		n := o.MaxPayloadSize
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+
+		s = s[:n] + "...(truncated)"
+	}
+
+	return s
+}
+
+// redactHeader returns a copy of the header with the credentials redacted.
+// The authentication scheme (e.g. Bearer) is kept.
+func (o *LogOpts) redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+
+	for _, k := range o.RedactedHeaders {
+		k = http.CanonicalHeaderKey(k)
+
+		for i, v := range h[k] {
+			if scheme, _, ok := strings.Cut(v, " "); ok && k == "Authorization" {
+				h[k][i] = scheme + " " + Redacted
+				continue
+			}
+
+			h[k][i] = Redacted
+		}
+	}
+
+	return h
+}
+
+// redactURL returns the URL without credentials.
+func redactURL(u *url.URL) string {
+	r := *u
+	if r.User != nil {
+		r.User = url.User(Redacted)
+	}
+
+	q := r.Query()
+	for _, k := range redactedQuery {
+		if q.Has(k) {
+			q.Set(k, Redacted)
+		}
+	}
+
+	r.RawQuery = q.Encode()
+
+	return r.String()
+}
+
+// readRequestBody returns the body of a rewindable request without consuming it.
+func readRequestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	b, _ := io.ReadAll(body)
+
+	return b
+}
+
+// isStream reports whether the response is a stream of events.
+func isStream(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	return mediaType == "text/event-stream" || mediaType == "application/x-ndjson"
+}
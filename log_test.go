@@ -0,0 +1,209 @@
+package prompts_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+var email = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+
+// logs returns a logger that writes JSON records to the returned buffer.
+func logs() (*slog.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})), buf
+}
+
+func records(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	recs := []map[string]any{}
+	for line := range strings.Lines(buf.String()) {
+		rec := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(line), &rec))
+		recs = append(recs, rec)
+	}
+
+	return recs
+}
+
+func TestLogDoer(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []prompts.LogOpt
+		status      int
+		contentType string
+		body        string
+		level       string
+		reqBody     any
+		respBody    any
+	}{
+		{
+			name:        "success",
+			opts:        []prompts.LogOpt{prompts.WithRedactedPatterns(email)},
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"output":"mail jane@example.com"}`,
+			level:       "DEBUG",
+			reqBody:     `{"input":"Write to [REDACTED]"}`,
+			respBody:    `{"output":"mail [REDACTED]"}`,
+		},
+		{
+			name:        "error status",
+			status:      http.StatusTooManyRequests,
+			contentType: "application/json",
+			body:        `{"error":{"message":"rate limit exceeded"}}`,
+			level:       "ERROR",
+			reqBody:     `{"input":"Write to john@example.com"}`,
+			respBody:    `{"error":{"message":"rate limit exceeded"}}`,
+		},
+		{
+			name:        "truncated",
+			opts:        []prompts.LogOpt{prompts.WithMaxPayloadSize(10)},
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"output":"ok"}`,
+			level:       "DEBUG",
+			reqBody:     `{"input":"...(truncated)`,
+			respBody:    `{"output":...(truncated)`,
+		},
+		{
+			name:        "stream",
+			status:      http.StatusOK,
+			contentType: "text/event-stream",
+			body:        "data: {}\n\n",
+			level:       "DEBUG",
+			reqBody:     `{"input":"Write to john@example.com"}`,
+		},
+		{
+			name:        "not sampled",
+			opts:        []prompts.LogOpt{prompts.WithPayloadSampleRate(0)},
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"output":"ok"}`,
+			level:       "DEBUG",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, `{"input":"Write to john@example.com"}`, string(b))
+
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			logger, buf := logs()
+			doer := prompts.NewLogDoer(srv.Client(), append(tt.opts, prompts.WithLogger(logger))...)
+
+			c := prompts.NewClient().Doer(doer).Base(srv.URL).APIKey("sk-secret").Post("responses").BodyJSON(map[string]string{"input": "Write to john@example.com"})
+
+			resp, err := c.Request(context.Background())
+			require.NoError(t, err)
+
+			res, err := doer.Do(resp)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			b, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.Equal(t, tt.body, string(b))
+
+			require.NotContains(t, buf.String(), "sk-secret")
+
+			recs := records(t, buf)
+			require.Len(t, recs, 1)
+			require.Equal(t, tt.level, recs[0]["level"])
+			require.InDelta(t, tt.status, recs[0]["status"], 0)
+			require.Equal(t, tt.reqBody, recs[0]["request_body"])
+			require.Equal(t, tt.respBody, recs[0]["response_body"])
+			require.Equal(t, map[string]any{"Authorization": []any{"Bearer [REDACTED]"}, "Content-Type": []any{"application/json"}}, recs[0]["request_headers"])
+		})
+	}
+}
+
+func TestLogDoerDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	doer := prompts.NewLogDoer(srv.Client(), prompts.WithLogger(logger), prompts.WithLogLevel(slog.LevelDebug, slog.LevelDebug))
+
+	_, err := prompts.NewClient().Doer(doer).Base(srv.URL).ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+}
+
+type responderFunc func(ctx context.Context, in string) (string, error)
+
+func (f responderFunc) Respond(ctx context.Context, in string) (string, error) {
+	return f(ctx, in)
+}
+
+func TestLogResponder(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		err      error
+		level    string
+		msg      string
+		response any
+	}{
+		{
+			name:     "success",
+			level:    "INFO",
+			msg:      "prompts: respond",
+			response: `"Hello [REDACTED]"`,
+		},
+		{
+			name:  "error",
+			err:   errFailed,
+			level: "WARN",
+			msg:   "prompts: respond failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := logs()
+
+			next := responderFunc(func(_ context.Context, in string) (string, error) {
+				return "Hello " + in, tt.err
+			})
+
+			r := prompts.NewLogResponder(next,
+				prompts.WithLogger(logger),
+				prompts.WithLogLevel(slog.LevelInfo, slog.LevelWarn),
+				prompts.WithRedactedPatterns(email),
+			)
+
+			_, err := r.Respond(context.Background(), "jane@example.com")
+			require.ErrorIs(t, err, tt.err)
+
+			recs := records(t, buf)
+			require.Len(t, recs, 1)
+			require.Equal(t, tt.level, recs[0]["level"])
+			require.Equal(t, tt.msg, recs[0]["msg"])
+			require.Equal(t, `"[REDACTED]"`, recs[0]["request"])
+			require.Equal(t, tt.response, recs[0]["response"])
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package ollama
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Options are the model options of a native request.
+// See https://github.com/ollama/ollama/blob/main/docs/modelfile.md#valid-parameters-and-values for details.
+type Options struct {
+	// NumCtx is the size of the context window.
+	NumCtx *int `json:"num_ctx,omitempty"`
+	// NumPredict is the maximum number of tokens to predict.
+	NumPredict *int `json:"num_predict,omitempty"`
+	// NumKeep is the number of tokens to keep from the prompt.
+	NumKeep *int `json:"num_keep,omitempty"`
+	// NumGPU is the number of layers to offload to the GPU.
+	NumGPU *int `json:"num_gpu,omitempty"`
+	// Seed is the random number seed.
+	Seed *int `json:"seed,omitempty"`
+	// Temperature is the sampling temperature.
+	Temperature *float32 `json:"temperature,omitempty"`
+	// TopK is the number of top tokens to sample from.
+	TopK *int `json:"top_k,omitempty"`
+	// TopP is the nucleus sampling parameter.
+	TopP *float64 `json:"top_p,omitempty"`
+	// MinP is the minimum probability of a token relative to the most likely token.
+	MinP *float64 `json:"min_p,omitempty"`
+	// RepeatPenalty is the penalty of repetitions.
+	RepeatPenalty *float64 `json:"repeat_penalty,omitempty"`
+	// RepeatLastN is how far the model looks back to prevent repetitions.
+	RepeatLastN *int `json:"repeat_last_n,omitempty"`
+	// Mirostat enables Mirostat sampling (0 = disabled, 1 = Mirostat, 2 = Mirostat 2.0).
+	Mirostat *int `json:"mirostat,omitempty"`
+	// MirostatEta is the learning rate of Mirostat.
+	MirostatEta *float64 `json:"mirostat_eta,omitempty"`
+	// MirostatTau is the balance between coherence and diversity of Mirostat.
+	MirostatTau *float64 `json:"mirostat_tau,omitempty"`
+	// Stop are the stop sequences.
+	Stop []string `json:"stop,omitempty"`
+}
+
+// merge returns the options with all fields set in o overridden.
+func (opts Options) merge(o Options) Options {
+	override(&opts.NumCtx, o.NumCtx)
+	override(&opts.NumPredict, o.NumPredict)
+	override(&opts.NumKeep, o.NumKeep)
+	override(&opts.NumGPU, o.NumGPU)
+	override(&opts.Seed, o.Seed)
+	override(&opts.Temperature, o.Temperature)
+	override(&opts.TopK, o.TopK)
+	override(&opts.TopP, o.TopP)
+	override(&opts.MinP, o.MinP)
+	override(&opts.RepeatPenalty, o.RepeatPenalty)
+	override(&opts.RepeatLastN, o.RepeatLastN)
+	override(&opts.Mirostat, o.Mirostat)
+	override(&opts.MirostatEta, o.MirostatEta)
+	override(&opts.MirostatTau, o.MirostatTau)
+
+	if len(o.Stop) > 0 {
+		opts.Stop = o.Stop
+	}
+
+	return opts
+}
+
+// override sets dst to src if src is set.
+func override[T any](dst **T, src *T) {
+	if src != nil {
+		*dst = src
+	}
+}
+
+// KeepAlive is how long a model stays loaded after a request.
+// A negative duration keeps the model loaded forever and zero unloads it immediately.
+type KeepAlive time.Duration
+
+// MarshalJSON marshals the keep alive duration as a duration string.
+func (k KeepAlive) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(k).String())
+}
+
+// Message is a message of a native chat request.
+type Message struct {
+	// Role is the role of the message.
+	Role string `json:"role"`
+	// Content is the text of the message.
+	Content string `json:"content"`
+	// Thinking is the thinking of the model.
+	Thinking string `json:"thinking,omitempty"`
+	// Images are the base64 encoded images of the message.
+	Images []string `json:"images,omitempty"`
+	// ToolCalls are the tool calls of the model.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolName is the name of the tool of a tool result.
+	ToolName string `json:"tool_name,omitempty"`
+}
+
+// ToolCall is a tool call of the model.
+type ToolCall struct {
+	// Function is the called function.
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction is the function of a tool call.
+type ToolCallFunction struct {
+	// Index is the index of the tool call.
+	Index int `json:"index,omitempty"`
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Arguments is the JSON object of the arguments.
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// Tool is a tool the model may call.
+type Tool struct {
+	// Type is the type of the tool.
+	Type string `json:"type"`
+	// Function is the function of the tool.
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is the function of a tool.
+type ToolFunction struct {
+	// Name is the name of the function.
+	Name string `json:"name"`
+	// Description is the description of the function.
+	Description string `json:"description,omitempty"`
+	// Parameters is the JSON Schema of the parameters.
+	Parameters json.RawMessage `json:"parameters"`
+}
+
+// ChatRequest is the request of the /api/chat endpoint.
+type ChatRequest struct {
+	// Model is the model to use.
+	Model string `json:"model"`
+	// Messages is the list of messages of the conversation.
+	Messages []Message `json:"messages"`
+	// Tools is the list of tools the model may call.
+	Tools []Tool `json:"tools,omitempty"`
+	// Format is "json" or a JSON Schema of the output.
+	Format json.RawMessage `json:"format,omitempty"`
+	// Options are the model options.
+	Options *Options `json:"options,omitempty"`
+	// Stream is a flag to enable streaming. Ollama streams by default.
+	Stream *bool `json:"stream,omitempty"`
+	// KeepAlive is how long the model stays loaded after the request.
+	KeepAlive *KeepAlive `json:"keep_alive,omitempty"`
+	// Think is a flag to enable thinking of thinking models.
+	Think *bool `json:"think,omitempty"`
+}
+
+// Metrics are the timings and token counts of a finished request.
+type Metrics struct {
+	// TotalDuration is the time spent generating the response in nanoseconds.
+	TotalDuration int64 `json:"total_duration,omitempty"`
+	// LoadDuration is the time spent loading the model in nanoseconds.
+	LoadDuration int64 `json:"load_duration,omitempty"`
+	// PromptEvalCount is the number of tokens in the prompt.
+	PromptEvalCount int `json:"prompt_eval_count,omitempty"`
+	// PromptEvalDuration is the time spent evaluating the prompt in nanoseconds.
+	PromptEvalDuration int64 `json:"prompt_eval_duration,omitempty"`
+	// EvalCount is the number of tokens in the response.
+	EvalCount int `json:"eval_count,omitempty"`
+	// EvalDuration is the time spent generating the response in nanoseconds.
+	EvalDuration int64 `json:"eval_duration,omitempty"`
+}
+
+// ChatResponse is a response or a streamed chunk of the /api/chat endpoint.
+type ChatResponse struct {
+	// Model is the model that generated the response.
+	Model string `json:"model"`
+	// CreatedAt is the time the response was created.
+	CreatedAt time.Time `json:"created_at"`
+	// Message is the message or message delta of the model.
+	Message Message `json:"message"`
+	// Done is a flag to indicate that the response is complete.
+	Done bool `json:"done"`
+	// DoneReason is the reason why the response is complete (e.g. "stop" or "length").
+	DoneReason string `json:"done_reason,omitempty"`
+
+	Metrics
+}
+
+// GenerateRequest is the request of the /api/generate endpoint.
+type GenerateRequest struct {
+	// Model is the model to use.
+	Model string `json:"model"`
+	// Prompt is the prompt to generate a response for.
+	Prompt string `json:"prompt"`
+	// Suffix is the text after the response, e.g. for code completion.
+	Suffix string `json:"suffix,omitempty"`
+	// System is the system prompt.
+	System string `json:"system,omitempty"`
+	// Images are the base64 encoded images of the prompt.
+	Images []string `json:"images,omitempty"`
+	// Format is "json" or a JSON Schema of the output.
+	Format json.RawMessage `json:"format,omitempty"`
+	// Options are the model options.
+	Options *Options `json:"options,omitempty"`
+	// Stream is a flag to enable streaming. Ollama streams by default.
+	Stream *bool `json:"stream,omitempty"`
+	// Raw is a flag to send the prompt without applying the template.
+	Raw bool `json:"raw,omitempty"`
+	// KeepAlive is how long the model stays loaded after the request.
+	KeepAlive *KeepAlive `json:"keep_alive,omitempty"`
+	// Think is a flag to enable thinking of thinking models.
+	Think *bool `json:"think,omitempty"`
+}
+
+// GenerateResponse is a response or a streamed chunk of the /api/generate endpoint.
+type GenerateResponse struct {
+	// Model is the model that generated the response.
+	Model string `json:"model"`
+	// CreatedAt is the time the response was created.
+	CreatedAt time.Time `json:"created_at"`
+	// Response is the response or response delta of the model.
+	Response string `json:"response"`
+	// Thinking is the thinking or thinking delta of the model.
+	Thinking string `json:"thinking,omitempty"`
+	// Done is a flag to indicate that the response is complete.
+	Done bool `json:"done"`
+	// DoneReason is the reason why the response is complete (e.g. "stop" or "length").
+	DoneReason string `json:"done_reason,omitempty"`
+
+	Metrics
+}
@@ -0,0 +1,23 @@
+package ollama
+
+import (
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+type (
+	ChatCompletionRequest  = openai.ChatCompletionRequest
+	ChatCompletionResponse = openai.ChatCompletionResponse
+	ChatCompletionMessage  = openai.ChatCompletionMessage
+	ChatCompletionChunk    = openai.ChatCompletionChunk
+)
+
+// NewChatCompletions creates a new Prompter for the OpenAI-compatible Chat Completions API of Ollama.
+func NewChatCompletions(client *prompts.Client) prompts.Prompter[*ChatCompletionRequest, *ChatCompletionResponse] {
+	return openai.NewChatCompletions(client, openai.WithBaseURL(DefaultURL), openai.WithProviderName(providerName))
+}
+
+// NewChatCompletionsStreamer creates a new Streamer for the OpenAI-compatible Chat Completions API of Ollama.
+func NewChatCompletionsStreamer(client *prompts.Client) prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] {
+	return openai.NewChatCompletionsStreamer(client, openai.WithBaseURL(DefaultURL), openai.WithProviderName(providerName))
+}
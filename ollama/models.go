@@ -0,0 +1,304 @@
+package ollama
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+)
+
+// ModelDetails are the details of a model.
+type ModelDetails struct {
+	// ParentModel is the model the model is based on.
+	ParentModel string `json:"parent_model,omitempty"`
+	// Format is the file format of the model (e.g. "gguf").
+	Format string `json:"format,omitempty"`
+	// Family is the family of the model.
+	Family string `json:"family,omitempty"`
+	// Families are the families of the model.
+	Families []string `json:"families,omitempty"`
+	// ParameterSize is the number of parameters (e.g. "8.2B").
+	ParameterSize string `json:"parameter_size,omitempty"`
+	// QuantizationLevel is the quantization level (e.g. "Q4_K_M").
+	QuantizationLevel string `json:"quantization_level,omitempty"`
+}
+
+// Model is a locally available model.
+type Model struct {
+	// Name is the name of the model.
+	Name string `json:"name"`
+	// Model is the name of the model.
+	Model string `json:"model"`
+	// ModifiedAt is the time the model was last modified.
+	ModifiedAt time.Time `json:"modified_at"`
+	// Size is the size of the model in bytes.
+	Size int64 `json:"size"`
+	// Digest is the digest of the model.
+	Digest string `json:"digest"`
+	// Details are the details of the model.
+	Details ModelDetails `json:"details"`
+}
+
+// RunningModel is a model loaded into memory.
+type RunningModel struct {
+	// Name is the name of the model.
+	Name string `json:"name"`
+	// Model is the name of the model.
+	Model string `json:"model"`
+	// Size is the size of the model in bytes.
+	Size int64 `json:"size"`
+	// Digest is the digest of the model.
+	Digest string `json:"digest"`
+	// Details are the details of the model.
+	Details ModelDetails `json:"details"`
+	// ExpiresAt is the time the model is unloaded.
+	ExpiresAt time.Time `json:"expires_at"`
+	// SizeVRAM is the size of the model in the VRAM in bytes.
+	SizeVRAM int64 `json:"size_vram"`
+	// ContextLength is the size of the context window the model was loaded with.
+	ContextLength int `json:"context_length,omitempty"`
+}
+
+// ShowResponse is the information of a model.
+type ShowResponse struct {
+	// Modelfile is the Modelfile of the model.
+	Modelfile string `json:"modelfile,omitempty"`
+	// Parameters are the parameters of the model.
+	Parameters string `json:"parameters,omitempty"`
+	// Template is the prompt template of the model.
+	Template string `json:"template,omitempty"`
+	// System is the system prompt of the model.
+	System string `json:"system,omitempty"`
+	// License is the license of the model.
+	License string `json:"license,omitempty"`
+	// Details are the details of the model.
+	Details ModelDetails `json:"details"`
+	// ModelInfo is the metadata of the model (e.g. "general.architecture").
+	ModelInfo map[string]any `json:"model_info,omitempty"`
+	// Capabilities are the capabilities of the model (e.g. "completion", "tools" or "thinking").
+	Capabilities []string `json:"capabilities,omitempty"`
+	// ModifiedAt is the time the model was last modified.
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// ProgressResponse is a progress event of pulling or creating a model.
+type ProgressResponse struct {
+	// Status is the status of the operation (e.g. "pulling manifest" or "success").
+	Status string `json:"status"`
+	// Digest is the digest of the layer being downloaded.
+	Digest string `json:"digest,omitempty"`
+	// Total is the total size of the layer in bytes.
+	Total int64 `json:"total,omitempty"`
+	// Completed is the downloaded size of the layer in bytes.
+	Completed int64 `json:"completed,omitempty"`
+	// Error is the error of the operation.
+	Error string `json:"error,omitempty"`
+}
+
+// CreateRequest is the request to create a model.
+type CreateRequest struct {
+	// Model is the name of the model to create.
+	Model string `json:"model"`
+	// From is the name of the model to create the model from.
+	From string `json:"from,omitempty"`
+	// Files are the GGUF or safetensor files of the model by name and digest.
+	Files map[string]string `json:"files,omitempty"`
+	// Adapters are the LoRA adapter files of the model by name and digest.
+	Adapters map[string]string `json:"adapters,omitempty"`
+	// Template is the prompt template of the model.
+	Template string `json:"template,omitempty"`
+	// License is the license of the model.
+	License []string `json:"license,omitempty"`
+	// System is the system prompt of the model.
+	System string `json:"system,omitempty"`
+	// Parameters are the parameters of the model.
+	Parameters *Options `json:"parameters,omitempty"`
+	// Messages are the messages of the conversation of the model.
+	Messages []Message `json:"messages,omitempty"`
+	// Quantize is the quantization type of the model (e.g. "q4_K_M").
+	Quantize string `json:"quantize,omitempty"`
+}
+
+// Models is a client for the model management API of Ollama.
+type Models struct {
+	client *prompts.Client
+}
+
+// NewModels creates a new Models with the given client.
+//
+// Pulling and creating models can take far longer than the DefaultTimeout of
+// the prompts.DefaultClient, which limits the whole request including the
+// streamed body. Use a client without a total timeout and bound the
+// operations with the context instead.
+//
+//	models := ollama.NewModels(prompts.NewClient().Client(&http.Client{}))
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+//	defer cancel()
+//
+//	if err := models.Ensure(ctx, ollama.DefaultModel); err != nil {
+//		return err
+//	}
+func NewModels(client *prompts.Client, opts ...NativeOpt) *Models {
+	o := &NativeOpts{BaseURL: DefaultNativeURL}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Models{client: client.New().Base(o.BaseURL)}
+}
+
+// List returns the locally available models.
+func (m *Models) List(ctx context.Context) ([]Model, error) {
+	res := &struct {
+		Models []Model `json:"models"`
+	}{}
+
+	_, err := m.client.New().Get("api/tags").ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Models, nil
+}
+
+// Running returns the models loaded into memory.
+func (m *Models) Running(ctx context.Context) ([]RunningModel, error) {
+	res := &struct {
+		Models []RunningModel `json:"models"`
+	}{}
+
+	_, err := m.client.New().Get("api/ps").ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Models, nil
+}
+
+// Show returns the information of the model.
+func (m *Models) Show(ctx context.Context, model string) (*ShowResponse, error) {
+	res := &ShowResponse{}
+
+	_, err := m.client.New().Post("api/show").BodyJSON(&modelRequest{Model: model}).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Copy copies the source model to the destination model.
+func (m *Models) Copy(ctx context.Context, source, destination string) error {
+	body := struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+	}{
+		Source:      source,
+		Destination: destination,
+	}
+
+	_, err := m.client.New().Post("api/copy").BodyJSON(&body).ReceiveSuccess(ctx, nil)
+
+	return err
+}
+
+// Delete deletes the model.
+func (m *Models) Delete(ctx context.Context, model string) error {
+	_, err := m.client.New().Delete("api/delete").BodyJSON(&modelRequest{Model: model}).ReceiveSuccess(ctx, nil)
+
+	return err
+}
+
+// Pull pulls the model from the registry and returns the stream of progress events.
+// The pull is aborted by the timeout of the http client (see NewModels).
+//
+//	for p, err := range models.Pull(ctx, "qwen3:8b") {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Printf("%s %d/%d\n", p.Status, p.Completed, p.Total)
+//	}
+func (m *Models) Pull(ctx context.Context, model string) iter.Seq2[*ProgressResponse, error] {
+	c := m.client.New().Post("api/pull").BodyJSON(&modelRequest{Model: model, Stream: cast.Ptr(true)})
+
+	return progress(ctx, c)
+}
+
+// Create creates a model and returns the stream of progress events.
+// The creation is aborted by the timeout of the http client (see NewModels).
+func (m *Models) Create(ctx context.Context, req *CreateRequest) iter.Seq2[*ProgressResponse, error] {
+	body := struct {
+		*CreateRequest
+		Stream bool `json:"stream"`
+	}{
+		CreateRequest: req,
+		Stream:        true,
+	}
+
+	c := m.client.New().Post("api/create").BodyJSON(&body)
+
+	return progress(ctx, c)
+}
+
+// Ensure pulls the model if it is not available locally.
+// The pull is aborted by the timeout of the http client (see NewModels).
+func (m *Models) Ensure(ctx context.Context, model string) error {
+	_, err := m.Show(ctx, model)
+
+	var perr *prompts.PromptError
+	if !errors.As(err, &perr) || perr.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	for _, err := range m.Pull(ctx, model) {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// modelRequest is the request of the endpoints operating on a single model.
+type modelRequest struct {
+	Model  string `json:"model"`
+	Stream *bool  `json:"stream,omitempty"`
+}
+
+// ProgressError is the error of a failed pull or create operation.
+type ProgressError struct {
+	// Message is the error message.
+	Message string
+}
+
+// Error returns the error message.
+func (e *ProgressError) Error() string {
+	return "ollama: " + e.Message
+}
+
+// progress returns the stream of progress events of the request. Progress
+// events carrying an error are yielded as a *ProgressError.
+func progress(ctx context.Context, c *prompts.Client) iter.Seq2[*ProgressResponse, error] {
+	return func(yield func(*ProgressResponse, error) bool) {
+		for p, err := range prompts.ReceiveStream(ctx, c, prompts.NewNDJSONDecoder[ProgressResponse]()) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if p.Error != "" {
+				yield(nil, &ProgressError{Message: p.Error})
+				return
+			}
+
+			if !yield(&p, nil) {
+				return
+			}
+		}
+	}
+}
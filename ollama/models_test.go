@@ -0,0 +1,120 @@
+package ollama_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/ollama"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelsEnsure(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		pull   string
+		calls  []string
+		err    string
+	}{
+		{
+			name:   "present",
+			status: http.StatusOK,
+			calls:  []string{"POST /api/show"},
+		},
+		{
+			name:   "missing",
+			status: http.StatusNotFound,
+			pull:   "{\"status\":\"pulling manifest\"}\n{\"status\":\"downloading\",\"digest\":\"sha256:1\",\"total\":10,\"completed\":5}\n{\"status\":\"success\"}\n",
+			calls:  []string{"POST /api/show", "POST /api/pull"},
+		},
+		{
+			name:   "pull error",
+			status: http.StatusNotFound,
+			pull:   "{\"status\":\"pulling manifest\"}\n{\"error\":\"pull model manifest: file does not exist\"}\n",
+			calls:  []string{"POST /api/show", "POST /api/pull"},
+			err:    "ollama: pull model manifest: file does not exist",
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			calls:  []string{"POST /api/show"},
+			err:    "boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, r.Method+" "+r.URL.Path)
+
+				var body map[string]any
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				require.Equal(t, ollama.DefaultModel, body["model"])
+
+				switch r.URL.Path {
+				case "/api/show":
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(tt.status)
+
+					switch tt.status {
+					case http.StatusOK:
+						_, _ = w.Write([]byte(`{"details":{"family":"qwen3"},"capabilities":["completion","tools"]}`))
+					case http.StatusNotFound:
+						_, _ = w.Write([]byte(`{"error":"model 'qwen3:8b' not found"}`))
+					default:
+						_, _ = w.Write([]byte(`{"error":"boom"}`))
+					}
+				case "/api/pull":
+					require.Equal(t, true, body["stream"])
+					_, _ = w.Write([]byte(tt.pull))
+				}
+			}))
+			defer srv.Close()
+
+			models := ollama.NewModels(prompts.NewClient(), ollama.WithBaseURL(srv.URL))
+
+			err := models.Ensure(context.Background(), ollama.DefaultModel)
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestModelsList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/tags":
+			_, _ = w.Write([]byte(`{"models":[{"name":"qwen3:8b","model":"qwen3:8b","size":5200000000,"details":{"parameter_size":"8.2B"}}]}`))
+		case "/api/ps":
+			_, _ = w.Write([]byte(`{"models":[{"name":"qwen3:8b","model":"qwen3:8b","size_vram":5200000000}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	models := ollama.NewModels(prompts.NewClient(), ollama.WithBaseURL(srv.URL))
+
+	list, err := models.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "8.2B", list[0].Details.ParameterSize)
+
+	running, err := models.Running(context.Background())
+	require.NoError(t, err)
+	require.Len(t, running, 1)
+	require.Equal(t, int64(5200000000), running[0].SizeVRAM)
+}
@@ -0,0 +1,442 @@
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+// DefaultNativeURL is the default endpoint for the native Ollama API.
+const DefaultNativeURL = "http://localhost:11434/"
+
+// ErrUnsupported is returned when a request can not be translated to the native Ollama API.
+var ErrUnsupported = errors.New("ollama: unsupported")
+
+// ChatOptions are the Ollama specific options of native requests. They are
+// the default options of the provider and can be set per request as an
+// extension of the request, which overrides the options of the provider.
+//
+//	req := openai.NewResponseRequest(ollama.WithChatOptions(ollama.ChatOptions{Options: ollama.Options{NumCtx: cast.Ptr(32768)}}))
+//	res, err := prompt.Respond(ctx, req)
+type ChatOptions struct {
+	// Options are the model options (e.g. num_ctx or seed).
+	Options Options
+	// KeepAlive is how long the model stays loaded after a request.
+	KeepAlive *KeepAlive
+	// Think is a flag to enable thinking of thinking models.
+	Think *bool
+}
+
+var _ openai.Extension = ChatOptions{}
+
+// Provider returns the name of the provider of the options.
+func (ChatOptions) Provider() string {
+	return providerName
+}
+
+// merge returns the options with all fields set in o overridden.
+func (c ChatOptions) merge(o ChatOptions) ChatOptions {
+	c.Options = c.Options.merge(o.Options)
+	override(&c.KeepAlive, o.KeepAlive)
+	override(&c.Think, o.Think)
+
+	return c
+}
+
+// WithChatOptions sets the options of a single native request.
+func WithChatOptions(opts ChatOptions) openai.RequestOpt {
+	return openai.WithExtensions(opts)
+}
+
+// NativeOpts are the options of the native Ollama provider.
+type NativeOpts struct {
+	// BaseURL is the endpoint of the API.
+	BaseURL string
+	// ChatOptions are the default options of all requests.
+	ChatOptions ChatOptions
+}
+
+// NativeOpt is a function type for configuring the native Ollama provider.
+type NativeOpt func(*NativeOpts)
+
+// WithBaseURL sets the endpoint of the API.
+func WithBaseURL(u string) NativeOpt {
+	return func(o *NativeOpts) {
+		o.BaseURL = u
+	}
+}
+
+// WithOptions sets the default model options of all requests.
+func WithOptions(opts Options) NativeOpt {
+	return func(o *NativeOpts) {
+		o.ChatOptions.Options = opts
+	}
+}
+
+// WithKeepAlive sets how long the model stays loaded after a request.
+func WithKeepAlive(k KeepAlive) NativeOpt {
+	return func(o *NativeOpts) {
+		o.ChatOptions.KeepAlive = &k
+	}
+}
+
+// WithThink enables or disables thinking of thinking models.
+func WithThink(think bool) NativeOpt {
+	return func(o *NativeOpts) {
+		o.ChatOptions.Think = &think
+	}
+}
+
+// Native is a struct that implements the Prompter interface for the native Ollama API.
+type Native[I *ResponseRequest, O *Response] struct {
+	client *prompts.Client
+	opts   ChatOptions
+}
+
+var _ prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] = (*Native[*ResponseRequest, *Response])(nil)
+
+// NewNative creates a new Ollama with the given client that uses the native /api/chat endpoint.
+//
+//	prompt := ollama.NewNative(prompts.NewClient(), ollama.WithOptions(ollama.Options{NumCtx: cast.Ptr(8192)}))
+func NewNative(client *prompts.Client, opts ...NativeOpt) prompts.Prompter[*ResponseRequest, *Response] {
+	return newNative(client, opts...)
+}
+
+// NewNativeStreamer creates a new Ollama with the given client that streams responses from the native /api/chat endpoint.
+func NewNativeStreamer(client *prompts.Client, opts ...NativeOpt) prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] {
+	return newNative(client, opts...)
+}
+
+func newNative(client *prompts.Client, opts ...NativeOpt) *Native[*ResponseRequest, *Response] {
+	o := &NativeOpts{BaseURL: DefaultNativeURL}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Native[*ResponseRequest, *Response]{client: client.New().Base(o.BaseURL), opts: o.ChatOptions}
+}
+
+// ProviderName returns the name of the provider.
+func (p *Native[I, O]) ProviderName() string {
+	return providerName
+}
+
+// Respond translates the request into a native chat request, sends it and
+// maps the message back into a response.
+func (p *Native[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	body, err := p.chatRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	body.Stream = cast.Ptr(false)
+
+	res := &ChatResponse{}
+
+	_, err = p.client.New().Post("api/chat").BodyJSON(body).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewResponse(res), nil
+}
+
+// Stream translates the request into a native chat request and returns the
+// stream of events. The NDJSON chunks are emitted as reasoning and text deltas,
+// followed by a completed event with the full response. A stream that ends
+// before the final chunk yields io.ErrUnexpectedEOF.
+func (p *Native[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ResponseStreamEvent, error] {
+	return func(yield func(*ResponseStreamEvent, error) bool) {
+		body, err := p.chatRequest(req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		body.Stream = cast.Ptr(true)
+
+		c := p.client.New().Post("api/chat").BodyJSON(body)
+
+		var b openai.ResponseStreamBuilder
+
+		if !yield(b.Created(body.Model), nil) {
+			return
+		}
+
+		acc := &ChatResponse{}
+
+		for chunk, err := range prompts.ReceiveStream(ctx, c, prompts.NewNDJSONDecoder[ChatResponse]()) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			acc.Message.Thinking += chunk.Message.Thinking
+			acc.Message.Content += chunk.Message.Content
+			acc.Message.ToolCalls = append(acc.Message.ToolCalls, chunk.Message.ToolCalls...)
+
+			if chunk.Message.Thinking != "" && !yield(b.ReasoningDelta(chunk.Message.Thinking), nil) {
+				return
+			}
+
+			if chunk.Message.Content != "" && !yield(b.TextDelta(chunk.Message.Content), nil) {
+				return
+			}
+
+			if !chunk.Done {
+				continue
+			}
+
+			acc.Model = chunk.Model
+			acc.CreatedAt = chunk.CreatedAt
+			acc.Done = true
+			acc.DoneReason = chunk.DoneReason
+			acc.Metrics = chunk.Metrics
+
+			yield(b.Done(NewResponse(acc)), nil)
+
+			return
+		}
+
+		yield(nil, io.ErrUnexpectedEOF)
+	}
+}
+
+// Generate sends a request to the /api/generate endpoint and returns the response.
+func (p *Native[I, O]) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	body := *req
+	body.Stream = cast.Ptr(false)
+
+	res := &GenerateResponse{}
+
+	_, err := p.client.New().Post("api/generate").BodyJSON(&body).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GenerateStream sends a request to the /api/generate endpoint and returns the stream of chunks.
+func (p *Native[I, O]) GenerateStream(ctx context.Context, req *GenerateRequest) iter.Seq2[*GenerateResponse, error] {
+	body := *req
+	body.Stream = cast.Ptr(true)
+
+	c := p.client.New().Post("api/generate").BodyJSON(&body)
+
+	return func(yield func(*GenerateResponse, error) bool) {
+		for chunk, err := range prompts.ReceiveStream(ctx, c, prompts.NewNDJSONDecoder[GenerateResponse]()) {
+			if !yield(&chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// chatRequest returns the native chat request with the options of the
+// provider and the request.
+func (p *Native[I, O]) chatRequest(req *ResponseRequest) (*ChatRequest, error) {
+	body, err := NewChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := p.opts.merge(ChatOptions{Options: *body.Options})
+	if o, ok := openai.ExtensionFor[ChatOptions](req.Extensions); ok {
+		opts = opts.merge(o)
+	}
+
+	body.Options = &opts.Options
+	body.KeepAlive = opts.KeepAlive
+	body.Think = opts.Think
+
+	if body.Model == "" {
+		body.Model = DefaultModel
+	}
+
+	return body, nil
+}
+
+// NewChatRequest translates a response request into a native chat request.
+// Instructions become a system message, function calls and their outputs
+// become tool calls and tool messages. The sampling parameters of the
+// request are set as model options.
+func NewChatRequest(req *ResponseRequest) (*ChatRequest, error) {
+	body := &ChatRequest{
+		Model: req.Model,
+		Options: &Options{
+			Temperature: req.Temperature,
+			TopP:        req.TopP,
+			TopK:        req.TopK,
+			NumPredict:  req.MaxTokens,
+		},
+	}
+
+	if req.Instructions != "" {
+		body.Messages = append(body.Messages, Message{Role: string(openai.RoleSystem), Content: req.Instructions})
+	}
+
+	names := map[string]string{}
+
+	for _, input := range req.Input {
+		switch item := input.Item.(type) {
+		case nil:
+			msg, err := newMessage(input)
+			if err != nil {
+				return nil, err
+			}
+
+			body.Messages = append(body.Messages, msg)
+		case openai.ResponseInputFunctionCallOutput:
+			body.Messages = append(body.Messages, Message{Role: RoleTool.String(), Content: item.Output, ToolName: names[item.CallID]})
+		case openai.ResponseOutputFunctionCall:
+			names[item.CallID] = item.Name
+
+			args := json.RawMessage(item.Arguments)
+			if len(strings.TrimSpace(item.Arguments)) == 0 {
+				args = json.RawMessage("{}")
+			}
+
+			call := ToolCall{Function: ToolCallFunction{Name: item.Name, Arguments: args}}
+
+			// parallel function calls belong to the same assistant message.
+			if n := len(body.Messages); n > 0 && len(body.Messages[n-1].ToolCalls) > 0 {
+				call.Function.Index = len(body.Messages[n-1].ToolCalls)
+				body.Messages[n-1].ToolCalls = append(body.Messages[n-1].ToolCalls, call)
+
+				continue
+			}
+
+			body.Messages = append(body.Messages, Message{Role: string(openai.RoleAssistant), ToolCalls: []ToolCall{call}})
+		case openai.ResponseOutputMessage:
+			body.Messages = append(body.Messages, Message{Role: string(openai.RoleAssistant), Content: item.Text()})
+		case openai.ResponseOutputReasoning:
+			continue
+		default:
+			return nil, fmt.Errorf("%w: input item %T", ErrUnsupported, item)
+		}
+	}
+
+	if req.ToolChoice != openai.ToolChoiceNone {
+		for _, t := range req.Tools {
+			fn, ok := t.Tool.(openai.ResponseFunctionTool)
+			if !ok {
+				return nil, fmt.Errorf("%w: tool %T", ErrUnsupported, t.Tool)
+			}
+
+			params, err := json.Marshal(fn.Function.Parameters)
+			if err != nil {
+				return nil, err
+			}
+
+			body.Tools = append(body.Tools, Tool{
+				Type:     "function",
+				Function: ToolFunction{Name: fn.Function.Name, Description: fn.Function.Description, Parameters: params},
+			})
+		}
+	}
+
+	if req.Text != nil && req.Text.Format != nil {
+		switch req.Text.Format.Type {
+		case openai.ResponseTextFormatJSONSchema:
+			body.Format = req.Text.Format.Schema
+		case openai.ResponseTextFormatJSONObject:
+			body.Format = json.RawMessage(`"json"`)
+		default:
+		}
+	}
+
+	return body, nil
+}
+
+// newMessage translates an input message into a native message.
+func newMessage(input ResponseInput) (Message, error) {
+	role := input.Role
+	if role == openai.RoleDeveloper {
+		role = openai.RoleSystem
+	}
+
+	msg := Message{Role: role.String()}
+	texts := []string{}
+
+	for _, c := range input.Content {
+		switch content := c.Content.(type) {
+		case openai.ResponseMessageContentText:
+			texts = append(texts, content.Text)
+		case openai.ResponseMessageContentImage:
+			if content.Image.Base64 == "" {
+				return Message{}, fmt.Errorf("%w: images must be base64 encoded", ErrUnsupported)
+			}
+
+			msg.Images = append(msg.Images, content.Image.Base64)
+		default:
+			return Message{}, fmt.Errorf("%w: content %T", ErrUnsupported, content)
+		}
+	}
+
+	msg.Content = strings.Join(texts, "\n")
+
+	return msg, nil
+}
+
+// NewResponse maps a native chat response into a response. The thinking
+// becomes a reasoning item, the content a message and the tool calls
+// function calls.
+func NewResponse(res *ChatResponse) *Response {
+	r := &Response{
+		Object: "response",
+		Model:  res.Model,
+		Status: openai.ResponseStatusCompleted,
+		Usage: &openai.ResponseUsage{
+			InputTokens:  res.PromptEvalCount,
+			OutputTokens: res.EvalCount,
+			TotalTokens:  res.PromptEvalCount + res.EvalCount,
+		},
+	}
+
+	if !res.CreatedAt.IsZero() {
+		r.CreatedAt = res.CreatedAt.Unix()
+	}
+
+	if res.Message.Thinking != "" {
+		r.Output = append(r.Output, openai.ResponseOutput{Output: openai.ResponseOutputReasoning{
+			Summary: []openai.ResponseOutputReasoningText{},
+			Content: []openai.ResponseOutputReasoningText{{Type: "reasoning_text", Text: res.Message.Thinking}},
+		}})
+	}
+
+	if res.Message.Content != "" {
+		r.Output = append(r.Output, openai.ResponseOutput{Output: openai.ResponseOutputMessage{
+			Role:   openai.RoleAssistant,
+			Status: openai.ResponseStatusCompleted,
+			ResponseOutputMessageContent: []openai.ResponseOutputMessageContent{
+				{Content: openai.ResponseOutputMessageContentText{Text: res.Message.Content}},
+			},
+		}})
+	}
+
+	for i, call := range res.Message.ToolCalls {
+		id := fmt.Sprintf("call_%d", i)
+
+		r.Output = append(r.Output, openai.ResponseOutput{Output: openai.ResponseOutputFunctionCall{
+			ID:        id,
+			CallID:    id,
+			Status:    openai.ResponseStatusCompleted,
+			Name:      call.Function.Name,
+			Arguments: string(call.Function.Arguments),
+		}})
+	}
+
+	if res.DoneReason == "length" {
+		r.Status = openai.ResponseStatusIncomplete
+		r.IncompleteDetails = &openai.ResponseIncompleteDetails{Reason: openai.IncompleteReasonMaxOutputTokens}
+	}
+
+	return r
+}
@@ -0,0 +1,144 @@
+package ollama_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/ollama"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNativeRespond(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/chat", r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, map[string]any{
+			"model": "qwen3:8b",
+			"messages": []any{
+				map[string]any{"role": "system", "content": "Be brief."},
+				map[string]any{"role": "user", "content": "Weather?"},
+				map[string]any{"role": "assistant", "content": "", "tool_calls": []any{
+					map[string]any{"function": map[string]any{"name": "weather", "arguments": map[string]any{"city": "Berlin"}}},
+				}},
+				map[string]any{"role": "tool", "content": "sunny", "tool_name": "weather"},
+			},
+			"format":     map[string]any{"type": "object"},
+			"options":    map[string]any{"num_ctx": float64(32768), "seed": float64(42), "temperature": 0.5},
+			"stream":     false,
+			"keep_alive": "10m0s",
+		}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"model": "qwen3:8b",
+			"created_at": "2025-01-01T00:00:00Z",
+			"message": {"role": "assistant", "content": "Sunny.", "thinking": "Easy."},
+			"done": true,
+			"done_reason": "stop",
+			"prompt_eval_count": 12,
+			"eval_count": 3
+		}`))
+	}))
+	defer srv.Close()
+
+	p := ollama.NewNative(prompts.NewClient(),
+		ollama.WithBaseURL(srv.URL),
+		ollama.WithOptions(ollama.Options{NumCtx: cast.Ptr(8192), Seed: cast.Ptr(42)}),
+		ollama.WithKeepAlive(ollama.KeepAlive(10*time.Minute)),
+	)
+
+	req := openai.NewResponseRequest(
+		openai.WithInstructions("Be brief."),
+		openai.WithInput(
+			openai.NewInputMessage(openai.RoleUser, "Weather?"),
+			openai.ResponseInput{Item: openai.ResponseOutputFunctionCall{CallID: "call_0", Name: "weather", Arguments: `{"city":"Berlin"}`}},
+			openai.NewFunctionCallOutput("call_0", "sunny"),
+		),
+		openai.WithTextFormat(openai.ResponseTextFormat{Type: openai.ResponseTextFormatJSONSchema, Schema: json.RawMessage(`{"type":"object"}`)}),
+		ollama.WithChatOptions(ollama.ChatOptions{Options: ollama.Options{NumCtx: cast.Ptr(32768)}}),
+	)
+	req.Temperature = cast.Ptr[float32](0.5)
+
+	res, err := p.Respond(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "Sunny.", res.OutputText())
+	require.Len(t, res.Output, 2)
+
+	reasoning, ok := res.Output[0].GetReasoning()
+	require.True(t, ok)
+	require.Equal(t, "Easy.", reasoning.Content[0].Text)
+	require.Equal(t, 15, res.Usage.TotalTokens)
+}
+
+func TestNativeStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/x-ndjson", r.Header.Get("Accept"))
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"model":"qwen3:8b","message":{"role":"assistant","thinking":"Greet."},"done":false}
+{"model":"qwen3:8b","message":{"role":"assistant","content":"Hel"},"done":false}
+{"model":"qwen3:8b","message":{"role":"assistant","content":"lo"},"done":false}
+{"model":"qwen3:8b","message":{"role":"assistant","content":""},"done":true,"done_reason":"length","eval_count":2}
+`))
+	}))
+	defer srv.Close()
+
+	p := ollama.NewNativeStreamer(prompts.NewClient(), ollama.WithBaseURL(srv.URL))
+
+	var types []openai.ResponseStreamEventType
+	var text string
+	var res openai.Response
+
+	for e, err := range p.Stream(context.Background(), openai.NewResponseRequest()) {
+		require.NoError(t, err)
+		types = append(types, e.Type)
+
+		if delta, ok := e.GetTextDelta(); ok && e.Type == openai.ResponseStreamEventTypeOutputTextDelta {
+			require.Equal(t, 1, delta.OutputIndex)
+			text += delta.Delta
+		}
+
+		if r, ok := e.GetResponse(); ok {
+			res = r.Response
+		}
+	}
+
+	require.Equal(t, []openai.ResponseStreamEventType{
+		openai.ResponseStreamEventTypeCreated,
+		openai.ResponseStreamEventTypeReasoningTextDelta,
+		openai.ResponseStreamEventTypeOutputTextDelta,
+		openai.ResponseStreamEventTypeOutputTextDelta,
+		openai.ResponseStreamEventTypeIncomplete,
+	}, types)
+	require.Equal(t, "Hello", text)
+	require.Equal(t, "Hello", res.OutputText())
+	require.True(t, res.Truncated())
+}
+
+func TestNativeStreamUnexpectedEOF(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"model":"qwen3:8b","message":{"role":"assistant","content":"Hel"},"done":false}
+`))
+	}))
+	defer srv.Close()
+
+	p := ollama.NewNativeStreamer(prompts.NewClient(), ollama.WithBaseURL(srv.URL))
+
+	var last error
+	for _, err := range p.Stream(context.Background(), openai.NewResponseRequest()) {
+		last = err
+	}
+
+	require.ErrorIs(t, last, io.ErrUnexpectedEOF)
+}
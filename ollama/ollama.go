@@ -1,27 +1,31 @@
-package perplexity
+package ollama
 
 import (
 	"context"
+	"iter"
 
 	"github.com/katallaxie/prompts"
 	"github.com/katallaxie/prompts/openai"
 )
 
 type (
-	ResponseRequest            = openai.ResponseRequest
-	Response                   = openai.Response
-	ResponseInput              = openai.ResponseInput
-	ResponseTool               = openai.ResponseTool
-	ResponseMessageContent     = openai.ResponseMessageContent
-	ResponseMessageContentText = openai.ResponseMessageContentText
-	ResponseFunctionTool       = openai.ResponseFunctionTool
-	ResponseFunctionDefinition = openai.ResponseFunctionDefinition
-	ResponseFunctionParameters = openai.ResponseFunctionParameters
-	ResponseFunctionProperties = openai.ResponseFunctionProperties
-	Role                       = openai.Role
+	ResponseRequest             = openai.ResponseRequest
+	Response                    = openai.Response
+	ResponseInput               = openai.ResponseInput
+	ResponseTool                = openai.ResponseTool
+	ResponseMessageContent      = openai.ResponseMessageContent
+	ResponseMessageContentText  = openai.ResponseMessageContentText
+	ResponseMessageContentImage = openai.ResponseMessageContentImage
+	ResponseMessageContentFile  = openai.ResponseMessageContentFile
+	ResponseFunctionTool        = openai.ResponseFunctionTool
+	ResponseFunctionDefinition  = openai.ResponseFunctionDefinition
+	ResponseFunctionParameters  = openai.ResponseFunctionParameters
+	ResponseFunctionProperties  = openai.ResponseFunctionProperties
+	ResponseStreamEvent         = openai.ResponseStreamEvent
+	Role                        = openai.Role
 )
 
-// Role constants for the Perplexity API.
+// Role constants for the Ollama API.
 const (
 	RoleAgent     Role = "agent"
 	RoleNone      Role = "none"
@@ -36,18 +40,40 @@ type Ollama[I *ResponseRequest, O *Response] struct {
 	client *prompts.Client
 }
 
+var _ prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] = (*Ollama[*ResponseRequest, *Response])(nil)
+
 // New creates a new Ollama with the given client.
 func New(client *prompts.Client) prompts.Prompter[*ResponseRequest, *Response] {
+	return newOllama(client)
+}
+
+// NewStreamer creates a new Ollama with the given client that streams responses.
+func NewStreamer(client *prompts.Client) prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] {
+	return newOllama(client)
+}
+
+func newOllama(client *prompts.Client) *Ollama[*ResponseRequest, *Response] {
 	base := client.New().Base(DefaultURL)
 
 	return &Ollama[*ResponseRequest, *Response]{client: base}
 }
 
+// ProviderName returns the name of the provider.
+func (p *Ollama[I, O]) ProviderName() string {
+	return providerName
+}
+
 // Respond sends a chat completion request and returns the response.
 func (p *Ollama[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	body := *req
+	if err := body.Validate(); err != nil {
+		return nil, err
+	}
+	body.Stream = false
+
 	res := &Response{}
 
-	_, err := p.client.New().Post("responses").BodyJSON(req).ReceiveSuccess(ctx, res)
+	_, err := p.client.New().Post("responses").BodyJSON(&body).ReceiveSuccess(ctx, res)
 	if err != nil {
 		return nil, err
 	}
@@ -55,6 +81,29 @@ func (p *Ollama[I, O]) Respond(ctx context.Context, req I) (O, error) {
 	return res, nil
 }
 
+// Stream sends a chat completion request and returns the stream of events.
+func (p *Ollama[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ResponseStreamEvent, error] {
+	return func(yield func(*ResponseStreamEvent, error) bool) {
+		body := *req
+		if err := body.Validate(); err != nil {
+			yield(nil, err)
+			return
+		}
+		body.Stream = true
+
+		c := p.client.New().Post("responses").BodyJSON(&body)
+
+		for e, err := range openai.DecodeResponseStream(prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder())) {
+			if !yield(e, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// providerName is the name of the provider reported to middleware.
+const providerName = "ollama"
+
 // DefaultURL is the default endpoint for the Ollama API.
 const DefaultURL = "http://localhost:11434/v1/"
 
@@ -0,0 +1,44 @@
+package ollama_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/ollama"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+type doer func(*http.Request) (*http.Response, error)
+
+func (d doer) Do(r *http.Request) (*http.Response, error) {
+	return d(r)
+}
+
+func TestUnsupported(t *testing.T) {
+	client := prompts.NewClient().Doer(doer(func(*http.Request) (*http.Response, error) {
+		t.Error("unexpected request")
+		return nil, http.ErrHandlerTimeout
+	}))
+
+	req := openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "Hello")))
+	req.TopK = cast.Ptr(40)
+
+	_, err := ollama.New(client).Respond(context.Background(), req)
+	require.ErrorIs(t, err, openai.ErrUnsupported)
+
+	for _, err := range ollama.NewStreamer(client).Stream(context.Background(), req) {
+		require.ErrorIs(t, err, openai.ErrUnsupported)
+	}
+}
+
+func TestProviderName(t *testing.T) {
+	client := prompts.NewClient()
+
+	require.Equal(t, "ollama", prompts.ProviderName(ollama.New(client)))
+	require.Equal(t, "ollama", prompts.ProviderName(ollama.NewNative(client)))
+	require.Equal(t, "ollama", prompts.ProviderName(ollama.NewChatCompletions(client)))
+}
@@ -0,0 +1,121 @@
+package openai
+
+import (
+	"context"
+	"time"
+
+	"github.com/katallaxie/prompts"
+)
+
+// DefaultPollInterval is the default interval of polling background responses.
+const DefaultPollInterval = 2 * time.Second
+
+// Poller submits requests as background responses and polls them until they
+// are done. Every request is short, so long running responses (e.g. deep
+// research) are not limited by the timeout of the HTTP client.
+//
+//	poller := openai.NewPoller(openai.New(client), openai.NewStore(client))
+//	res, err := poller.Respond(ctx, req)
+type Poller struct {
+	prompter prompts.Prompter[*ResponseRequest, *Response]
+	store    Store
+	interval time.Duration
+}
+
+var _ prompts.Prompter[*ResponseRequest, *Response] = (*Poller)(nil)
+
+// PollerOpt is a function type for configuring the Poller.
+type PollerOpt func(*Poller)
+
+// WithPollInterval sets the interval of polling background responses.
+func WithPollInterval(d time.Duration) PollerOpt {
+	return func(p *Poller) {
+		p.interval = d
+	}
+}
+
+// NewPoller creates a new Poller that submits requests with the prompter and polls them with the store.
+func NewPoller(prompter prompts.Prompter[*ResponseRequest, *Response], store Store, opts ...PollerOpt) *Poller {
+	p := &Poller{prompter: prompter, store: store, interval: DefaultPollInterval}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// ProviderName returns the name of the provider of the prompter.
+func (p *Poller) ProviderName() string {
+	return prompts.ProviderName(p.prompter)
+}
+
+// Submit submits the request as a background response and returns its ID.
+func (p *Poller) Submit(ctx context.Context, req *ResponseRequest) (string, error) {
+	res, err := p.submit(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return res.ID, nil
+}
+
+// Wait polls the background response with the given ID until it is done.
+// If the context is cancelled, the background response is cancelled as well
+// and the error of the context is returned.
+func (p *Poller) Wait(ctx context.Context, id string) (*Response, error) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, p.cancel(ctx, id)
+		case <-ticker.C:
+		}
+
+		res, err := p.store.GetResponse(ctx, id)
+		if err != nil && ctx.Err() != nil {
+			return nil, p.cancel(ctx, id)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if res.Status.Done() {
+			return res, nil
+		}
+	}
+}
+
+// cancel cancels the response of a done context and returns the error of the context.
+func (p *Poller) cancel(ctx context.Context, id string) error {
+	_, _ = p.store.CancelResponse(context.WithoutCancel(ctx), id)
+
+	return ctx.Err()
+}
+
+// Respond submits the request as a background response and waits until it is done.
+func (p *Poller) Respond(ctx context.Context, req *ResponseRequest) (*Response, error) {
+	res, err := p.submit(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Status.Done() {
+		return res, nil
+	}
+
+	return p.Wait(ctx, res.ID)
+}
+
+// submit sends the request in the background. Background responses have to be stored.
+func (p *Poller) submit(ctx context.Context, req *ResponseRequest) (*Response, error) {
+	body := *req
+	body.Background = true
+
+	store := true
+	body.Store = &store
+
+	return p.prompter.Respond(ctx, &body)
+}
@@ -0,0 +1,129 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoller(t *testing.T) {
+	var polls, cancels atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/responses":
+			req := &openai.ResponseRequest{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+			require.True(t, req.Background)
+			require.True(t, *req.Store)
+
+			_, _ = w.Write([]byte(`{"id":"resp_1","status":"queued","background":true}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/responses/resp_1":
+			switch polls.Add(1) {
+			case 1:
+				_, _ = w.Write([]byte(`{"id":"resp_1"}`))
+				return
+			case 2:
+				_, _ = w.Write([]byte(`{"id":"resp_1","status":"in_progress"}`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"id":"resp_1","status":"completed"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/responses/resp_1/cancel":
+			cancels.Add(1)
+			_, _ = w.Write([]byte(`{"id":"resp_1","status":"cancelled"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := prompts.NewClient()
+	poller := openai.NewPoller(
+		openai.New(client, openai.WithBaseURL(srv.URL)),
+		openai.NewStore(client, openai.WithBaseURL(srv.URL)),
+		openai.WithPollInterval(time.Millisecond),
+	)
+
+	res, err := poller.Respond(context.Background(), openai.NewResponseRequest())
+	require.NoError(t, err)
+	require.Equal(t, openai.ResponseStatusCompleted, res.Status)
+	require.Equal(t, int32(3), polls.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = poller.Wait(ctx, "resp_1")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, int32(1), cancels.Load())
+}
+
+func TestPollerCancelDuringPoll(t *testing.T) {
+	var cancels atomic.Int32
+	polling := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/responses/resp_1":
+			close(polling)
+			<-r.Context().Done()
+		case r.Method == http.MethodPost && r.URL.Path == "/responses/resp_1/cancel":
+			cancels.Add(1)
+			_, _ = w.Write([]byte(`{"id":"resp_1","status":"cancelled"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client := prompts.NewClient()
+	poller := openai.NewPoller(
+		openai.New(client, openai.WithBaseURL(srv.URL)),
+		openai.NewStore(client, openai.WithBaseURL(srv.URL)),
+		openai.WithPollInterval(time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-polling
+		cancel()
+	}()
+
+	_, err := poller.Wait(ctx, "resp_1")
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, int32(1), cancels.Load())
+}
+
+func TestStreamResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/responses/resp_1", r.URL.Path)
+		require.Equal(t, "starting_after=2&stream=true", r.URL.RawQuery)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"type\":\"response.output_text.delta\",\"sequence_number\":3,\"delta\":\"lo\"}\n\n" +
+			"data: {\"type\":\"response.completed\",\"sequence_number\":4,\"response\":{\"id\":\"resp_1\",\"status\":\"completed\"}}\n\n"))
+	}))
+	defer srv.Close()
+
+	s := openai.NewStore(prompts.NewClient(), openai.WithBaseURL(srv.URL))
+
+	seq := []int{}
+	for e, err := range s.StreamResponse(context.Background(), "resp_1", 2) {
+		require.NoError(t, err)
+		seq = append(seq, e.SequenceNumber)
+	}
+
+	require.Equal(t, []int{3, 4}, seq)
+}
@@ -0,0 +1,540 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"iter"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/katallaxie/prompts"
+)
+
+// ChatCompletionMessage is a message of a chat completion request.
+type ChatCompletionMessage struct {
+	// Role is the role of the message sender.
+	Role Role
+	// Content is the text of the message.
+	Content string
+	// Parts is the multimodal content of the message. If set, it is sent instead of Content.
+	Parts []ChatCompletionContentPart
+	// Name is the name of the participant.
+	Name string
+	// ToolCalls is the tool calls of an assistant message.
+	ToolCalls []ChatCompletionMessageToolCall
+	// ToolCallID is the ID of the tool call a tool message responds to.
+	ToolCallID string
+}
+
+// MarshalJSON marshals the chat completion message into JSON.
+func (m ChatCompletionMessage) MarshalJSON() ([]byte, error) {
+	var content any = m.Content
+	if len(m.Parts) > 0 {
+		content = m.Parts
+	}
+
+	// assistant messages with tool calls may omit the content.
+	if m.Content == "" && len(m.Parts) == 0 && len(m.ToolCalls) > 0 {
+		content = nil
+	}
+
+	return json.Marshal(struct {
+		Role       Role                            `json:"role"`
+		Content    any                             `json:"content"`
+		Name       string                          `json:"name,omitempty"`
+		ToolCalls  []ChatCompletionMessageToolCall `json:"tool_calls,omitempty"`
+		ToolCallID string                          `json:"tool_call_id,omitempty"`
+	}{
+		Role:       m.Role,
+		Content:    content,
+		Name:       m.Name,
+		ToolCalls:  m.ToolCalls,
+		ToolCallID: m.ToolCallID,
+	})
+}
+
+// UnmarshalJSON unmarshals the chat completion message from JSON.
+// The content may either be a string or a list of content parts.
+func (m *ChatCompletionMessage) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Role       Role                            `json:"role"`
+		Content    json.RawMessage                 `json:"content"`
+		Name       string                          `json:"name,omitempty"`
+		ToolCalls  []ChatCompletionMessageToolCall `json:"tool_calls,omitempty"`
+		ToolCallID string                          `json:"tool_call_id,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*m = ChatCompletionMessage{
+		Role:       aux.Role,
+		Name:       aux.Name,
+		ToolCalls:  aux.ToolCalls,
+		ToolCallID: aux.ToolCallID,
+	}
+
+	content := bytes.TrimSpace(aux.Content)
+	if len(content) == 0 || bytes.Equal(content, []byte("null")) {
+		return nil
+	}
+
+	if content[0] == '[' {
+		return json.Unmarshal(content, &m.Parts)
+	}
+
+	return json.Unmarshal(content, &m.Content)
+}
+
+// NewChatCompletionMessage creates a new text message with the given role.
+func NewChatCompletionMessage(role Role, content string) ChatCompletionMessage {
+	return ChatCompletionMessage{Role: role, Content: content}
+}
+
+// ChatCompletionContentPart is a part of the multimodal content of a message.
+type ChatCompletionContentPart struct {
+	// Type is the type of the part ("text", "image_url", "input_audio" or "file").
+	Type string `json:"type"`
+	// Text is the text of a text part.
+	Text string `json:"text,omitempty"`
+	// ImageURL is the image of an image part.
+	ImageURL *ChatCompletionImageURL `json:"image_url,omitempty"`
+	// InputAudio is the audio of an audio part.
+	InputAudio *Audio `json:"input_audio,omitempty"`
+	// File is the file of a file part.
+	File *ChatCompletionFile `json:"file,omitempty"`
+}
+
+// ChatCompletionImageURL is the image of an image part.
+type ChatCompletionImageURL struct {
+	// URL is the URL or the data URL of the image.
+	URL string `json:"url"`
+	// Detail is the detail level of the image.
+	Detail ImageDetail `json:"detail,omitempty"`
+}
+
+// ChatCompletionFile is the file of a file part.
+type ChatCompletionFile struct {
+	// FileID is the ID of an uploaded file.
+	FileID string `json:"file_id,omitempty"`
+	// FileData is the data URL of the file.
+	FileData string `json:"file_data,omitempty"`
+	// Filename is the name of the file.
+	Filename string `json:"filename,omitempty"`
+}
+
+// NewChatCompletionTextPart creates a new text part.
+func NewChatCompletionTextPart(text string) ChatCompletionContentPart {
+	return ChatCompletionContentPart{Type: "text", Text: text}
+}
+
+// NewChatCompletionImagePart creates a new image part from the image.
+func NewChatCompletionImagePart(image Image) ChatCompletionContentPart {
+	u := image.URL
+	if image.Base64 != "" {
+		u = image.DataURL()
+	}
+
+	return ChatCompletionContentPart{Type: "image_url", ImageURL: &ChatCompletionImageURL{URL: u, Detail: image.Detail}}
+}
+
+// ChatCompletionTool is a tool of a chat completion request.
+type ChatCompletionTool struct {
+	// Type is the type of the tool.
+	Type string `json:"type"`
+	// Function is the function of the tool.
+	Function ResponseFunctionDefinition `json:"function"`
+}
+
+// NewChatCompletionTool creates a new function tool.
+func NewChatCompletionTool(function ResponseFunctionDefinition) ChatCompletionTool {
+	return ChatCompletionTool{Type: "function", Function: function}
+}
+
+// ChatCompletionResponseFormat is the format of the output of a chat completion.
+type ChatCompletionResponseFormat struct {
+	// Type is the type of the format.
+	Type ResponseTextFormatType `json:"type"`
+	// JSONSchema is the JSON Schema of structured outputs.
+	JSONSchema *ChatCompletionJSONSchema `json:"json_schema,omitempty"`
+}
+
+// ChatCompletionJSONSchema is the JSON Schema of structured outputs.
+type ChatCompletionJSONSchema struct {
+	// Name is the name of the format.
+	Name string `json:"name"`
+	// Description is the description of the format.
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON Schema.
+	Schema json.RawMessage `json:"schema,omitempty"`
+	// Strict is a flag to enable strict schema adherence.
+	Strict *bool `json:"strict,omitempty"`
+}
+
+// NewChatCompletionResponseFormat creates a new response format from the text format of the Responses API.
+func NewChatCompletionResponseFormat(format ResponseTextFormat) ChatCompletionResponseFormat {
+	if format.Type != ResponseTextFormatJSONSchema {
+		return ChatCompletionResponseFormat{Type: format.Type}
+	}
+
+	return ChatCompletionResponseFormat{
+		Type: format.Type,
+		JSONSchema: &ChatCompletionJSONSchema{
+			Name:        format.Name,
+			Description: format.Description,
+			Schema:      format.Schema,
+			Strict:      format.Strict,
+		},
+	}
+}
+
+// ChatCompletionRequestUnwrapper is implemented by the chat completion requests
+// of providers that extend the request (e.g. Perplexity).
+type ChatCompletionRequestUnwrapper interface {
+	// Unwrap returns the embedded chat completion request.
+	Unwrap() *ChatCompletionRequest
+}
+
+// ChatCompletionResponseUnwrapper is implemented by the chat completions of
+// providers that extend the response (e.g. Perplexity).
+type ChatCompletionResponseUnwrapper interface {
+	// Unwrap returns the embedded chat completion.
+	Unwrap() *ChatCompletionResponse
+}
+
+// ChatCompletionChunkUnwrapper is implemented by the chunks of providers that
+// extend the chunks of a streamed chat completion (e.g. Perplexity).
+type ChatCompletionChunkUnwrapper interface {
+	// Unwrap returns the embedded chunk.
+	Unwrap() *ChatCompletionChunk
+}
+
+// ChatCompletionStreamOptions are the options of a streamed chat completion.
+type ChatCompletionStreamOptions struct {
+	// IncludeUsage is a flag to send the usage in the last chunk.
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// ChatCompletionRequest is the request of the Chat Completions API.
+type ChatCompletionRequest struct {
+	// Model is the model for the chat completion request.
+	Model string `json:"model"`
+	// Messages is the list of messages of the conversation.
+	Messages []ChatCompletionMessage `json:"messages"`
+	// Tools is the list of tools the model may call.
+	Tools []ChatCompletionTool `json:"tools,omitempty"`
+	// ToolChoice is the tool choice for the chat completion request.
+	ToolChoice ToolChoice `json:"tool_choice,omitempty"`
+	// ParallelToolCalls is a flag to enable parallel tool calls.
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
+	// MaxCompletionTokens is the maximum number of tokens to generate.
+	MaxCompletionTokens *int `json:"max_completion_tokens,omitempty"`
+	// MaxTokens is the maximum number of tokens to generate, used by older servers.
+	MaxTokens *int `json:"max_tokens,omitempty"`
+	// Temperature is the sampling temperature
+	Temperature *float32 `json:"temperature,omitempty"`
+	// TopP is the nucleus sampling parameter
+	TopP *float64 `json:"top_p,omitempty"`
+	// Stop are the stop sequences.
+	Stop []string `json:"stop,omitempty"`
+	// Seed is the seed for deterministic sampling.
+	Seed *int `json:"seed,omitempty"`
+	// ResponseFormat is the format of the output.
+	ResponseFormat *ChatCompletionResponseFormat `json:"response_format,omitempty"`
+	// Stream is a flag to enable streaming
+	Stream bool `json:"stream,omitempty"`
+	// StreamOptions are the options of a streamed chat completion.
+	StreamOptions *ChatCompletionStreamOptions `json:"stream_options,omitempty"`
+	// User is the identifier of the end-user.
+	User string `json:"user,omitempty"`
+}
+
+// ChatCompletionRequestOpt is a function type for configuring the ChatCompletionRequest.
+type ChatCompletionRequestOpt func(*ChatCompletionRequest)
+
+// NewChatCompletionRequest creates a new chat completion request with the given options.
+func NewChatCompletionRequest(opts ...ChatCompletionRequestOpt) *ChatCompletionRequest {
+	req := new(ChatCompletionRequest)
+
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	return req
+}
+
+// WithMessages sets the messages for the chat completion request.
+func WithMessages(msgs ...ChatCompletionMessage) ChatCompletionRequestOpt {
+	return func(req *ChatCompletionRequest) {
+		req.Messages = msgs
+	}
+}
+
+// WithChatCompletionTools sets the tools for the chat completion request.
+func WithChatCompletionTools(tools ...ChatCompletionTool) ChatCompletionRequestOpt {
+	return func(req *ChatCompletionRequest) {
+		req.Tools = tools
+	}
+}
+
+// ChatCompletionChoice is a choice of a chat completion.
+type ChatCompletionChoice struct {
+	// Index is the index of the choice.
+	Index int `json:"index"`
+	// Message is the message generated by the model.
+	Message ChatCompletionChoiceIndex `json:"message"`
+	// FinishReason is the reason why the model stopped generating.
+	FinishReason FinishReason `json:"finish_reason"`
+}
+
+// ChatCompletionResponse is the response of the Chat Completions API.
+type ChatCompletionResponse struct {
+	// ID is the unique identifier of the chat completion.
+	ID string `json:"id"`
+	// Object is the type of object returned.
+	Object string `json:"object,omitempty"`
+	// Created is the timestamp of when the chat completion was created.
+	Created int64 `json:"created,omitempty"`
+	// Model is the model used for the chat completion.
+	Model string `json:"model,omitempty"`
+	// Choices is the list of generated choices.
+	Choices []ChatCompletionChoice `json:"choices"`
+	// Usage is the token usage of the chat completion.
+	Usage *CompletionUsage `json:"usage,omitempty"`
+	// SystemFingerprint is the fingerprint of the backend configuration.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+	// ServiceTier is the service tier used to process the request.
+	ServiceTier string `json:"service_tier,omitempty"`
+}
+
+// Content returns the content of the first choice.
+func (r *ChatCompletionResponse) Content() string {
+	if len(r.Choices) == 0 {
+		return ""
+	}
+
+	return r.Choices[0].Message.Content
+}
+
+// ToolCalls returns the function tool calls of the first choice.
+func (r *ChatCompletionResponse) ToolCalls() []ChatCompletionMessageFunctionToolCall {
+	calls := []ChatCompletionMessageFunctionToolCall{}
+
+	if len(r.Choices) == 0 {
+		return calls
+	}
+
+	for _, c := range r.Choices[0].Message.ToolCalls {
+		if call, ok := c.GetFunction(); ok {
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+// ChatCompletionChunk is a chunk of a streamed chat completion.
+type ChatCompletionChunk struct {
+	// ID is the unique identifier of the chat completion.
+	ID string `json:"id"`
+	// Object is the type of object returned.
+	Object string `json:"object,omitempty"`
+	// Created is the timestamp of when the chat completion was created.
+	Created int64 `json:"created,omitempty"`
+	// Model is the model used for the chat completion.
+	Model string `json:"model,omitempty"`
+	// Choices is the list of choice deltas.
+	Choices []ChatCompletionChunkChoice `json:"choices"`
+	// Usage is the token usage, sent in the last chunk if requested.
+	Usage *CompletionUsage `json:"usage,omitempty"`
+}
+
+// ChatCompletionChunkChoice is the delta of a choice of a streamed chat completion.
+type ChatCompletionChunkChoice struct {
+	// Index is the index of the choice.
+	Index int `json:"index"`
+	// Delta is the delta of the message.
+	Delta ChatCompletionChunkDelta `json:"delta"`
+	// FinishReason is the reason why the model stopped generating.
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
+}
+
+// ChatCompletionChunkDelta is the delta of a message of a streamed chat completion.
+type ChatCompletionChunkDelta struct {
+	// Role is the role of the message sender.
+	Role Role `json:"role,omitempty"`
+	// Content is the content delta.
+	Content string `json:"content,omitempty"`
+	// Refusal is the refusal delta.
+	Refusal string `json:"refusal,omitempty"`
+	// ReasoningContent is the reasoning delta sent by some servers (e.g. vLLM).
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// ToolCalls are the tool call deltas.
+	ToolCalls []ChatCompletionChunkToolCall `json:"tool_calls,omitempty"`
+}
+
+// ChatCompletionChunkToolCall is the delta of a tool call of a streamed chat completion.
+type ChatCompletionChunkToolCall struct {
+	// Index is the index of the tool call.
+	Index int `json:"index"`
+	// ID is the unique identifier of the tool call, sent in the first delta.
+	ID string `json:"id,omitempty"`
+	// Type is the type of the tool call, sent in the first delta.
+	Type string `json:"type,omitempty"`
+	// Function is the function delta.
+	Function ChatCompletionMessageFunction `json:"function"`
+}
+
+// DecodeChatCompletionStream transforms a sequence of server-sent events into a
+// sequence of chat completion chunks.
+func DecodeChatCompletionStream(events iter.Seq2[prompts.Event, error]) iter.Seq2[*ChatCompletionChunk, error] {
+	return DecodeStream[ChatCompletionChunk](events, nil)
+}
+
+// ChatCompletions is a struct that implements the Prompter interface for the
+// Chat Completions API of OpenAI and OpenAI-compatible servers (e.g. vLLM or llama.cpp).
+type ChatCompletions[I *ChatCompletionRequest, O *ChatCompletionResponse] struct {
+	client *prompts.Client
+	path   string
+	name   string
+}
+
+var _ prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] = (*ChatCompletions[*ChatCompletionRequest, *ChatCompletionResponse])(nil)
+
+// NewChatCompletions creates a new ChatCompletions with the given client.
+//
+//	prompt := openai.NewChatCompletions(client, openai.WithBaseURL("http://localhost:8000/v1/"))
+func NewChatCompletions(client *prompts.Client, opts ...Opt) prompts.Prompter[*ChatCompletionRequest, *ChatCompletionResponse] {
+	return newChatCompletions(client, opts...)
+}
+
+// NewChatCompletionsStreamer creates a new ChatCompletions with the given client that streams chat completions.
+func NewChatCompletionsStreamer(client *prompts.Client, opts ...Opt) prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] {
+	return newChatCompletions(client, opts...)
+}
+
+func newChatCompletions(client *prompts.Client, opts ...Opt) *ChatCompletions[*ChatCompletionRequest, *ChatCompletionResponse] {
+	p := newOpenAI(client, opts...)
+
+	return &ChatCompletions[*ChatCompletionRequest, *ChatCompletionResponse]{client: p.client, path: "chat/completions", name: p.name}
+}
+
+// NewAzureChatCompletions creates a new ChatCompletions for the deployment of
+// the Azure OpenAI resource at the given endpoint.
+func NewAzureChatCompletions(client *prompts.Client, endpoint string, opts ...AzureOpt) prompts.Prompter[*ChatCompletionRequest, *ChatCompletionResponse] {
+	return newAzureChatCompletions(client, endpoint, opts...)
+}
+
+// NewAzureChatCompletionsStreamer creates a new ChatCompletions for the
+// deployment of the Azure OpenAI resource at the given endpoint that streams chat completions.
+func NewAzureChatCompletionsStreamer(client *prompts.Client, endpoint string, opts ...AzureOpt) prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] {
+	return newAzureChatCompletions(client, endpoint, opts...)
+}
+
+func newAzureChatCompletions(client *prompts.Client, endpoint string, opts ...AzureOpt) *ChatCompletions[*ChatCompletionRequest, *ChatCompletionResponse] {
+	p := newAzure(client, endpoint, opts...)
+
+	path := "chat/completions"
+	if p.deployment != "" {
+		path = "deployments/" + url.PathEscape(p.deployment) + "/" + path
+	}
+
+	return &ChatCompletions[*ChatCompletionRequest, *ChatCompletionResponse]{client: p.client, path: path, name: p.name}
+}
+
+// ProviderName returns the name of the provider ("openai" or "azure.ai.openai").
+func (p *ChatCompletions[I, O]) ProviderName() string {
+	return p.name
+}
+
+// Respond sends a chat completion request and returns the response.
+func (p *ChatCompletions[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	res := &ChatCompletionResponse{}
+
+	body := *req
+	body.Stream = false
+	body.StreamOptions = nil
+
+	_, err := p.client.New().Post(p.path).BodyJSON(&body).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Stream sends a chat completion request and returns the stream of chunks.
+func (p *ChatCompletions[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ChatCompletionChunk, error] {
+	body := *req
+	body.Stream = true
+
+	c := p.client.New().Post(p.path).BodyJSON(&body)
+
+	return DecodeChatCompletionStream(prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder()))
+}
+
+// AccumulateChatCompletion accumulates the chunks of a streamed chat
+// completion into a response. Tool call argument deltas are concatenated.
+func AccumulateChatCompletion(chunks iter.Seq2[*ChatCompletionChunk, error]) (*ChatCompletionResponse, error) {
+	res := &ChatCompletionResponse{Object: "chat.completion"}
+	content := map[int]*strings.Builder{}
+	calls := map[int]map[int]*ChatCompletionMessageFunctionToolCall{}
+
+	for chunk, err := range chunks {
+		if err != nil {
+			return res, err
+		}
+
+		res.ID = chunk.ID
+		res.Created = chunk.Created
+		res.Model = chunk.Model
+
+		if chunk.Usage != nil {
+			res.Usage = chunk.Usage
+		}
+
+		for _, c := range chunk.Choices {
+			for len(res.Choices) <= c.Index {
+				res.Choices = append(res.Choices, ChatCompletionChoice{Index: len(res.Choices), Message: ChatCompletionChoiceIndex{Role: RoleAssistant}})
+				content[len(res.Choices)-1] = &strings.Builder{}
+				calls[len(res.Choices)-1] = map[int]*ChatCompletionMessageFunctionToolCall{}
+			}
+
+			choice := &res.Choices[c.Index]
+			content[c.Index].WriteString(c.Delta.Content)
+			choice.Message.Refusal += c.Delta.Refusal
+			choice.Message.ReasoningContent += c.Delta.ReasoningContent
+
+			if c.FinishReason != FinishReasonUnknown {
+				choice.FinishReason = c.FinishReason
+			}
+
+			for _, tc := range c.Delta.ToolCalls {
+				call, ok := calls[c.Index][tc.Index]
+				if !ok {
+					call = &ChatCompletionMessageFunctionToolCall{Type: "function"}
+					calls[c.Index][tc.Index] = call
+				}
+
+				call.ID += tc.ID
+				call.Function.Name += tc.Function.Name
+				call.Function.Arguments += tc.Function.Arguments
+			}
+		}
+	}
+
+	for i := range res.Choices {
+		res.Choices[i].Message.Content = content[i].String()
+
+		// the indices of the tool calls are not necessarily contiguous.
+		for _, j := range slices.Sorted(maps.Keys(calls[i])) {
+			res.Choices[i].Message.ToolCalls = append(res.Choices[i].Message.ToolCalls, ChatCompletionMessageToolCall{ToolCall: *calls[i][j]})
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,177 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatCompletionMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  openai.ChatCompletionMessage
+		json string
+	}{
+		{
+			name: "text",
+			msg:  openai.NewChatCompletionMessage(openai.RoleUser, "Hello"),
+			json: `{"role":"user","content":"Hello"}`,
+		},
+		{
+			name: "parts",
+			msg: openai.ChatCompletionMessage{
+				Role:  openai.RoleUser,
+				Parts: []openai.ChatCompletionContentPart{openai.NewChatCompletionTextPart("What is this?")},
+			},
+			json: `{"role":"user","content":[{"type":"text","text":"What is this?"}]}`,
+		},
+		{
+			name: "tool calls",
+			msg: openai.ChatCompletionMessage{
+				Role: openai.RoleAssistant,
+				ToolCalls: []openai.ChatCompletionMessageToolCall{
+					{ToolCall: openai.ChatCompletionMessageFunctionToolCall{ID: "call_1", Type: "function", Function: openai.ChatCompletionMessageFunction{Name: "weather", Arguments: `{"city":"Berlin"}`}}},
+				},
+			},
+			json: `{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"weather","arguments":"{\"city\":\"Berlin\"}"}}]}`,
+		},
+		{
+			name: "tool result",
+			msg:  openai.ChatCompletionMessage{Role: openai.RoleTool, Content: "sunny", ToolCallID: "call_1"},
+			json: `{"role":"tool","content":"sunny","tool_call_id":"call_1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.msg)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.json, string(b))
+
+			msg := openai.ChatCompletionMessage{}
+			require.NoError(t, json.Unmarshal(b, &msg))
+			require.Equal(t, tt.msg, msg)
+		})
+	}
+}
+
+func TestChatCompletions(t *testing.T) {
+	tests := []struct {
+		name  string
+		new   func(client *prompts.Client, url string) prompts.Prompter[*openai.ChatCompletionRequest, *openai.ChatCompletionResponse]
+		path  string
+		query string
+	}{
+		{
+			name: "openai compatible",
+			new: func(client *prompts.Client, url string) prompts.Prompter[*openai.ChatCompletionRequest, *openai.ChatCompletionResponse] {
+				return openai.NewChatCompletions(client, openai.WithBaseURL(url+"/v1"))
+			},
+			path: "/v1/chat/completions",
+		},
+		{
+			name: "azure",
+			new: func(client *prompts.Client, url string) prompts.Prompter[*openai.ChatCompletionRequest, *openai.ChatCompletionResponse] {
+				return openai.NewAzureChatCompletions(client, url, openai.WithDeployment("my-gpt"))
+			},
+			path:  "/openai/deployments/my-gpt/chat/completions",
+			query: "api-version=" + openai.DefaultAzureAPIVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tt.path, r.URL.Path)
+				require.Equal(t, tt.query, r.URL.RawQuery)
+
+				req := &openai.ChatCompletionRequest{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+				require.False(t, req.Stream)
+				require.Len(t, req.Messages, 1)
+				require.Len(t, req.Tools, 1)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"chatcmpl-1","choices":[{"index":0,"message":{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"weather","arguments":"{\"city\":\"Berlin\"}"}}]},"finish_reason":"tool_calls"}]}`))
+			}))
+			defer srv.Close()
+
+			req := openai.NewChatCompletionRequest(
+				openai.WithMessages(openai.NewChatCompletionMessage(openai.RoleUser, "Weather in Berlin?")),
+				openai.WithChatCompletionTools(openai.NewChatCompletionTool(openai.ResponseFunctionDefinition{Name: "weather"})),
+			)
+
+			res, err := tt.new(prompts.NewClient(), srv.URL).Respond(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, openai.FinishReasonToolCalls, res.Choices[0].FinishReason)
+
+			calls := res.ToolCalls()
+			require.Len(t, calls, 1)
+			require.Equal(t, "weather", calls[0].Function.Name)
+			require.JSONEq(t, `{"city":"Berlin"}`, calls[0].Function.Arguments)
+		})
+	}
+}
+
+func TestChatCompletionsStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &openai.ChatCompletionRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		require.True(t, req.Stream)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hel\"}}]}\n\n" +
+			"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+			"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"weather\",\"arguments\":\"{\\\"city\\\":\"}}]}}]}\n\n" +
+			"data: {\"id\":\"chatcmpl-1\",\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":\"\\\"Berlin\\\"}\"}}]},\"finish_reason\":\"tool_calls\"}]}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	s := openai.NewChatCompletionsStreamer(prompts.NewClient(), openai.WithBaseURL(srv.URL))
+
+	res, err := openai.AccumulateChatCompletion(s.Stream(context.Background(), openai.NewChatCompletionRequest()))
+	require.NoError(t, err)
+	require.Equal(t, "Hello", res.Content())
+	require.Equal(t, openai.FinishReasonToolCalls, res.Choices[0].FinishReason)
+
+	calls := res.ToolCalls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "call_1", calls[0].ID)
+	require.JSONEq(t, `{"city":"Berlin"}`, calls[0].Function.Arguments)
+}
+
+func TestAccumulateChatCompletionSparseToolCalls(t *testing.T) {
+	data := []string{
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":2,"id":"call_2","type":"function","function":{"name":"time","arguments":"{}"}}]}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_0","type":"function","function":{"name":"weather","arguments":"{}"}}]}}]}`,
+		`{"id":"chatcmpl-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":5,"id":"call_5","type":"function","function":{"name":"news","arguments":"{}"}}]}}]}`,
+	}
+
+	chunks := func(yield func(*openai.ChatCompletionChunk, error) bool) {
+		for _, d := range data {
+			chunk := &openai.ChatCompletionChunk{}
+			require.NoError(t, json.Unmarshal([]byte(d), chunk))
+
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+	}
+
+	res, err := openai.AccumulateChatCompletion(chunks)
+	require.NoError(t, err)
+
+	var ids []string
+	for _, call := range res.ToolCalls() {
+		ids = append(ids, call.ID)
+	}
+
+	require.Equal(t, []string{"call_0", "call_2", "call_5"}, ids)
+}
@@ -0,0 +1,95 @@
+package openai
+
+import (
+	"context"
+	"sync"
+
+	"github.com/katallaxie/prompts"
+)
+
+// Conversation chains the turns of a multi-turn conversation via the
+// previous_response_id of stored responses. Only the new input of a turn is
+// sent, the history is kept by the server. It needs a provider that stores
+// responses and supports previous_response_id (e.g. OpenAI or Perplexity),
+// providers that translate requests to other APIs (e.g. compat without the
+// Responses API) reject the chained requests.
+//
+//	conv := openai.NewConversation(openai.New(client), openai.WithInstructions("Be brief."))
+//	res, err := conv.Send(ctx, "What is the capital of France?")
+//	res, err = conv.Send(ctx, "And of Germany?")
+type Conversation struct {
+	prompter prompts.Prompter[*ResponseRequest, *Response]
+	opts     []RequestOpt
+	previous string
+	mu       sync.Mutex
+}
+
+var _ prompts.Prompter[*ResponseRequest, *Response] = (*Conversation)(nil)
+
+// NewConversation creates a new Conversation. The options are applied to the
+// request of every turn, as instructions are not carried over by previous_response_id.
+func NewConversation(prompter prompts.Prompter[*ResponseRequest, *Response], opts ...RequestOpt) *Conversation {
+	return &Conversation{prompter: prompter, opts: opts}
+}
+
+// ProviderName returns the name of the provider of the prompter.
+func (c *Conversation) ProviderName() string {
+	return prompts.ProviderName(c.prompter)
+}
+
+// Send sends a user message as the next turn of the conversation.
+func (c *Conversation) Send(ctx context.Context, text string) (*Response, error) {
+	return c.Next(ctx, NewInputMessage(RoleUser, text))
+}
+
+// Next sends the input as the next turn of the conversation (e.g. the outputs of function calls).
+func (c *Conversation) Next(ctx context.Context, input ...ResponseInput) (*Response, error) {
+	req := NewResponseRequest(c.opts...)
+	req.Input = input
+
+	return c.Respond(ctx, req)
+}
+
+// Respond sends the request as the next turn of the conversation. The request
+// is chained to the previous response unless it sets a previous response ID
+// or a conversation, and it is stored unless storing is disabled.
+func (c *Conversation) Respond(ctx context.Context, req *ResponseRequest) (*Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	body := *req
+
+	if body.PreviousResponseID == "" && body.Conversation == "" {
+		body.PreviousResponseID = c.previous
+	}
+
+	if body.Store == nil {
+		store := true
+		body.Store = &store
+	}
+
+	res, err := c.prompter.Respond(ctx, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.previous = res.ID
+
+	return res, nil
+}
+
+// PreviousResponseID returns the ID of the last response of the conversation.
+func (c *Conversation) PreviousResponseID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.previous
+}
+
+// Reset starts a new conversation, optionally continuing from the response with the given ID.
+func (c *Conversation) Reset(previousResponseID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.previous = previousResponseID
+}
@@ -0,0 +1,47 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversation(t *testing.T) {
+	requests := []openai.ResponseRequest{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := openai.ResponseRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"id":"resp_%d","status":"completed"}`, len(requests))
+	}))
+	defer srv.Close()
+
+	conv := openai.NewConversation(openai.New(prompts.NewClient(), openai.WithBaseURL(srv.URL)), openai.WithInstructions("Be brief."))
+
+	_, err := conv.Send(context.Background(), "What is the capital of France?")
+	require.NoError(t, err)
+
+	_, err = conv.Send(context.Background(), "And of Germany?")
+	require.NoError(t, err)
+	require.Equal(t, "resp_2", conv.PreviousResponseID())
+
+	require.Len(t, requests, 2)
+	require.Empty(t, requests[0].PreviousResponseID)
+	require.Equal(t, "resp_1", requests[1].PreviousResponseID)
+
+	for _, req := range requests {
+		require.Equal(t, "Be brief.", req.Instructions)
+		require.True(t, *req.Store)
+		require.Len(t, req.Input, 1)
+	}
+}
@@ -0,0 +1,32 @@
+package openai
+
+// Extension is a set of provider specific fields of a request or response
+// (e.g. the search options of Perplexity). Providers send the extensions of a
+// request they know and ignore all others, and attach the fields of their
+// responses that have no place in the Responses API as extensions.
+type Extension interface {
+	// Provider returns the name of the provider of the extension (e.g. "perplexity").
+	Provider() string
+}
+
+// ExtensionFor returns the last extension of the type T.
+//
+//	opts, ok := openai.ExtensionFor[perplexity.SearchOptions](req.Extensions)
+func ExtensionFor[T Extension](exts []Extension) (T, bool) {
+	for i := len(exts) - 1; i >= 0; i-- {
+		if ext, ok := exts[i].(T); ok {
+			return ext, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// WithExtensions adds provider specific fields to the request.
+func WithExtensions(exts ...Extension) RequestOpt {
+	return func(req *ResponseRequest) {
+		req.Extensions = append(req.Extensions, exts...)
+	}
+}
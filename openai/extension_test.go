@@ -0,0 +1,56 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+type fooExtension struct {
+	Value string
+}
+
+func (fooExtension) Provider() string { return "foo" }
+
+type barExtension struct{}
+
+func (barExtension) Provider() string { return "bar" }
+
+func TestExtensionFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		exts     []openai.Extension
+		expected fooExtension
+		ok       bool
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "other provider",
+			exts: []openai.Extension{barExtension{}},
+		},
+		{
+			name:     "last wins",
+			exts:     []openai.Extension{fooExtension{Value: "a"}, barExtension{}, fooExtension{Value: "b"}},
+			expected: fooExtension{Value: "b"},
+			ok:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := openai.NewResponseRequest(openai.WithExtensions(tt.exts...))
+
+			ext, ok := openai.ExtensionFor[fooExtension](req.Extensions)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.expected, ext)
+
+			b, err := json.Marshal(req)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"model": "", "input": null}`, string(b))
+		})
+	}
+}
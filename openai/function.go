@@ -0,0 +1,103 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts/schema"
+)
+
+// NewFunctionDefinition creates a function definition with the parameters
+// derived from the JSON Schema of the argument struct T. Strict definitions
+// require all parameters and disallow additional properties.
+//
+//	type HoroscopeArgs struct {
+//		Sign string `json:"sign" jsonschema:"description=The zodiac sign,enum=aries,enum=taurus"`
+//	}
+//
+//	fn, err := openai.NewFunctionDefinition[HoroscopeArgs]("get_horoscope", "Get the horoscope for a given zodiac sign.", true)
+func NewFunctionDefinition[T any](name, description string, strict bool) (ResponseFunctionDefinition, error) {
+	params, err := NewFunctionParameters(reflect.TypeFor[T](), strict)
+	if err != nil {
+		return ResponseFunctionDefinition{}, fmt.Errorf("openai: function %s: %w", name, err)
+	}
+
+	return ResponseFunctionDefinition{
+		Name:        name,
+		Description: description,
+		Parameters:  params,
+		Strict:      strict,
+	}, nil
+}
+
+// NewFunctionDefinitionFor creates a function definition with the parameters
+// derived from the argument type of the given function.
+func NewFunctionDefinitionFor[T, R any](name, description string, strict bool, _ func(context.Context, T) (R, error)) (ResponseFunctionDefinition, error) {
+	return NewFunctionDefinition[T](name, description, strict)
+}
+
+// NewFunctionTool creates a function tool with the parameters derived from
+// the JSON Schema of the argument struct T.
+func NewFunctionTool[T any](name, description string, strict bool) (ResponseTool, error) {
+	fn, err := NewFunctionDefinition[T](name, description, strict)
+	if err != nil {
+		return ResponseTool{}, err
+	}
+
+	return ResponseTool{Tool: ResponseFunctionTool{Function: fn}}, nil
+}
+
+// NewFunctionParameters creates the function parameters from the JSON Schema
+// of the given struct type.
+func NewFunctionParameters(t reflect.Type, strict bool) (ResponseFunctionParameters, error) {
+	var opts []schema.Opt
+	if strict {
+		opts = append(opts, schema.WithStrict())
+	}
+
+	s, err := schema.Reflect(t, opts...)
+	if err != nil {
+		return ResponseFunctionParameters{}, err
+	}
+
+	defs := s.Defs
+
+	// recursive argument structs are referenced from the definitions
+	if s.Ref != "" {
+		s = defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+	}
+
+	if s == nil || !s.Type.Has(schema.TypeObject) || s.Properties == nil {
+		return ResponseFunctionParameters{}, fmt.Errorf("parameters must be a struct, got %s", t)
+	}
+
+	params := ResponseFunctionParameters{
+		Properties: make(ResponseFunctionProperties, len(s.Properties)),
+		Required:   s.Required,
+	}
+
+	for name, prop := range s.Properties {
+		if params.Properties[name], err = json.Marshal(prop); err != nil {
+			return ResponseFunctionParameters{}, err
+		}
+	}
+
+	if len(defs) > 0 {
+		params.Defs = make(ResponseFunctionProperties, len(defs))
+		for name, def := range defs {
+			if params.Defs[name], err = json.Marshal(def); err != nil {
+				return ResponseFunctionParameters{}, err
+			}
+		}
+	}
+
+	if strict {
+		params.AdditionalProperties = cast.Ptr(false)
+	}
+
+	return params, nil
+}
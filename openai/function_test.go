@@ -0,0 +1,91 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+type WeatherArgs struct {
+	Location struct {
+		City    string `json:"city" jsonschema:"description=The name of the city"`
+		Country string `json:"country,omitempty"`
+	} `json:"location"`
+	Units []string `json:"units" jsonschema:"enum=celsius,enum=fahrenheit"`
+}
+
+func TestNewFunctionTool(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+		want   string
+	}{
+		{
+			name:   "not strict",
+			strict: false,
+			want: `{
+				"type": "function",
+				"name": "get_weather",
+				"description": "Get the weather.",
+				"parameters": {
+					"type": "object",
+					"properties": {
+						"location": {
+							"type": "object",
+							"properties": {
+								"city": {"type": "string", "description": "The name of the city"},
+								"country": {"type": "string"}
+							},
+							"required": ["city"]
+						},
+						"units": {"type": "array", "items": {"type": "string", "enum": ["celsius", "fahrenheit"]}}
+					},
+					"required": ["location", "units"]
+				}
+			}`,
+		},
+		{
+			name:   "strict",
+			strict: true,
+			want: `{
+				"type": "function",
+				"name": "get_weather",
+				"description": "Get the weather.",
+				"strict": true,
+				"parameters": {
+					"type": "object",
+					"properties": {
+						"location": {
+							"type": "object",
+							"properties": {
+								"city": {"type": "string", "description": "The name of the city"},
+								"country": {"type": ["string", "null"]}
+							},
+							"required": ["city", "country"],
+							"additionalProperties": false
+						},
+						"units": {"type": "array", "items": {"type": "string", "enum": ["celsius", "fahrenheit"]}}
+					},
+					"required": ["location", "units"],
+					"additionalProperties": false
+				}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, err := openai.NewFunctionTool[WeatherArgs]("get_weather", "Get the weather.", tt.strict)
+			require.NoError(t, err)
+
+			b, err := json.Marshal(tool)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+
+	_, err := openai.NewFunctionTool[string]("echo", "", false)
+	require.Error(t, err)
+}
@@ -0,0 +1,243 @@
+package openai
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/katallaxie/prompts"
+)
+
+// DefaultURL is the default endpoint for the OpenAI API.
+const DefaultURL = "https://api.openai.com/v1/"
+
+// DefaultModel is the default model for the OpenAI API.
+const DefaultModel = "gpt-5"
+
+// DefaultAzureAPIVersion is the default API version for Azure OpenAI.
+const DefaultAzureAPIVersion = "2025-04-01-preview"
+
+const (
+	organizationHeader = "OpenAI-Organization"
+	projectHeader      = "OpenAI-Project"
+	azureAPIKeyHeader  = "Api-Key"
+)
+
+// OpenAI is a struct that implements the Prompter interface for the OpenAI API.
+type OpenAI[I *ResponseRequest, O *Response] struct {
+	client     *prompts.Client
+	deployment string
+	name       string
+}
+
+var _ prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] = (*OpenAI[*ResponseRequest, *Response])(nil)
+
+// Opts are the options of the OpenAI provider.
+type Opts struct {
+	// BaseURL is the endpoint of the API.
+	BaseURL string
+	// Organization is the organization the requests are billed to.
+	Organization string
+	// Project is the project the requests are billed to.
+	Project string
+	// ProviderName is the name of the provider reported to middleware
+	// (default: "openai"), e.g. for OpenAI-compatible servers.
+	ProviderName string
+}
+
+// Opt is a function type for configuring the OpenAI provider.
+type Opt func(*Opts)
+
+// WithBaseURL sets the endpoint of the API.
+func WithBaseURL(u string) Opt {
+	return func(o *Opts) {
+		o.BaseURL = u
+	}
+}
+
+// WithOrganization sets the OpenAI-Organization header.
+func WithOrganization(org string) Opt {
+	return func(o *Opts) {
+		o.Organization = org
+	}
+}
+
+// WithProject sets the OpenAI-Project header.
+func WithProject(project string) Opt {
+	return func(o *Opts) {
+		o.Project = project
+	}
+}
+
+// WithProviderName sets the name of the provider reported to middleware.
+func WithProviderName(name string) Opt {
+	return func(o *Opts) {
+		o.ProviderName = name
+	}
+}
+
+// New creates a new OpenAI with the given client.
+//
+//	client := prompts.NewClient().APIKey(os.Getenv("OPENAI_API_KEY"))
+//	prompt := openai.New(client, openai.WithProject("proj_123"))
+func New(client *prompts.Client, opts ...Opt) prompts.Prompter[*ResponseRequest, *Response] {
+	return newOpenAI(client, opts...)
+}
+
+// NewStreamer creates a new OpenAI with the given client that streams responses.
+func NewStreamer(client *prompts.Client, opts ...Opt) prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] {
+	return newOpenAI(client, opts...)
+}
+
+func newOpenAI(client *prompts.Client, opts ...Opt) *OpenAI[*ResponseRequest, *Response] {
+	o := &Opts{BaseURL: DefaultURL, ProviderName: "openai"}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base := client.New().Base(withTrailingSlash(o.BaseURL))
+
+	if o.Organization != "" {
+		base.Set(organizationHeader, o.Organization)
+	}
+
+	if o.Project != "" {
+		base.Set(projectHeader, o.Project)
+	}
+
+	return &OpenAI[*ResponseRequest, *Response]{client: base, name: o.ProviderName}
+}
+
+// AzureOpts are the options of the Azure OpenAI provider.
+type AzureOpts struct {
+	// Deployment is the name of the model deployment. It is used as the
+	// model of requests without one.
+	Deployment string
+	// APIVersion is the api-version query parameter.
+	APIVersion string
+	// APIKey is sent as the api-key header instead of the Authorization
+	// header of the client. If empty, the Authorization header is used,
+	// e.g. for Microsoft Entra ID tokens.
+	APIKey string
+}
+
+// AzureOpt is a function type for configuring the Azure OpenAI provider.
+type AzureOpt func(*AzureOpts)
+
+// WithDeployment sets the name of the model deployment.
+func WithDeployment(deployment string) AzureOpt {
+	return func(o *AzureOpts) {
+		o.Deployment = deployment
+	}
+}
+
+// WithAPIVersion sets the api-version query parameter.
+func WithAPIVersion(version string) AzureOpt {
+	return func(o *AzureOpts) {
+		o.APIVersion = version
+	}
+}
+
+// WithAzureAPIKey sets the api-key header.
+func WithAzureAPIKey(key string) AzureOpt {
+	return func(o *AzureOpts) {
+		o.APIKey = key
+	}
+}
+
+// azureQuery is the query of Azure OpenAI requests.
+type azureQuery struct {
+	APIVersion string `url:"api-version"`
+}
+
+// NewAzure creates a new OpenAI for the Azure OpenAI resource at the given endpoint.
+//
+//	client := prompts.NewClient()
+//	prompt := openai.NewAzure(client, "https://my-resource.openai.azure.com",
+//		openai.WithDeployment("gpt-5"),
+//		openai.WithAzureAPIKey(os.Getenv("AZURE_OPENAI_API_KEY")),
+//	)
+func NewAzure(client *prompts.Client, endpoint string, opts ...AzureOpt) prompts.Prompter[*ResponseRequest, *Response] {
+	return newAzure(client, endpoint, opts...)
+}
+
+// NewAzureStreamer creates a new OpenAI for the Azure OpenAI resource at the given endpoint that streams responses.
+func NewAzureStreamer(client *prompts.Client, endpoint string, opts ...AzureOpt) prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] {
+	return newAzure(client, endpoint, opts...)
+}
+
+func newAzure(client *prompts.Client, endpoint string, opts ...AzureOpt) *OpenAI[*ResponseRequest, *Response] {
+	o := &AzureOpts{APIVersion: DefaultAzureAPIVersion}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	base := client.New().Base(withTrailingSlash(endpoint) + "openai/").QueryStruct(&azureQuery{APIVersion: o.APIVersion})
+
+	if o.APIKey != "" {
+		base.Del("Authorization").Set(azureAPIKeyHeader, o.APIKey)
+	}
+
+	return &OpenAI[*ResponseRequest, *Response]{client: base, deployment: o.Deployment, name: "azure.ai.openai"}
+}
+
+// ProviderName returns the name of the provider ("openai" or "azure.ai.openai").
+func (p *OpenAI[I, O]) ProviderName() string {
+	return p.name
+}
+
+// Respond sends a response request and returns the response.
+func (p *OpenAI[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	body := p.body(req)
+	if err := body.Validate(); err != nil {
+		return nil, err
+	}
+	body.Stream = false
+
+	res := &Response{}
+
+	_, err := p.client.New().Post("responses").BodyJSON(&body).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Stream sends a response request and returns the stream of events.
+func (p *OpenAI[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ResponseStreamEvent, error] {
+	return func(yield func(*ResponseStreamEvent, error) bool) {
+		body := p.body(req)
+		if err := body.Validate(); err != nil {
+			yield(nil, err)
+			return
+		}
+		body.Stream = true
+
+		c := p.client.New().Post("responses").BodyJSON(&body)
+
+		for e, err := range DecodeResponseStream(prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder())) {
+			if !yield(e, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// body returns a copy of the request with the deployment as default model.
+func (p *OpenAI[I, O]) body(req *ResponseRequest) ResponseRequest {
+	body := *req
+	if body.Model == "" {
+		body.Model = p.deployment
+	}
+
+	return body
+}
+
+func withTrailingSlash(u string) string {
+	if strings.HasSuffix(u, "/") {
+		return u
+	}
+
+	return u + "/"
+}
@@ -0,0 +1,179 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviders(t *testing.T) {
+	tests := []struct {
+		name   string
+		new    func(client *prompts.Client, url string) prompts.Prompter[*openai.ResponseRequest, *openai.Response]
+		path   string
+		query  string
+		header http.Header
+		model  string
+	}{
+		{
+			name: "openai",
+			new: func(client *prompts.Client, url string) prompts.Prompter[*openai.ResponseRequest, *openai.Response] {
+				return openai.New(client, openai.WithBaseURL(url+"/v1"), openai.WithOrganization("org-1"), openai.WithProject("proj_1"))
+			},
+			path: "/v1/responses",
+			header: http.Header{
+				"Authorization":       {"Bearer secret"},
+				"Openai-Organization": {"org-1"},
+				"Openai-Project":      {"proj_1"},
+			},
+		},
+		{
+			name: "azure",
+			new: func(client *prompts.Client, url string) prompts.Prompter[*openai.ResponseRequest, *openai.Response] {
+				return openai.NewAzure(client, url, openai.WithDeployment("my-gpt"), openai.WithAzureAPIKey("azure-key"))
+			},
+			path:  "/openai/responses",
+			query: "api-version=" + openai.DefaultAzureAPIVersion,
+			header: http.Header{
+				"Api-Key":       {"azure-key"},
+				"Authorization": nil,
+			},
+			model: "my-gpt",
+		},
+		{
+			name: "azure api version",
+			new: func(client *prompts.Client, url string) prompts.Prompter[*openai.ResponseRequest, *openai.Response] {
+				return openai.NewAzure(client, url+"/", openai.WithAPIVersion("preview"))
+			},
+			path:  "/openai/responses",
+			query: "api-version=preview",
+			header: http.Header{
+				"Authorization": {"Bearer secret"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, tt.path, r.URL.Path)
+				require.Equal(t, tt.query, r.URL.RawQuery)
+
+				for k, v := range tt.header {
+					require.Equal(t, v, r.Header.Values(k))
+				}
+
+				req := &openai.ResponseRequest{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(req))
+				require.Equal(t, tt.model, req.Model)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"resp_1","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"Hello"}]}]}`))
+			}))
+			defer srv.Close()
+
+			client := prompts.NewClient().APIKey("secret")
+
+			res, err := tt.new(client, srv.URL).Respond(context.Background(), openai.NewResponseRequest())
+			require.NoError(t, err)
+			require.Equal(t, "Hello", res.OutputText())
+		})
+	}
+}
+
+func TestRespondBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"model": "gpt-4o",
+			"input": [{"role": "user", "content": [{"type": "input_text", "text": "Hello"}]}],
+			"max_output_tokens": 100,
+			"temperature": 0.5
+		}`, string(b))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"resp_1","output":[]}`))
+	}))
+	defer srv.Close()
+
+	req := openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "Hello")))
+	req.Model = "gpt-4o"
+	req.MaxTokens = cast.Ptr(100)
+	req.Temperature = cast.Ptr[float32](0.5)
+
+	_, err := openai.New(prompts.NewClient(), openai.WithBaseURL(srv.URL+"/v1")).Respond(context.Background(), req)
+	require.NoError(t, err)
+}
+
+func TestUnsupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("unexpected request")
+	}))
+	defer srv.Close()
+
+	client := prompts.NewClient()
+
+	tests := []struct {
+		name     string
+		prompter prompts.Prompter[*openai.ResponseRequest, *openai.Response]
+		streamer prompts.Streamer[*openai.ResponseRequest, *openai.ResponseStreamEvent]
+	}{
+		{
+			name:     "openai",
+			prompter: openai.New(client, openai.WithBaseURL(srv.URL)),
+			streamer: openai.NewStreamer(client, openai.WithBaseURL(srv.URL)),
+		},
+		{
+			name:     "azure",
+			prompter: openai.NewAzure(client, srv.URL),
+			streamer: openai.NewAzureStreamer(client, srv.URL),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "Hello")))
+			req.TopK = cast.Ptr(40)
+
+			_, err := tt.prompter.Respond(context.Background(), req)
+			require.ErrorIs(t, err, openai.ErrUnsupported)
+
+			for _, err := range tt.streamer.Stream(context.Background(), req) {
+				require.ErrorIs(t, err, openai.ErrUnsupported)
+			}
+		})
+	}
+}
+
+func TestProviderName(t *testing.T) {
+	client := prompts.NewClient()
+
+	tests := []struct {
+		name     string
+		provider any
+		want     string
+	}{
+		{name: "openai", provider: openai.New(client), want: "openai"},
+		{name: "azure", provider: openai.NewAzureStreamer(client, "https://my-resource.openai.azure.com"), want: "azure.ai.openai"},
+		{name: "chat completions", provider: openai.NewChatCompletions(client, openai.WithProviderName("vllm")), want: "vllm"},
+		{name: "azure chat completions", provider: openai.NewAzureChatCompletions(client, "https://my-resource.openai.azure.com"), want: "azure.ai.openai"},
+		{name: "conversation", provider: openai.NewConversation(openai.NewAzure(client, "https://my-resource.openai.azure.com")), want: "azure.ai.openai"},
+		{name: "poller", provider: openai.NewPoller(openai.New(client), openai.NewStore(client)), want: "openai"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, prompts.ProviderName(tt.provider))
+		})
+	}
+}
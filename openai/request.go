@@ -3,7 +3,10 @@ package openai
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
 var _ fmt.Stringer = (*Role)(nil)
@@ -26,6 +29,8 @@ const (
 	RoleDeveloper Role = "developer"
 	// RoleSystem is the system role.
 	RoleSystem Role = "system"
+	// RoleTool is the tool role of tool results in chat completions.
+	RoleTool Role = "tool"
 	// RoleFunction is the function role.
 	RoleFunction Role = "function"
 	// RoleNone is the none role.
@@ -108,18 +113,26 @@ type ResponseFunctionParameters struct {
 	Properties ResponseFunctionProperties `json:"properties,omitempty"`
 	// Required is the required parameters for the function tool.
 	Required []string `json:"required,omitempty"`
+	// AdditionalProperties is a flag to allow additional parameters.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"` //nolint:tagliatelle
+	// Defs is the definitions of recursive parameter types.
+	Defs ResponseFunctionProperties `json:"$defs,omitempty"` //nolint:tagliatelle
 }
 
 // MarshalJSON marshals the response function parameters into JSON.
 func (c ResponseFunctionParameters) MarshalJSON() ([]byte, error) {
 	return json.Marshal(struct {
-		Type       string                     `json:"type"`
-		Properties map[string]json.RawMessage `json:"properties,omitempty"`
-		Required   []string                   `json:"required,omitempty"`
+		Type                 string                     `json:"type"`
+		Properties           map[string]json.RawMessage `json:"properties,omitempty"`
+		Required             []string                   `json:"required,omitempty"`
+		AdditionalProperties *bool                      `json:"additionalProperties,omitempty"` //nolint:tagliatelle
+		Defs                 map[string]json.RawMessage `json:"$defs,omitempty"`                //nolint:tagliatelle
 	}{
-		Type:       "object",
-		Properties: c.Properties,
-		Required:   c.Required,
+		Type:                 "object",
+		Properties:           c.Properties,
+		Required:             c.Required,
+		AdditionalProperties: c.AdditionalProperties,
+		Defs:                 c.Defs,
 	})
 }
 
@@ -157,11 +170,53 @@ type ResponseMessageContent struct {
 
 // MarshalJSON marshals the response message content into JSON.
 func (c ResponseMessageContent) MarshalJSON() ([]byte, error) {
-	if text, ok := c.GetText(); ok {
-		return json.Marshal(text)
+	if c.Content == nil {
+		return json.Marshal(nil) // Return null if there is no content
 	}
 
-	return json.Marshal(nil) // Return null if the content is not text
+	return json.Marshal(c.Content)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for ResponseMessageContent.
+func (c *ResponseMessageContent) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Type string `json:"type,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.Content = nil
+
+	switch aux.Type {
+	case "input_text", "output_text", "text":
+		var text ResponseMessageContentText
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		c.Content = text
+	case "input_image":
+		var image ResponseMessageContentImage
+		if err := json.Unmarshal(data, &image); err != nil {
+			return err
+		}
+		c.Content = image
+	case "input_file":
+		var file ResponseMessageContentFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return err
+		}
+		c.Content = file
+	case "input_audio":
+		var audio ResponseMessageContentAudio
+		if err := json.Unmarshal(data, &audio); err != nil {
+			return err
+		}
+		c.Content = audio
+	}
+
+	return nil
 }
 
 type isResponseMessageContent interface {
@@ -205,6 +260,15 @@ func (c ResponseMessageContent) GetFile() (ResponseMessageContentFile, bool) {
 	return ResponseMessageContentFile{}, false
 }
 
+// GetAudio returns the audio content of the response message content.
+func (c ResponseMessageContent) GetAudio() (ResponseMessageContentAudio, bool) {
+	if audio, ok := c.Content.(ResponseMessageContentAudio); ok {
+		return audio, true
+	}
+
+	return ResponseMessageContentAudio{}, false
+}
+
 // ResponseMessageContentText is the text content of a response message.
 type ResponseMessageContentText struct {
 	// Text is the text of the content.
@@ -229,6 +293,53 @@ type ResponseMessageContentImage struct {
 	Image Image `json:"image"`
 }
 
+// MarshalJSON marshals the response message content image into JSON.
+func (c ResponseMessageContentImage) MarshalJSON() ([]byte, error) {
+	imageURL := c.Image.URL
+	if c.Image.Base64 != "" {
+		imageURL = c.Image.DataURL()
+	}
+
+	return json.Marshal(struct {
+		Type     string      `json:"type"`
+		ImageURL string      `json:"image_url,omitempty"`
+		FileID   string      `json:"file_id,omitempty"`
+		Detail   ImageDetail `json:"detail,omitempty"`
+	}{
+		Type:     "input_image",
+		ImageURL: imageURL,
+		FileID:   c.Image.FileID,
+		Detail:   c.Image.Detail,
+	})
+}
+
+// UnmarshalJSON unmarshals the response message content image from JSON.
+func (c *ResponseMessageContentImage) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		ImageURL string      `json:"image_url,omitempty"`
+		FileID   string      `json:"file_id,omitempty"`
+		Detail   ImageDetail `json:"detail,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.Image = Image{
+		FileID: aux.FileID,
+		Detail: aux.Detail,
+	}
+
+	if mimeType, b64, ok := parseDataURL(aux.ImageURL); ok {
+		c.Image.MIMEType = mimeType
+		c.Image.Base64 = b64
+	} else {
+		c.Image.URL = aux.ImageURL
+	}
+
+	return nil
+}
+
 func (c ResponseMessageContentImage) isResponseMessageContent() {}
 
 // ResponseMessageContentFile is the file content of a response message.
@@ -236,16 +347,124 @@ type ResponseMessageContentFile struct {
 	File File `json:"file"`
 }
 
+// MarshalJSON marshals the response message content file into JSON.
+func (c ResponseMessageContentFile) MarshalJSON() ([]byte, error) {
+	var fileData string
+	if c.File.Base64 != "" {
+		fileData = c.File.DataURL()
+	}
+
+	return json.Marshal(struct {
+		Type     string `json:"type"`
+		FileID   string `json:"file_id,omitempty"`
+		FileURL  string `json:"file_url,omitempty"`
+		FileData string `json:"file_data,omitempty"`
+		Filename string `json:"filename,omitempty"`
+	}{
+		Type:     "input_file",
+		FileID:   c.File.FileID,
+		FileURL:  c.File.URL,
+		FileData: fileData,
+		Filename: c.File.Name,
+	})
+}
+
+// UnmarshalJSON unmarshals the response message content file from JSON.
+func (c *ResponseMessageContentFile) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		FileID   string `json:"file_id,omitempty"`
+		FileURL  string `json:"file_url,omitempty"`
+		FileData string `json:"file_data,omitempty"`
+		Filename string `json:"filename,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	c.File = File{
+		Name:   aux.Filename,
+		URL:    aux.FileURL,
+		FileID: aux.FileID,
+	}
+
+	if mimeType, b64, ok := parseDataURL(aux.FileData); ok {
+		c.File.MIMEType = mimeType
+		c.File.Base64 = b64
+	} else {
+		c.File.Base64 = aux.FileData
+	}
+
+	return nil
+}
+
 func (c ResponseMessageContentFile) isResponseMessageContent() {}
 
+// ResponseMessageContentAudio is the audio content of a response message.
+type ResponseMessageContentAudio struct {
+	Audio Audio `json:"input_audio"`
+}
+
+// MarshalJSON marshals the response message content audio into JSON.
+func (c ResponseMessageContentAudio) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string `json:"type"`
+		Audio Audio  `json:"input_audio"`
+	}{
+		Type:  "input_audio",
+		Audio: c.Audio,
+	})
+}
+
+func (c ResponseMessageContentAudio) isResponseMessageContent() {}
+
 // File is the file for the response message content.
 type File struct {
 	// Name is the name of the file.
 	Name string `json:"name"`
 	// URL is the URL of the file.
 	URL string `json:"url"`
+	// FileID is the ID of an uploaded file.
+	FileID string `json:"file_id,omitempty"`
+	// Base64 is the base64 encoding of the file.
+	Base64 string `json:"base64,omitempty"`
+	// MIMEType is the MIME type of the file.
+	MIMEType string `json:"mime_type,omitempty"`
+}
+
+// Encode encodes the file data into base64 and detects its MIME type.
+func (f *File) Encode(data []byte) string {
+	f.Base64 = base64.StdEncoding.EncodeToString(data)
+	f.MIMEType = detectContentType(data)
+
+	return f.Base64
+}
+
+// DataURL returns the data URL of the encoded file.
+func (f File) DataURL() string {
+	return dataURL(f.MIMEType, f.Base64)
 }
 
+// NewFile creates a new inline file from the given name and data (e.g. a PDF).
+func NewFile(name string, data []byte) File {
+	f := File{Name: name}
+	f.Encode(data)
+
+	return f
+}
+
+// ImageDetail is the detail level of an image.
+type ImageDetail string
+
+const (
+	// ImageDetailAuto lets the model decide the detail level.
+	ImageDetailAuto ImageDetail = "auto"
+	// ImageDetailLow is the low detail level.
+	ImageDetailLow ImageDetail = "low"
+	// ImageDetailHigh is the high detail level.
+	ImageDetailHigh ImageDetail = "high"
+)
+
 // Image is a type that represents an image.
 type Image struct {
 	// URL is the URL of the image.
@@ -254,14 +473,27 @@ type Image struct {
 	Base64 string `json:"base64,omitempty"`
 	// Name is the name of the image.
 	Name string `json:"name,omitempty"`
+	// FileID is the ID of an uploaded image file.
+	FileID string `json:"file_id,omitempty"`
+	// MIMEType is the MIME type of the image.
+	MIMEType string `json:"mime_type,omitempty"`
+	// Detail is the detail level of the image.
+	Detail ImageDetail `json:"detail,omitempty"`
 }
 
-// Encode encodes the image into a string.
-func (i Image) Encode(data []byte) string {
+// Encode encodes the image into base64 and detects its MIME type.
+func (i *Image) Encode(data []byte) string {
 	i.Base64 = base64.StdEncoding.EncodeToString(data)
+	i.MIMEType = detectContentType(data)
+
 	return i.Base64
 }
 
+// DataURL returns the data URL of the encoded image.
+func (i Image) DataURL() string {
+	return dataURL(i.MIMEType, i.Base64)
+}
+
 // NewImage creates a new image from the given data.
 func NewImage(data []byte) Image {
 	var img Image
@@ -269,6 +501,67 @@ func NewImage(data []byte) Image {
 	return img
 }
 
+// AudioFormat is the format of an audio input.
+type AudioFormat string
+
+const (
+	// AudioFormatWAV is the WAV audio format.
+	AudioFormatWAV AudioFormat = "wav"
+	// AudioFormatMP3 is the MP3 audio format.
+	AudioFormatMP3 AudioFormat = "mp3"
+)
+
+// Audio is a type that represents an audio input.
+type Audio struct {
+	// Data is the base64 encoding of the audio.
+	Data string `json:"data"`
+	// Format is the format of the audio.
+	Format AudioFormat `json:"format"`
+}
+
+// NewAudio creates a new audio input from the given data and format.
+func NewAudio(data []byte, format AudioFormat) Audio {
+	return Audio{
+		Data:   base64.StdEncoding.EncodeToString(data),
+		Format: format,
+	}
+}
+
+// detectContentType detects the MIME type of the data.
+func detectContentType(data []byte) string {
+	mimeType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	return mimeType
+}
+
+// dataURL returns a base64 data URL.
+func dataURL(mimeType, b64 string) string {
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	return "data:" + mimeType + ";base64," + b64
+}
+
+// parseDataURL parses a base64 data URL into its MIME type and data.
+func parseDataURL(s string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(s, "data:")
+	if !ok {
+		return "", "", false
+	}
+
+	meta, b64, ok := strings.Cut(rest, ",")
+	if !ok {
+		return "", "", false
+	}
+
+	mimeType, ok := strings.CutSuffix(meta, ";base64")
+	if !ok {
+		return "", "", false
+	}
+
+	return mimeType, b64, true
+}
+
 // ResponseInput is the message for chat completion.
 type ResponseInput struct {
 	// Role is the role of the message sender.
@@ -277,8 +570,146 @@ type ResponseInput struct {
 	Content []ResponseMessageContent `json:"content"`
 	// Name is the name of the message sender (optional).
 	Name string `json:"name,omitempty"`
+	// Item is an input item other than a message (e.g. a function call output).
+	// If set, it is sent instead of the message.
+	Item isResponseInputItem `json:"-"`
+}
+
+type isResponseInputItem interface {
+	isResponseInputItem()
+}
+
+// MarshalJSON marshals the response input into JSON.
+func (c ResponseInput) MarshalJSON() ([]byte, error) {
+	if c.Item != nil {
+		return json.Marshal(c.Item)
+	}
+
+	return json.Marshal(struct {
+		Role    Role                     `json:"role"`
+		Content []ResponseMessageContent `json:"content"`
+		Name    string                   `json:"name,omitempty"`
+	}{
+		Role:    c.Role,
+		Content: c.Content,
+		Name:    c.Name,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for ResponseInput.
+// Messages with a string content are unmarshalled as text content.
+func (c *ResponseInput) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Type    string          `json:"type,omitempty"`
+		ID      string          `json:"id,omitempty"`
+		Role    Role            `json:"role"`
+		Content json.RawMessage `json:"content"`
+		Name    string          `json:"name,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*c = ResponseInput{}
+
+	switch {
+	case aux.Type == "function_call_output":
+		var output ResponseInputFunctionCallOutput
+		if err := json.Unmarshal(data, &output); err != nil {
+			return err
+		}
+		c.Item = output
+
+		return nil
+	case aux.Type != "" && (aux.Type != OutputTypeMessage || aux.ID != ""):
+		var output ResponseOutput
+		if err := json.Unmarshal(data, &output); err != nil {
+			return err
+		}
+		c.Item, _ = output.Output.(isResponseInputItem)
+
+		return nil
+	}
+
+	c.Role = aux.Role
+	c.Name = aux.Name
+
+	var text string
+	if err := json.Unmarshal(aux.Content, &text); err == nil {
+		c.Content = []ResponseMessageContent{{Content: ResponseMessageContentText{Text: text}}}
+		return nil
+	}
+
+	if len(aux.Content) > 0 {
+		return json.Unmarshal(aux.Content, &c.Content)
+	}
+
+	return nil
 }
 
+// GetFunctionCallOutput returns the function call output of the response input.
+func (c ResponseInput) GetFunctionCallOutput() (ResponseInputFunctionCallOutput, bool) {
+	if output, ok := c.Item.(ResponseInputFunctionCallOutput); ok {
+		return output, true
+	}
+
+	return ResponseInputFunctionCallOutput{}, false
+}
+
+// NewInputMessage creates a new text message input with the given role.
+func NewInputMessage(role Role, text string) ResponseInput {
+	return ResponseInput{
+		Role:    role,
+		Content: []ResponseMessageContent{{Content: ResponseMessageContentText{Text: text}}},
+	}
+}
+
+// NewInputItem creates a new input from an output item of a previous response
+// (e.g. a function call or a reasoning item). It returns false if the output
+// item can not be used as input.
+func NewInputItem(output ResponseOutput) (ResponseInput, bool) {
+	item, ok := output.Output.(isResponseInputItem)
+	if !ok {
+		return ResponseInput{}, false
+	}
+
+	return ResponseInput{Item: item}, true
+}
+
+// NewFunctionCallOutput creates a new input with the output of a function call.
+func NewFunctionCallOutput(callID, output string) ResponseInput {
+	return ResponseInput{
+		Item: ResponseInputFunctionCallOutput{
+			CallID: callID,
+			Output: output,
+		},
+	}
+}
+
+// ResponseInputFunctionCallOutput is the output of a function call sent back to the model.
+type ResponseInputFunctionCallOutput struct {
+	// CallID is the unique identifier of the function call.
+	CallID string `json:"call_id"`
+	// Output is the output of the function call.
+	Output string `json:"output"`
+}
+
+// MarshalJSON marshals the function call output into JSON.
+func (c ResponseInputFunctionCallOutput) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string `json:"type"`
+		CallID string `json:"call_id"`
+		Output string `json:"output"`
+	}{
+		Type:   "function_call_output",
+		CallID: c.CallID,
+		Output: c.Output,
+	})
+}
+
+func (ResponseInputFunctionCallOutput) isResponseInputItem() {}
+
 // ResponseRequest is the request for chat completion.
 type ResponseRequest struct {
 	// Model is the model for the chat completion request.
@@ -291,16 +722,45 @@ type ResponseRequest struct {
 	Tools []ResponseTool `json:"tools,omitempty"`
 	// ToolChoice is the tool choice for the chat completion request.
 	ToolChoice ToolChoice `json:"tool_choice,omitempty"`
-	// MaxTokens is the maximum number of tokens for the chat completion request.
-	MaxTokens *int `json:"max_tokens,omitzero"`
+	// MaxTokens is the maximum number of output tokens for the chat completion request.
+	MaxTokens *int `json:"max_output_tokens,omitzero"`
 	// Temperature is the sampling temperature
 	Temperature *float32 `json:"temperature,omitzero"`
 	// Stream is a flag to enable streaming
 	Stream bool `json:"stream,omitempty"`
 	// TopP is the nucleus sampling parameter
 	TopP *float64 `json:"top_p,omitzero"`
-	// TopK is the number of top tokens to sample from
-	TopK *int `json:"top_k,omitzero"`
+	// TopK is the number of top tokens to sample from. It is not part of the
+	// Responses API and only sent by providers that support it (e.g. Anthropic).
+	TopK *int `json:"-"`
+	// Text is the configuration of the text output (e.g. structured outputs)
+	Text *ResponseText `json:"text,omitempty"`
+	// PreviousResponseID is the ID of the previous response to continue the conversation from
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
+	// Store is a flag to store the response for later retrieval
+	Store *bool `json:"store,omitempty"`
+	// Metadata is the set of key-value pairs attached to the response
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Conversation is the ID of the conversation the response belongs to
+	Conversation string `json:"conversation,omitempty"`
+	// Background is a flag to run the response in the background
+	Background bool `json:"background,omitempty"`
+	// Extensions are the provider specific fields of the request
+	Extensions []Extension `json:"-"`
+}
+
+// ErrUnsupported is returned when a request has parameters the Responses API does not support.
+var ErrUnsupported = errors.New("openai: unsupported")
+
+// Validate returns ErrUnsupported if the request has parameters that are not
+// part of the Responses API (e.g. TopK). Providers of the Responses API reject
+// them instead of dropping them silently.
+func (r *ResponseRequest) Validate() error {
+	if r.TopK != nil {
+		return fmt.Errorf("%w: top_k", ErrUnsupported)
+	}
+
+	return nil
 }
 
 // RequestOpt is a function type for configuring the ResponseRequest.
@@ -337,3 +797,41 @@ func WithTools(tools ...ResponseTool) RequestOpt {
 		req.Tools = tools
 	}
 }
+
+// WithTextFormat sets the format of the text output for the chat completion request.
+func WithTextFormat(format ResponseTextFormat) RequestOpt {
+	return func(req *ResponseRequest) {
+		if req.Text == nil {
+			req.Text = &ResponseText{}
+		}
+		req.Text.Format = &format
+	}
+}
+
+// WithPreviousResponseID sets the ID of the previous response for the chat completion request.
+func WithPreviousResponseID(id string) RequestOpt {
+	return func(req *ResponseRequest) {
+		req.PreviousResponseID = id
+	}
+}
+
+// WithStore sets the flag to store the response for the chat completion request.
+func WithStore(store bool) RequestOpt {
+	return func(req *ResponseRequest) {
+		req.Store = &store
+	}
+}
+
+// WithMetadata sets the metadata for the chat completion request.
+func WithMetadata(metadata map[string]string) RequestOpt {
+	return func(req *ResponseRequest) {
+		req.Metadata = metadata
+	}
+}
+
+// WithConversation sets the conversation for the chat completion request.
+func WithConversation(id string) RequestOpt {
+	return func(req *ResponseRequest) {
+		req.Conversation = id
+	}
+}
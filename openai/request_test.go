@@ -0,0 +1,69 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseMessageContent(t *testing.T) {
+	pdf := openai.NewFile("scan.pdf", []byte("%PDF-1.7"))
+	png := openai.NewImage([]byte("\x89PNG\x0D\x0A\x1A\x0A"))
+	png.Detail = openai.ImageDetailHigh
+
+	tests := []struct {
+		name    string
+		content openai.ResponseMessageContent
+		want    string
+	}{
+		{
+			name:    "empty content",
+			content: openai.ResponseMessageContent{},
+			want:    `null`,
+		},
+		{
+			name:    "text",
+			content: openai.ResponseMessageContent{Content: openai.ResponseMessageContentText{Text: "Hello"}},
+			want:    `{"type":"input_text","text":"Hello"}`,
+		},
+		{
+			name:    "image url",
+			content: openai.ResponseMessageContent{Content: openai.ResponseMessageContentImage{Image: openai.Image{URL: "https://example.com/cat.png", Detail: openai.ImageDetailLow}}},
+			want:    `{"type":"input_image","image_url":"https://example.com/cat.png","detail":"low"}`,
+		},
+		{
+			name:    "image base64",
+			content: openai.ResponseMessageContent{Content: openai.ResponseMessageContentImage{Image: png}},
+			want:    `{"type":"input_image","image_url":"data:image/png;base64,iVBORw0KGgo=","detail":"high"}`,
+		},
+		{
+			name:    "file id",
+			content: openai.ResponseMessageContent{Content: openai.ResponseMessageContentFile{File: openai.File{FileID: "file-123"}}},
+			want:    `{"type":"input_file","file_id":"file-123"}`,
+		},
+		{
+			name:    "file base64",
+			content: openai.ResponseMessageContent{Content: openai.ResponseMessageContentFile{File: pdf}},
+			want:    `{"type":"input_file","file_data":"data:application/pdf;base64,JVBERi0xLjc=","filename":"scan.pdf"}`,
+		},
+		{
+			name:    "audio",
+			content: openai.ResponseMessageContent{Content: openai.ResponseMessageContentAudio{Audio: openai.NewAudio([]byte("RIFF"), openai.AudioFormatWAV)}},
+			want:    `{"type":"input_audio","input_audio":{"data":"UklGRg==","format":"wav"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.content)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+
+			var got openai.ResponseMessageContent
+			require.NoError(t, json.Unmarshal(b, &got))
+			require.Equal(t, tt.content, got)
+		})
+	}
+}
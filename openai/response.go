@@ -1,10 +1,13 @@
 package openai
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/katallaxie/pkg/utilx"
+	"github.com/katallaxie/prompts"
 )
 
 // FinishReason is the reason for finishing a chat completion.
@@ -17,6 +20,8 @@ const (
 	FinishReasonLength FinishReason = "length"
 	// FinishReasonContentFilter indicates that the chat completion was finished because the content filter was triggered.
 	FinishReasonContentFilter FinishReason = "content_filter"
+	// FinishReasonToolCalls indicates that the chat completion was finished because the model called tools.
+	FinishReasonToolCalls FinishReason = "tool_calls"
 	// FinishReasonUnknown indicates that the chat completion was finished for an unknown reason.
 	FinishReasonUnknown FinishReason = ""
 )
@@ -65,7 +70,7 @@ func (c *ChatCompletionMessageToolCall) UnmarshalJSON(data []byte) error {
 
 	c.ToolCall = nil
 
-	if utilx.NotNil(aux.Function) {
+	if aux.Function != nil {
 		c.ToolCall = ChatCompletionMessageFunctionToolCall{
 			ID:       aux.ID,
 			Type:     aux.Type,
@@ -73,7 +78,7 @@ func (c *ChatCompletionMessageToolCall) UnmarshalJSON(data []byte) error {
 		}
 	}
 
-	if utilx.NotNil(aux.Custom) {
+	if aux.Custom != nil {
 		c.ToolCall = ChatCompletionMessageCustomToolCall{
 			ID:     aux.ID,
 			Type:   aux.Type,
@@ -84,12 +89,35 @@ func (c *ChatCompletionMessageToolCall) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON implements the json.Marshaler interface for ChatCompletionMessageToolCall.
+func (c ChatCompletionMessageToolCall) MarshalJSON() ([]byte, error) {
+	switch call := c.ToolCall.(type) {
+	case ChatCompletionMessageFunctionToolCall:
+		call.Type = utilx.IfElse(call.Type == "", "function", call.Type)
+		return json.Marshal(call)
+	case ChatCompletionMessageCustomToolCall:
+		call.Type = utilx.IfElse(call.Type == "", "custom", call.Type)
+		return json.Marshal(call)
+	default:
+		return json.Marshal(nil)
+	}
+}
+
+// GetFunction returns the function tool call.
+func (c ChatCompletionMessageToolCall) GetFunction() (ChatCompletionMessageFunctionToolCall, bool) {
+	if call, ok := c.ToolCall.(ChatCompletionMessageFunctionToolCall); ok {
+		return call, true
+	}
+
+	return ChatCompletionMessageFunctionToolCall{}, false
+}
+
 // ChatCompletionMessageFunction represents a function in a chat completion message.
 type ChatCompletionMessageFunction struct {
 	// Name is the name of the function.
 	Name string `json:"name,omitempty"`
-	// Arguments is the arguments for the function.
-	Arguments map[string]any `json:"arguments,omitempty"`
+	// Arguments is the JSON encoded arguments for the function.
+	Arguments string `json:"arguments,omitempty"`
 }
 
 // ChatCompletionMessageCustomToolCall represents a custom tool call in a chat completion message.
@@ -108,7 +136,7 @@ type ChatCompletionMessageCustom struct {
 	// Name is the name of the custom tool.
 	Name string `json:"name,omitempty"`
 	// Input is the input for the custom tool.
-	Input map[string]any `json:"input,omitempty"`
+	Input string `json:"input,omitempty"`
 }
 
 // ChatCompletionChoiceIndex is the index for the chat completion.
@@ -121,6 +149,10 @@ type ChatCompletionChoiceIndex struct {
 	Annotations []ChatCompletionAnnotation `json:"annotations,omitempty"`
 	// ToolCalls is the tool calls for the message.
 	ToolCalls []ChatCompletionMessageToolCall `json:"tool_calls,omitempty"`
+	// Refusal is the refusal message of the model.
+	Refusal string `json:"refusal,omitempty"`
+	// ReasoningContent is the reasoning of the model sent by some servers (e.g. vLLM).
+	ReasoningContent string `json:"reasoning_content,omitempty"`
 }
 
 // ChatCompletionAnnotation is the annotation for the chat completion.
@@ -151,6 +183,10 @@ type CompletionUsage struct {
 	CompletionTokens int `json:"completion_tokens,omitempty"`
 	// TotalTokens is the total number of tokens used in the chat completion.
 	TotalTokens int `json:"total_tokens,omitempty"`
+	// PromptTokensDetails is the breakdown of the prompt tokens.
+	PromptTokensDetails ResponseInputTokensDetails `json:"prompt_tokens_details,omitzero"`
+	// CompletionTokensDetails is the breakdown of the completion tokens.
+	CompletionTokensDetails ResponseOutputTokensDetails `json:"completion_tokens_details,omitzero"`
 }
 
 type ResponseStatus string
@@ -162,8 +198,25 @@ const (
 	ResponseStatusCompleted ResponseStatus = "completed"
 	// ResponseStatusIncomplete indicates that the response is incomplete.
 	ResponseStatusIncomplete ResponseStatus = "incomplete"
+	// ResponseStatusFailed indicates that the response failed.
+	ResponseStatusFailed ResponseStatus = "failed"
+	// ResponseStatusCancelled indicates that the response was cancelled.
+	ResponseStatusCancelled ResponseStatus = "cancelled"
+	// ResponseStatusQueued indicates that the response is queued.
+	ResponseStatusQueued ResponseStatus = "queued"
 )
 
+// Done returns true if the response reached a final status. A missing
+// status is not done, as it is unknown whether the response is still running.
+func (s ResponseStatus) Done() bool {
+	switch s {
+	case ResponseStatusCompleted, ResponseStatusIncomplete, ResponseStatusFailed, ResponseStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
 // ResponseOutput represents the output of the chat completion response.
 type ResponseOutput struct {
 	// Output is the output of the chat completion response.
@@ -174,9 +227,25 @@ type isOutput interface {
 	isOutput()
 }
 
+// Available output item types.
+const (
+	// OutputTypeMessage is the type of a message output.
+	OutputTypeMessage = "message"
+	// OutputTypeFunctionCall is the type of a function call output.
+	OutputTypeFunctionCall = "function_call"
+	// OutputTypeReasoning is the type of a reasoning output.
+	OutputTypeReasoning = "reasoning"
+	// OutputTypeWebSearchCall is the type of a web search call output.
+	OutputTypeWebSearchCall = "web_search_call"
+	// OutputTypeFileSearchCall is the type of a file search call output.
+	OutputTypeFileSearchCall = "file_search_call"
+)
+
+// UnmarshalJSON implements the json.Unmarshaler interface for ResponseOutput.
+// Unknown output items are preserved as ResponseOutputUnknown.
 func (r *ResponseOutput) UnmarshalJSON(data []byte) error {
 	var aux struct {
-		Message string `json:"type,omitempty"`
+		Type string `json:"type,omitempty"`
 	}
 
 	if err := json.Unmarshal(data, &aux); err != nil {
@@ -185,24 +254,80 @@ func (r *ResponseOutput) UnmarshalJSON(data []byte) error {
 
 	r.Output = nil
 
-	switch aux.Message {
-	case "function_call":
+	switch aux.Type {
+	case OutputTypeFunctionCall:
 		var fnCall ResponseOutputFunctionCall
 		if err := json.Unmarshal(data, &fnCall); err != nil {
 			return err
 		}
 		r.Output = fnCall
-	case "message":
+	case OutputTypeMessage:
 		var msg ResponseOutputMessage
 		if err := json.Unmarshal(data, &msg); err != nil {
 			return err
 		}
 		r.Output = msg
+	case OutputTypeReasoning:
+		var reasoning ResponseOutputReasoning
+		if err := json.Unmarshal(data, &reasoning); err != nil {
+			return err
+		}
+		r.Output = reasoning
+	case OutputTypeWebSearchCall:
+		var search ResponseOutputWebSearchCall
+		if err := json.Unmarshal(data, &search); err != nil {
+			return err
+		}
+		r.Output = search
+	case OutputTypeFileSearchCall:
+		var search ResponseOutputFileSearchCall
+		if err := json.Unmarshal(data, &search); err != nil {
+			return err
+		}
+		r.Output = search
+	default:
+		r.Output = ResponseOutputUnknown{Type: aux.Type, Raw: bytes.Clone(data)}
 	}
 
 	return nil
 }
 
+// MarshalJSON marshals the response output into JSON.
+func (r ResponseOutput) MarshalJSON() ([]byte, error) {
+	if r.Output == nil {
+		return json.Marshal(nil)
+	}
+
+	return json.Marshal(r.Output)
+}
+
+// GetMessage returns the message of the response output.
+func (r ResponseOutput) GetMessage() (ResponseOutputMessage, bool) {
+	if msg, ok := r.Output.(ResponseOutputMessage); ok {
+		return msg, true
+	}
+
+	return ResponseOutputMessage{}, false
+}
+
+// GetFunctionCall returns the function call of the response output.
+func (r ResponseOutput) GetFunctionCall() (ResponseOutputFunctionCall, bool) {
+	if fnCall, ok := r.Output.(ResponseOutputFunctionCall); ok {
+		return fnCall, true
+	}
+
+	return ResponseOutputFunctionCall{}, false
+}
+
+// GetReasoning returns the reasoning of the response output.
+func (r ResponseOutput) GetReasoning() (ResponseOutputReasoning, bool) {
+	if reasoning, ok := r.Output.(ResponseOutputReasoning); ok {
+		return reasoning, true
+	}
+
+	return ResponseOutputReasoning{}, false
+}
+
 // ResponseOutputFunctionCall represents a function call output in the chat completion response.
 type ResponseOutputFunctionCall struct {
 	// ID is the unique identifier for the function call output.
@@ -212,15 +337,30 @@ type ResponseOutputFunctionCall struct {
 	// Status is the status of the message output.
 	Status ResponseStatus `json:"status,omitempty"`
 	// Role is the role of the message sender.
-	Role Role `json:"role"`
+	Role Role `json:"role,omitempty"`
 	// Name is the name of the function being called.
 	Name string `json:"name,omitempty"`
 	// Arguments is the arguments for the function being called.
 	Arguments string `json:"arguments,omitempty"`
 }
 
+// MarshalJSON marshals the function call output into JSON.
+func (c ResponseOutputFunctionCall) MarshalJSON() ([]byte, error) {
+	type alias ResponseOutputFunctionCall
+
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{
+		Type:  OutputTypeFunctionCall,
+		alias: alias(c),
+	})
+}
+
 func (ResponseOutputFunctionCall) isOutput() {}
 
+func (ResponseOutputFunctionCall) isResponseInputItem() {}
+
 // ResponseOutputMessage represents a message output in the chat completion response.
 type ResponseOutputMessage struct {
 	// ID is the unique identifier for the message output.
@@ -233,8 +373,162 @@ type ResponseOutputMessage struct {
 	ResponseOutputMessageContent []ResponseOutputMessageContent `json:"content,omitempty"`
 }
 
+// MarshalJSON marshals the message output into JSON.
+func (c ResponseOutputMessage) MarshalJSON() ([]byte, error) {
+	type alias ResponseOutputMessage
+
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{
+		Type:  OutputTypeMessage,
+		alias: alias(c),
+	})
+}
+
+// Text returns the concatenated text of the message output.
+func (c ResponseOutputMessage) Text() string {
+	var sb strings.Builder
+
+	for _, content := range c.ResponseOutputMessageContent {
+		if text, ok := content.GetText(); ok {
+			sb.WriteString(text.Text)
+		}
+	}
+
+	return sb.String()
+}
+
 func (ResponseOutputMessage) isOutput() {}
 
+func (ResponseOutputMessage) isResponseInputItem() {}
+
+// ResponseOutputReasoning represents a reasoning output in the chat completion response.
+type ResponseOutputReasoning struct {
+	// ID is the unique identifier for the reasoning output.
+	ID string `json:"id,omitempty"`
+	// Status is the status of the reasoning output.
+	Status ResponseStatus `json:"status,omitempty"`
+	// Summary is the summary of the reasoning.
+	Summary []ResponseOutputReasoningText `json:"summary"`
+	// Content is the reasoning text.
+	Content []ResponseOutputReasoningText `json:"content,omitempty"`
+	// EncryptedContent is the encrypted content of the reasoning.
+	EncryptedContent string `json:"encrypted_content,omitempty"`
+}
+
+// MarshalJSON marshals the reasoning output into JSON.
+func (c ResponseOutputReasoning) MarshalJSON() ([]byte, error) {
+	type alias ResponseOutputReasoning
+
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{
+		Type:  OutputTypeReasoning,
+		alias: alias(c),
+	})
+}
+
+func (ResponseOutputReasoning) isOutput() {}
+
+func (ResponseOutputReasoning) isResponseInputItem() {}
+
+// ResponseOutputReasoningText represents a summary or text part of a reasoning output.
+type ResponseOutputReasoningText struct {
+	// Type is the type of the text (e.g. "summary_text" or "reasoning_text").
+	Type string `json:"type"`
+	// Text is the text of the reasoning.
+	Text string `json:"text"`
+}
+
+// ResponseOutputWebSearchCall represents a web search call output in the chat completion response.
+type ResponseOutputWebSearchCall struct {
+	// ID is the unique identifier for the web search call.
+	ID string `json:"id,omitempty"`
+	// Status is the status of the web search call.
+	Status string `json:"status,omitempty"`
+	// Action is the action taken by the web search call.
+	Action json.RawMessage `json:"action,omitempty"`
+}
+
+// MarshalJSON marshals the web search call output into JSON.
+func (c ResponseOutputWebSearchCall) MarshalJSON() ([]byte, error) {
+	type alias ResponseOutputWebSearchCall
+
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{
+		Type:  OutputTypeWebSearchCall,
+		alias: alias(c),
+	})
+}
+
+func (ResponseOutputWebSearchCall) isOutput() {}
+
+func (ResponseOutputWebSearchCall) isResponseInputItem() {}
+
+// ResponseOutputFileSearchCall represents a file search call output in the chat completion response.
+type ResponseOutputFileSearchCall struct {
+	// ID is the unique identifier for the file search call.
+	ID string `json:"id,omitempty"`
+	// Status is the status of the file search call.
+	Status string `json:"status,omitempty"`
+	// Queries is the queries used to search for files.
+	Queries []string `json:"queries,omitempty"`
+	// Results is the results of the file search call.
+	Results []ResponseFileSearchResult `json:"results,omitempty"`
+}
+
+// MarshalJSON marshals the file search call output into JSON.
+func (c ResponseOutputFileSearchCall) MarshalJSON() ([]byte, error) {
+	type alias ResponseOutputFileSearchCall
+
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		alias
+	}{
+		Type:  OutputTypeFileSearchCall,
+		alias: alias(c),
+	})
+}
+
+func (ResponseOutputFileSearchCall) isOutput() {}
+
+func (ResponseOutputFileSearchCall) isResponseInputItem() {}
+
+// ResponseFileSearchResult represents a result of a file search call.
+type ResponseFileSearchResult struct {
+	// FileID is the ID of the file.
+	FileID string `json:"file_id,omitempty"`
+	// Filename is the name of the file.
+	Filename string `json:"filename,omitempty"`
+	// Score is the relevance score of the file.
+	Score float64 `json:"score,omitempty"`
+	// Text is the text retrieved from the file.
+	Text string `json:"text,omitempty"`
+	// Attributes is the attributes of the file.
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// ResponseOutputUnknown represents an output item of a type that is not modeled.
+type ResponseOutputUnknown struct {
+	// Type is the type of the output item.
+	Type string `json:"type"`
+	// Raw is the raw JSON of the output item.
+	Raw json.RawMessage `json:"-"`
+}
+
+// MarshalJSON marshals the unknown output item into its raw JSON.
+func (c ResponseOutputUnknown) MarshalJSON() ([]byte, error) {
+	return c.Raw, nil
+}
+
+func (ResponseOutputUnknown) isOutput() {}
+
+func (ResponseOutputUnknown) isResponseInputItem() {}
+
 // ResponseOutputMessageContent represents the content of a message output in the chat completion response.
 type ResponseOutputMessageContent struct {
 	// Content is the content of the message output.
@@ -246,6 +540,7 @@ type isResponseOutputMessageContent interface {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface for ResponseOutputMessageContent.
+// Unknown content parts are preserved as ResponseOutputMessageContentUnknown.
 func (c *ResponseOutputMessageContent) UnmarshalJSON(data []byte) error {
 	var aux struct {
 		Type string `json:"type,omitempty"`
@@ -257,27 +552,132 @@ func (c *ResponseOutputMessageContent) UnmarshalJSON(data []byte) error {
 
 	c.Content = nil
 
-	if aux.Type == "text" {
+	switch aux.Type {
+	case "output_text", "text":
 		var text ResponseOutputMessageContentText
 		if err := json.Unmarshal(data, &text); err != nil {
 			return err
 		}
 		c.Content = text
+	case "refusal":
+		var refusal ResponseOutputMessageContentRefusal
+		if err := json.Unmarshal(data, &refusal); err != nil {
+			return err
+		}
+		c.Content = refusal
+	default:
+		c.Content = ResponseOutputMessageContentUnknown{Type: aux.Type, Raw: bytes.Clone(data)}
 	}
 
 	return nil
 }
 
+// MarshalJSON marshals the content of a message output into JSON.
+func (c ResponseOutputMessageContent) MarshalJSON() ([]byte, error) {
+	if c.Content == nil {
+		return json.Marshal(nil)
+	}
+
+	return json.Marshal(c.Content)
+}
+
+// GetText returns the text content of the message output.
+func (c ResponseOutputMessageContent) GetText() (ResponseOutputMessageContentText, bool) {
+	if text, ok := c.Content.(ResponseOutputMessageContentText); ok {
+		return text, true
+	}
+
+	return ResponseOutputMessageContentText{}, false
+}
+
+// GetRefusal returns the refusal content of the message output.
+func (c ResponseOutputMessageContent) GetRefusal() (ResponseOutputMessageContentRefusal, bool) {
+	if refusal, ok := c.Content.(ResponseOutputMessageContentRefusal); ok {
+		return refusal, true
+	}
+
+	return ResponseOutputMessageContentRefusal{}, false
+}
+
 func (ResponseOutputMessageContent) isResponseOutputMessageContent() {}
 
 // ResponseOutputMessageContentText represents a text content of a message output in the chat completion response.
 type ResponseOutputMessageContentText struct {
 	// Text is the text content of the message output.
 	Text string `json:"text,omitempty"`
+	// Annotations is the annotations of the text content.
+	Annotations []ResponseOutputAnnotation `json:"annotations,omitempty"`
+}
+
+// MarshalJSON marshals the text content into JSON.
+func (c ResponseOutputMessageContentText) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type        string                     `json:"type"`
+		Text        string                     `json:"text"`
+		Annotations []ResponseOutputAnnotation `json:"annotations"`
+	}{
+		Type:        "output_text",
+		Text:        c.Text,
+		Annotations: utilx.IfElse(c.Annotations == nil, []ResponseOutputAnnotation{}, c.Annotations),
+	})
 }
 
 func (ResponseOutputMessageContentText) isResponseOutputMessageContent() {}
 
+// ResponseOutputMessageContentRefusal represents a refusal content of a message output in the chat completion response.
+type ResponseOutputMessageContentRefusal struct {
+	// Refusal is the refusal explanation of the model.
+	Refusal string `json:"refusal,omitempty"`
+}
+
+// MarshalJSON marshals the refusal content into JSON.
+func (c ResponseOutputMessageContentRefusal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type    string `json:"type"`
+		Refusal string `json:"refusal"`
+	}{
+		Type:    "refusal",
+		Refusal: c.Refusal,
+	})
+}
+
+func (ResponseOutputMessageContentRefusal) isResponseOutputMessageContent() {}
+
+// ResponseOutputMessageContentUnknown represents a content part of a type that is not modeled.
+type ResponseOutputMessageContentUnknown struct {
+	// Type is the type of the content part.
+	Type string `json:"type"`
+	// Raw is the raw JSON of the content part.
+	Raw json.RawMessage `json:"-"`
+}
+
+// MarshalJSON marshals the unknown content part into its raw JSON.
+func (c ResponseOutputMessageContentUnknown) MarshalJSON() ([]byte, error) {
+	return c.Raw, nil
+}
+
+func (ResponseOutputMessageContentUnknown) isResponseOutputMessageContent() {}
+
+// ResponseOutputAnnotation represents an annotation of a text content.
+type ResponseOutputAnnotation struct {
+	// Type is the type of the annotation (e.g. "url_citation" or "file_citation").
+	Type string `json:"type"`
+	// URL is the URL of an URL citation.
+	URL string `json:"url,omitempty"`
+	// Title is the title of an URL citation.
+	Title string `json:"title,omitempty"`
+	// StartIndex is the start index of the citation in the text.
+	StartIndex int `json:"start_index,omitempty"`
+	// EndIndex is the end index of the citation in the text.
+	EndIndex int `json:"end_index,omitempty"`
+	// FileID is the ID of a cited file.
+	FileID string `json:"file_id,omitempty"`
+	// Filename is the name of a cited file.
+	Filename string `json:"filename,omitempty"`
+	// Index is the index of a file citation in the text.
+	Index int `json:"index,omitempty"`
+}
+
 // Response represents a response structure for chat completion API.
 type Response struct {
 	// ID is the unique identifier for the response
@@ -302,6 +702,233 @@ type Response struct {
 
 	// Output is the output of the chat completion response
 	Output []ResponseOutput `json:"output,omitempty"`
+
+	// Model is the model used to generate the response
+	Model string `json:"model,omitempty"`
+
+	// Error is the error of a failed response
+	Error *ResponseError `json:"error,omitempty"`
+
+	// IncompleteDetails is the reason why the response is incomplete
+	IncompleteDetails *ResponseIncompleteDetails `json:"incomplete_details,omitempty"`
+
+	// PreviousResponseID is the ID of the previous response in a conversation
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
+
+	// Conversation is the conversation the response belongs to
+	Conversation *ResponseConversation `json:"conversation,omitempty"`
+
+	// Store indicates whether the response is stored for later retrieval
+	Store bool `json:"store,omitempty"`
+
+	// Background indicates whether the response runs in the background
+	Background bool `json:"background,omitempty"`
+
+	// Metadata is the set of key-value pairs attached to the response
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Reasoning is the reasoning configuration of the response
+	Reasoning *ResponseReasoning `json:"reasoning,omitempty"`
+
+	// Text is the text configuration of the response
+	Text *ResponseText `json:"text,omitempty"`
+
+	// Temperature is the sampling temperature used for the response
+	Temperature *float32 `json:"temperature,omitempty"`
+
+	// TopP is the nucleus sampling parameter used for the response
+	TopP *float64 `json:"top_p,omitempty"`
+
+	// MaxOutputTokens is the upper bound for the number of generated tokens
+	MaxOutputTokens *int `json:"max_output_tokens,omitempty"`
+
+	// ServiceTier is the service tier used to process the response
+	ServiceTier string `json:"service_tier,omitempty"`
+
+	// User is the identifier of the end-user
+	User string `json:"user,omitempty"`
+
+	// Usage is the token usage of the response
+	Usage *ResponseUsage `json:"usage,omitempty"`
+
+	// Extensions are the provider specific fields of the response
+	Extensions []Extension `json:"-"`
+}
+
+// Err returns the error of a failed response as a *prompts.PromptError or nil.
+func (r *Response) Err() error {
+	if r.Error == nil {
+		return nil
+	}
+
+	perr := &prompts.PromptError{ErrorCode: r.Error.Code}
+	perr.JSON.Message = r.Error.Message
+
+	return perr
+}
+
+// Truncated returns true if the response is incomplete because it reached
+// the maximum number of output tokens.
+func (r *Response) Truncated() bool {
+	return r.Status == ResponseStatusIncomplete &&
+		r.IncompleteDetails != nil &&
+		r.IncompleteDetails.Reason == IncompleteReasonMaxOutputTokens
+}
+
+// ResponseConversation represents the conversation of a response.
+type ResponseConversation struct {
+	// ID is the unique identifier of the conversation.
+	ID string `json:"id"`
+}
+
+// ResponseError represents the error of a failed response.
+type ResponseError struct {
+	// Code is the error code.
+	Code string `json:"code,omitempty"`
+	// Message is the error message.
+	Message string `json:"message,omitempty"`
+}
+
+// IncompleteReason is the reason why a response is incomplete.
+type IncompleteReason string
+
+const (
+	// IncompleteReasonMaxOutputTokens indicates that the maximum number of output tokens was reached.
+	IncompleteReasonMaxOutputTokens IncompleteReason = "max_output_tokens"
+	// IncompleteReasonContentFilter indicates that the content filter was triggered.
+	IncompleteReasonContentFilter IncompleteReason = "content_filter"
+)
+
+// ResponseIncompleteDetails represents the details of an incomplete response.
+type ResponseIncompleteDetails struct {
+	// Reason is the reason why the response is incomplete.
+	Reason IncompleteReason `json:"reason,omitempty"`
+}
+
+// ReasoningEffort is the effort spent on reasoning.
+type ReasoningEffort string
+
+const (
+	// ReasoningEffortMinimal is the minimal reasoning effort.
+	ReasoningEffortMinimal ReasoningEffort = "minimal"
+	// ReasoningEffortLow is the low reasoning effort.
+	ReasoningEffortLow ReasoningEffort = "low"
+	// ReasoningEffortMedium is the medium reasoning effort.
+	ReasoningEffortMedium ReasoningEffort = "medium"
+	// ReasoningEffortHigh is the high reasoning effort.
+	ReasoningEffortHigh ReasoningEffort = "high"
+)
+
+// ResponseReasoning represents the reasoning configuration.
+type ResponseReasoning struct {
+	// Effort is the effort spent on reasoning.
+	Effort ReasoningEffort `json:"effort,omitempty"`
+	// Summary is the kind of reasoning summary (e.g. "auto", "concise" or "detailed").
+	Summary string `json:"summary,omitempty"`
+}
+
+// ResponseText represents the text configuration.
+type ResponseText struct {
+	// Format is the format of the text output.
+	Format *ResponseTextFormat `json:"format,omitempty"`
+	// Verbosity is the verbosity of the text output.
+	Verbosity string `json:"verbosity,omitempty"`
+}
+
+// ResponseTextFormatType is the type of the text output format.
+type ResponseTextFormatType string
+
+const (
+	// ResponseTextFormatText is the plain text format.
+	ResponseTextFormatText ResponseTextFormatType = "text"
+	// ResponseTextFormatJSONObject is the JSON mode format.
+	ResponseTextFormatJSONObject ResponseTextFormatType = "json_object"
+	// ResponseTextFormatJSONSchema is the structured outputs format.
+	ResponseTextFormatJSONSchema ResponseTextFormatType = "json_schema"
+)
+
+// ResponseTextFormat represents the format of the text output.
+type ResponseTextFormat struct {
+	// Type is the type of the format.
+	Type ResponseTextFormatType `json:"type"`
+	// Name is the name of the response format.
+	Name string `json:"name,omitempty"`
+	// Description is the description of the response format.
+	Description string `json:"description,omitempty"`
+	// Schema is the JSON Schema of the response format.
+	Schema json.RawMessage `json:"schema,omitempty"`
+	// Strict is a flag to enable strict schema adherence.
+	Strict *bool `json:"strict,omitempty"`
+}
+
+// ResponseUsage represents the token usage of a response.
+type ResponseUsage struct {
+	// InputTokens is the number of input tokens.
+	InputTokens int `json:"input_tokens"`
+	// InputTokensDetails is the breakdown of the input tokens.
+	InputTokensDetails ResponseInputTokensDetails `json:"input_tokens_details"`
+	// OutputTokens is the number of output tokens.
+	OutputTokens int `json:"output_tokens"`
+	// OutputTokensDetails is the breakdown of the output tokens.
+	OutputTokensDetails ResponseOutputTokensDetails `json:"output_tokens_details"`
+	// TotalTokens is the total number of tokens used.
+	TotalTokens int `json:"total_tokens"`
+}
+
+// ResponseInputTokensDetails represents the breakdown of the input tokens.
+type ResponseInputTokensDetails struct {
+	// CachedTokens is the number of tokens retrieved from the cache.
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// ResponseOutputTokensDetails represents the breakdown of the output tokens.
+type ResponseOutputTokensDetails struct {
+	// ReasoningTokens is the number of reasoning tokens.
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// OutputText returns the concatenated text of all message outputs.
+func (r *Response) OutputText() string {
+	var sb strings.Builder
+
+	for _, output := range r.Output {
+		if msg, ok := output.GetMessage(); ok {
+			sb.WriteString(msg.Text())
+		}
+	}
+
+	return sb.String()
+}
+
+// Refusal returns the refusal explanation if the model refused to respond.
+func (r *Response) Refusal() (string, bool) {
+	for _, output := range r.Output {
+		msg, ok := output.GetMessage()
+		if !ok {
+			continue
+		}
+
+		for _, content := range msg.ResponseOutputMessageContent {
+			if refusal, ok := content.GetRefusal(); ok {
+				return refusal.Refusal, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// FunctionCalls returns the function calls of the response.
+func (r *Response) FunctionCalls() []ResponseOutputFunctionCall {
+	var calls []ResponseOutputFunctionCall
+
+	for _, output := range r.Output {
+		if fnCall, ok := output.GetFunctionCall(); ok {
+			calls = append(calls, fnCall)
+		}
+	}
+
+	return calls
 }
 
 // SearchResult represents a search result structure for chat completion API.
@@ -314,4 +941,8 @@ type SearchResult struct {
 	Snippet string `json:"snippet,omitempty"`
 	// Source is the source of the search result
 	Source string `json:"source,omitempty"`
+	// Date is the publication date of the search result
+	Date string `json:"date,omitempty"`
+	// LastUpdated is the date the search result was last updated
+	LastUpdated string `json:"last_updated,omitempty"`
 }
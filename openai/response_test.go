@@ -0,0 +1,101 @@
+package openai_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseOutput(t *testing.T) {
+	data := `{
+		"id": "resp_123",
+		"object": "response",
+		"status": "completed",
+		"output": [
+			{"type": "reasoning", "id": "rs_1", "summary": [{"type": "summary_text", "text": "Thinking..."}]},
+			{"type": "web_search_call", "id": "ws_1", "status": "completed", "action": {"type": "search", "query": "aquarius"}},
+			{"type": "file_search_call", "id": "fs_1", "status": "completed", "queries": ["horoscope"], "results": [{"file_id": "file-1", "filename": "stars.pdf", "score": 0.9}]},
+			{"type": "function_call", "id": "fc_1", "call_id": "call_1", "name": "get_horoscope", "arguments": "{\"sign\":\"aquarius\"}"},
+			{"type": "message", "id": "msg_1", "role": "assistant", "status": "completed", "content": [
+				{"type": "output_text", "text": "Hello ", "annotations": [{"type": "url_citation", "url": "https://example.com", "title": "Example", "start_index": 0, "end_index": 5}]},
+				{"type": "refusal", "refusal": "I can't"},
+				{"type": "output_text", "text": "world"}
+			]},
+			{"type": "image_generation_call", "id": "ig_1"}
+		]
+	}`
+
+	var res openai.Response
+	require.NoError(t, json.Unmarshal([]byte(data), &res))
+	require.Len(t, res.Output, 6)
+
+	reasoning, ok := res.Output[0].GetReasoning()
+	require.True(t, ok)
+	require.Equal(t, "Thinking...", reasoning.Summary[0].Text)
+
+	require.IsType(t, openai.ResponseOutputWebSearchCall{}, res.Output[1].Output)
+	require.IsType(t, openai.ResponseOutputFileSearchCall{}, res.Output[2].Output)
+
+	calls := res.FunctionCalls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "get_horoscope", calls[0].Name)
+
+	msg, ok := res.Output[4].GetMessage()
+	require.True(t, ok)
+
+	text, ok := msg.ResponseOutputMessageContent[0].GetText()
+	require.True(t, ok)
+	require.Equal(t, "https://example.com", text.Annotations[0].URL)
+
+	refusal, ok := msg.ResponseOutputMessageContent[1].GetRefusal()
+	require.True(t, ok)
+	require.Equal(t, "I can't", refusal.Refusal)
+
+	unknown, ok := res.Output[5].Output.(openai.ResponseOutputUnknown)
+	require.True(t, ok)
+	require.Equal(t, "image_generation_call", unknown.Type)
+	require.JSONEq(t, `{"type": "image_generation_call", "id": "ig_1"}`, string(unknown.Raw))
+
+	require.Equal(t, "Hello world", res.OutputText())
+
+	b, err := json.Marshal(res.Output[3])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"function_call","id":"fc_1","call_id":"call_1","name":"get_horoscope","arguments":"{\"sign\":\"aquarius\"}"}`, string(b))
+}
+
+func TestResponseUsage(t *testing.T) {
+	data := `{
+		"id": "resp_123",
+		"model": "gpt-5",
+		"status": "incomplete",
+		"incomplete_details": {"reason": "max_output_tokens"},
+		"previous_response_id": "resp_122",
+		"metadata": {"tenant": "acme"},
+		"reasoning": {"effort": "low"},
+		"max_output_tokens": 16,
+		"usage": {
+			"input_tokens": 100,
+			"input_tokens_details": {"cached_tokens": 40},
+			"output_tokens": 16,
+			"output_tokens_details": {"reasoning_tokens": 10},
+			"total_tokens": 116
+		}
+	}`
+
+	var res openai.Response
+	require.NoError(t, json.Unmarshal([]byte(data), &res))
+	require.True(t, res.Truncated())
+	require.NoError(t, res.Err())
+	require.Equal(t, "gpt-5", res.Model)
+	require.Equal(t, "acme", res.Metadata["tenant"])
+	require.Equal(t, openai.ReasoningEffortLow, res.Reasoning.Effort)
+	require.Equal(t, 40, res.Usage.InputTokensDetails.CachedTokens)
+	require.Equal(t, 10, res.Usage.OutputTokensDetails.ReasoningTokens)
+	require.Equal(t, 116, res.Usage.TotalTokens)
+
+	res = openai.Response{}
+	require.NoError(t, json.Unmarshal([]byte(`{"status":"failed","error":{"code":"server_error","message":"boom"}}`), &res))
+	require.EqualError(t, res.Err(), "boom")
+}
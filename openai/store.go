@@ -0,0 +1,143 @@
+package openai
+
+import (
+	"context"
+	"iter"
+	"net/url"
+
+	"github.com/katallaxie/prompts"
+)
+
+// Store is the interface of providers that store responses (e.g. OpenAI and Azure OpenAI).
+//
+//	store := openai.NewStore(client)
+//	items, err := store.ListInputItems(ctx, res.ID, &openai.ListInputItemsOpts{Limit: 100})
+type Store interface {
+	// GetResponse returns the stored response with the given ID.
+	GetResponse(ctx context.Context, id string) (*Response, error)
+	// DeleteResponse deletes the stored response with the given ID.
+	DeleteResponse(ctx context.Context, id string) error
+	// CancelResponse cancels the background response with the given ID.
+	CancelResponse(ctx context.Context, id string) (*Response, error)
+	// ListInputItems returns the input items of the stored response with the given ID.
+	ListInputItems(ctx context.Context, id string, opts *ListInputItemsOpts) (*InputItemList, error)
+	// StreamResponse streams the events of the background response with the
+	// given ID after the given sequence number.
+	StreamResponse(ctx context.Context, id string, startingAfter int) iter.Seq2[*ResponseStreamEvent, error]
+}
+
+var _ Store = (*OpenAI[*ResponseRequest, *Response])(nil)
+
+// NewStore creates a new Store for the OpenAI API with the given client.
+func NewStore(client *prompts.Client, opts ...Opt) Store {
+	return newOpenAI(client, opts...)
+}
+
+// NewAzureStore creates a new Store for the Azure OpenAI resource at the given endpoint.
+func NewAzureStore(client *prompts.Client, endpoint string, opts ...AzureOpt) Store {
+	return newAzure(client, endpoint, opts...)
+}
+
+// ListOrder is the order of listed items.
+type ListOrder string
+
+const (
+	// ListOrderAsc lists the oldest items first.
+	ListOrderAsc ListOrder = "asc"
+	// ListOrderDesc lists the newest items first.
+	ListOrderDesc ListOrder = "desc"
+)
+
+// ListInputItemsOpts are the options of listing the input items of a response.
+type ListInputItemsOpts struct {
+	// After is the ID of the item to list the items after.
+	After string `url:"after,omitempty"`
+	// Limit is the maximum number of items to list (between 1 and 100).
+	Limit int `url:"limit,omitempty"`
+	// Order is the order of the items.
+	Order ListOrder `url:"order,omitempty"`
+	// Include are the additional fields to include (e.g. "message.input_image.image_url").
+	Include []string `url:"include,omitempty"`
+}
+
+// InputItemList is a page of the input items of a response.
+type InputItemList struct {
+	// Object is the type of object returned.
+	Object string `json:"object,omitempty"`
+	// Data are the input items.
+	Data []ResponseInput `json:"data"`
+	// FirstID is the ID of the first item of the page.
+	FirstID string `json:"first_id,omitempty"`
+	// LastID is the ID of the last item of the page.
+	LastID string `json:"last_id,omitempty"`
+	// HasMore indicates that there are more items after the page.
+	HasMore bool `json:"has_more,omitempty"`
+}
+
+// GetResponse returns the stored response with the given ID.
+func (p *OpenAI[I, O]) GetResponse(ctx context.Context, id string) (*Response, error) {
+	res := &Response{}
+
+	_, err := p.client.New().Get(responsePath(id)).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteResponse deletes the stored response with the given ID.
+func (p *OpenAI[I, O]) DeleteResponse(ctx context.Context, id string) error {
+	_, err := p.client.New().Delete(responsePath(id)).ReceiveSuccess(ctx, nil)
+
+	return err
+}
+
+// CancelResponse cancels the background response with the given ID and returns the cancelled response.
+func (p *OpenAI[I, O]) CancelResponse(ctx context.Context, id string) (*Response, error) {
+	res := &Response{}
+
+	_, err := p.client.New().Post(responsePath(id)+"/cancel").ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ListInputItems returns a page of the input items of the stored response with the given ID.
+func (p *OpenAI[I, O]) ListInputItems(ctx context.Context, id string, opts *ListInputItemsOpts) (*InputItemList, error) {
+	res := &InputItemList{}
+
+	c := p.client.New().Get(responsePath(id) + "/input_items")
+	if opts != nil {
+		c.QueryStruct(opts)
+	}
+
+	_, err := c.ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// streamQuery is the query of resumed response streams.
+type streamQuery struct {
+	Stream        bool `url:"stream"`
+	StartingAfter int  `url:"starting_after,omitempty"`
+}
+
+// StreamResponse streams the events of the background response with the
+// given ID after the given sequence number, e.g. to resume a stream after a
+// dropped connection.
+func (p *OpenAI[I, O]) StreamResponse(ctx context.Context, id string, startingAfter int) iter.Seq2[*ResponseStreamEvent, error] {
+	c := p.client.New().Get(responsePath(id)).QueryStruct(&streamQuery{Stream: true, StartingAfter: startingAfter})
+
+	return DecodeResponseStream(prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder()))
+}
+
+// responsePath returns the path of the stored response with the given ID.
+func responsePath(id string) string {
+	return "responses/" + url.PathEscape(id)
+}
@@ -0,0 +1,89 @@
+package openai_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		query  string
+		body   string
+		call   func(ctx context.Context, s openai.Store) error
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			path:   "/responses/resp_1",
+			body:   `{"id":"resp_1","status":"completed"}`,
+			call: func(ctx context.Context, s openai.Store) error {
+				res, err := s.GetResponse(ctx, "resp_1")
+				require.Equal(t, openai.ResponseStatusCompleted, res.Status)
+				return err
+			},
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/responses/resp_1",
+			body:   `{"id":"resp_1","object":"response","deleted":true}`,
+			call: func(ctx context.Context, s openai.Store) error {
+				return s.DeleteResponse(ctx, "resp_1")
+			},
+		},
+		{
+			name:   "cancel",
+			method: http.MethodPost,
+			path:   "/responses/resp_1/cancel",
+			body:   `{"id":"resp_1","status":"cancelled"}`,
+			call: func(ctx context.Context, s openai.Store) error {
+				res, err := s.CancelResponse(ctx, "resp_1")
+				require.Equal(t, openai.ResponseStatusCancelled, res.Status)
+				return err
+			},
+		},
+		{
+			name:   "list input items",
+			method: http.MethodGet,
+			path:   "/responses/resp_1/input_items",
+			query:  "limit=10&order=asc",
+			body:   `{"object":"list","data":[{"type":"function_call_output","call_id":"call_1","output":"sunny"}],"first_id":"fco_1","last_id":"fco_1","has_more":false}`,
+			call: func(ctx context.Context, s openai.Store) error {
+				res, err := s.ListInputItems(ctx, "resp_1", &openai.ListInputItemsOpts{Limit: 10, Order: openai.ListOrderAsc})
+				require.Len(t, res.Data, 1)
+
+				output, ok := res.Data[0].GetFunctionCallOutput()
+				require.True(t, ok)
+				require.Equal(t, "sunny", output.Output)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, tt.method, r.Method)
+				require.Equal(t, tt.path, r.URL.Path)
+				require.Equal(t, tt.query, r.URL.RawQuery)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			s := openai.NewStore(prompts.NewClient(), openai.WithBaseURL(srv.URL))
+			require.NoError(t, tt.call(context.Background(), s))
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"github.com/katallaxie/prompts"
+)
+
+// ResponseStreamEventType is the type of an event in a response stream.
+type ResponseStreamEventType string
+
+var _ fmt.Stringer = (*ResponseStreamEventType)(nil)
+
+// String returns the string representation of the event type.
+func (t ResponseStreamEventType) String() string {
+	return string(t)
+}
+
+// Available response stream event types.
+const (
+	// ResponseStreamEventTypeCreated is emitted when a response is created.
+	ResponseStreamEventTypeCreated ResponseStreamEventType = "response.created"
+	// ResponseStreamEventTypeQueued is emitted when a response is queued.
+	ResponseStreamEventTypeQueued ResponseStreamEventType = "response.queued"
+	// ResponseStreamEventTypeInProgress is emitted when a response is in progress.
+	ResponseStreamEventTypeInProgress ResponseStreamEventType = "response.in_progress"
+	// ResponseStreamEventTypeCompleted is emitted when a response is completed.
+	ResponseStreamEventTypeCompleted ResponseStreamEventType = "response.completed"
+	// ResponseStreamEventTypeFailed is emitted when a response failed.
+	ResponseStreamEventTypeFailed ResponseStreamEventType = "response.failed"
+	// ResponseStreamEventTypeIncomplete is emitted when a response finished as incomplete.
+	ResponseStreamEventTypeIncomplete ResponseStreamEventType = "response.incomplete"
+	// ResponseStreamEventTypeOutputItemAdded is emitted when a new output item is added.
+	ResponseStreamEventTypeOutputItemAdded ResponseStreamEventType = "response.output_item.added"
+	// ResponseStreamEventTypeOutputItemDone is emitted when an output item is done.
+	ResponseStreamEventTypeOutputItemDone ResponseStreamEventType = "response.output_item.done"
+	// ResponseStreamEventTypeContentPartAdded is emitted when a new content part is added.
+	ResponseStreamEventTypeContentPartAdded ResponseStreamEventType = "response.content_part.added"
+	// ResponseStreamEventTypeContentPartDone is emitted when a content part is done.
+	ResponseStreamEventTypeContentPartDone ResponseStreamEventType = "response.content_part.done"
+	// ResponseStreamEventTypeOutputTextDelta is emitted when there is an additional text delta.
+	ResponseStreamEventTypeOutputTextDelta ResponseStreamEventType = "response.output_text.delta"
+	// ResponseStreamEventTypeOutputTextDone is emitted when the text content is finalized.
+	ResponseStreamEventTypeOutputTextDone ResponseStreamEventType = "response.output_text.done"
+	// ResponseStreamEventTypeReasoningTextDelta is emitted when there is an additional reasoning text delta.
+	ResponseStreamEventTypeReasoningTextDelta ResponseStreamEventType = "response.reasoning_text.delta"
+	// ResponseStreamEventTypeRefusalDelta is emitted when there is a partial refusal text.
+	ResponseStreamEventTypeRefusalDelta ResponseStreamEventType = "response.refusal.delta"
+	// ResponseStreamEventTypeRefusalDone is emitted when the refusal text is finalized.
+	ResponseStreamEventTypeRefusalDone ResponseStreamEventType = "response.refusal.done"
+	// ResponseStreamEventTypeFunctionCallArgumentsDelta is emitted when there is a partial function call arguments delta.
+	ResponseStreamEventTypeFunctionCallArgumentsDelta ResponseStreamEventType = "response.function_call_arguments.delta"
+	// ResponseStreamEventTypeFunctionCallArgumentsDone is emitted when the function call arguments are finalized.
+	ResponseStreamEventTypeFunctionCallArgumentsDone ResponseStreamEventType = "response.function_call_arguments.done"
+	// ResponseStreamEventTypeError is emitted when an error occurs.
+	ResponseStreamEventTypeError ResponseStreamEventType = "error"
+)
+
+// ResponseStreamEvent is an event in a response stream.
+type ResponseStreamEvent struct {
+	// Type is the type of the event.
+	Type ResponseStreamEventType `json:"type"`
+	// SequenceNumber is the sequence number of the event.
+	SequenceNumber int `json:"sequence_number,omitempty"`
+	// Event is the payload of the event.
+	Event isResponseStreamEvent `json:"-"`
+}
+
+type isResponseStreamEvent interface {
+	isResponseStreamEvent()
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for ResponseStreamEvent.
+func (e *ResponseStreamEvent) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Type           ResponseStreamEventType `json:"type"`
+		SequenceNumber int                     `json:"sequence_number,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	e.Type = aux.Type
+	e.SequenceNumber = aux.SequenceNumber
+	e.Event = nil
+
+	var event isResponseStreamEvent
+	var err error
+
+	switch aux.Type {
+	case ResponseStreamEventTypeCreated, ResponseStreamEventTypeQueued, ResponseStreamEventTypeInProgress,
+		ResponseStreamEventTypeCompleted, ResponseStreamEventTypeFailed, ResponseStreamEventTypeIncomplete:
+		event, err = unmarshalStreamEvent[ResponseStreamEventResponse](data)
+	case ResponseStreamEventTypeOutputItemAdded, ResponseStreamEventTypeOutputItemDone:
+		event, err = unmarshalStreamEvent[ResponseStreamEventOutputItem](data)
+	case ResponseStreamEventTypeContentPartAdded, ResponseStreamEventTypeContentPartDone:
+		event, err = unmarshalStreamEvent[ResponseStreamEventContentPart](data)
+	case ResponseStreamEventTypeOutputTextDelta, ResponseStreamEventTypeReasoningTextDelta, ResponseStreamEventTypeRefusalDelta:
+		event, err = unmarshalStreamEvent[ResponseStreamEventTextDelta](data)
+	case ResponseStreamEventTypeOutputTextDone:
+		event, err = unmarshalStreamEvent[ResponseStreamEventTextDone](data)
+	case ResponseStreamEventTypeRefusalDone:
+		var aux struct {
+			ResponseStreamEventTextDone
+			Refusal string `json:"refusal,omitempty"`
+		}
+		err = json.Unmarshal(data, &aux)
+		aux.Text = aux.Refusal
+		event = aux.ResponseStreamEventTextDone
+	case ResponseStreamEventTypeFunctionCallArgumentsDelta:
+		event, err = unmarshalStreamEvent[ResponseStreamEventFunctionCallArgumentsDelta](data)
+	case ResponseStreamEventTypeFunctionCallArgumentsDone:
+		event, err = unmarshalStreamEvent[ResponseStreamEventFunctionCallArgumentsDone](data)
+	case ResponseStreamEventTypeError:
+		event, err = unmarshalStreamEvent[ResponseStreamEventError](data)
+	default:
+		event = ResponseStreamEventUnknown{Raw: bytes.Clone(data)}
+	}
+
+	if err != nil {
+		return err
+	}
+	e.Event = event
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for ResponseStreamEvent.
+// The fields of the payload are inlined next to the type and sequence number.
+func (e ResponseStreamEvent) MarshalJSON() ([]byte, error) {
+	obj := map[string]json.RawMessage{}
+
+	var payload any = e.Event
+	if unknown, ok := e.Event.(ResponseStreamEventUnknown); ok {
+		payload = unknown.Raw
+	}
+
+	if e.Event != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(b, &obj); err != nil {
+			return nil, err
+		}
+	}
+
+	obj["type"], _ = json.Marshal(e.Type)
+
+	if e.SequenceNumber != 0 {
+		obj["sequence_number"], _ = json.Marshal(e.SequenceNumber)
+	}
+
+	return json.Marshal(obj)
+}
+
+func unmarshalStreamEvent[E isResponseStreamEvent](data []byte) (isResponseStreamEvent, error) {
+	var event E
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetTextDelta returns the text delta of the event.
+func (e ResponseStreamEvent) GetTextDelta() (ResponseStreamEventTextDelta, bool) {
+	if delta, ok := e.Event.(ResponseStreamEventTextDelta); ok {
+		return delta, true
+	}
+
+	return ResponseStreamEventTextDelta{}, false
+}
+
+// GetResponse returns the response of a lifecycle event.
+func (e ResponseStreamEvent) GetResponse() (ResponseStreamEventResponse, bool) {
+	if res, ok := e.Event.(ResponseStreamEventResponse); ok {
+		return res, true
+	}
+
+	return ResponseStreamEventResponse{}, false
+}
+
+// GetFunctionCallArgumentsDelta returns the function call arguments delta of the event.
+func (e ResponseStreamEvent) GetFunctionCallArgumentsDelta() (ResponseStreamEventFunctionCallArgumentsDelta, bool) {
+	if delta, ok := e.Event.(ResponseStreamEventFunctionCallArgumentsDelta); ok {
+		return delta, true
+	}
+
+	return ResponseStreamEventFunctionCallArgumentsDelta{}, false
+}
+
+// GetError returns the error of the event.
+func (e ResponseStreamEvent) GetError() (ResponseStreamEventError, bool) {
+	if err, ok := e.Event.(ResponseStreamEventError); ok {
+		return err, true
+	}
+
+	return ResponseStreamEventError{}, false
+}
+
+// ResponseStreamEventResponse is a lifecycle event carrying the response object.
+type ResponseStreamEventResponse struct {
+	// Response is the response at the time of the event.
+	Response Response `json:"response"`
+}
+
+func (ResponseStreamEventResponse) isResponseStreamEvent() {}
+
+// ResponseStreamEventOutputItem is emitted when an output item is added or done.
+type ResponseStreamEventOutputItem struct {
+	// OutputIndex is the index of the output item.
+	OutputIndex int `json:"output_index"`
+	// Item is the output item.
+	Item ResponseOutput `json:"item"`
+}
+
+func (ResponseStreamEventOutputItem) isResponseStreamEvent() {}
+
+// ResponseStreamEventContentPart is emitted when a content part is added or done.
+type ResponseStreamEventContentPart struct {
+	// ItemID is the ID of the output item the content part belongs to.
+	ItemID string `json:"item_id"`
+	// OutputIndex is the index of the output item.
+	OutputIndex int `json:"output_index"`
+	// ContentIndex is the index of the content part.
+	ContentIndex int `json:"content_index"`
+	// Part is the content part.
+	Part ResponseOutputMessageContent `json:"part"`
+}
+
+func (ResponseStreamEventContentPart) isResponseStreamEvent() {}
+
+// ResponseStreamEventTextDelta is emitted when there is an additional text, reasoning or refusal delta.
+type ResponseStreamEventTextDelta struct {
+	// ItemID is the ID of the output item the delta belongs to.
+	ItemID string `json:"item_id"`
+	// OutputIndex is the index of the output item.
+	OutputIndex int `json:"output_index"`
+	// ContentIndex is the index of the content part.
+	ContentIndex int `json:"content_index"`
+	// Delta is the text delta.
+	Delta string `json:"delta"`
+}
+
+func (ResponseStreamEventTextDelta) isResponseStreamEvent() {}
+
+// ResponseStreamEventTextDone is emitted when the text or refusal content is finalized.
+type ResponseStreamEventTextDone struct {
+	// ItemID is the ID of the output item the text belongs to.
+	ItemID string `json:"item_id"`
+	// OutputIndex is the index of the output item.
+	OutputIndex int `json:"output_index"`
+	// ContentIndex is the index of the content part.
+	ContentIndex int `json:"content_index"`
+	// Text is the finalized text.
+	Text string `json:"text"`
+}
+
+func (ResponseStreamEventTextDone) isResponseStreamEvent() {}
+
+// ResponseStreamEventFunctionCallArgumentsDelta is emitted when there is a partial function call arguments delta.
+type ResponseStreamEventFunctionCallArgumentsDelta struct {
+	// ItemID is the ID of the function call item.
+	ItemID string `json:"item_id"`
+	// OutputIndex is the index of the output item.
+	OutputIndex int `json:"output_index"`
+	// Delta is the function call arguments delta.
+	Delta string `json:"delta"`
+}
+
+func (ResponseStreamEventFunctionCallArgumentsDelta) isResponseStreamEvent() {}
+
+// ResponseStreamEventFunctionCallArgumentsDone is emitted when the function call arguments are finalized.
+type ResponseStreamEventFunctionCallArgumentsDone struct {
+	// ItemID is the ID of the function call item.
+	ItemID string `json:"item_id"`
+	// OutputIndex is the index of the output item.
+	OutputIndex int `json:"output_index"`
+	// Arguments is the finalized function call arguments.
+	Arguments string `json:"arguments"`
+}
+
+func (ResponseStreamEventFunctionCallArgumentsDone) isResponseStreamEvent() {}
+
+// ResponseStreamEventError is emitted when an error occurs.
+type ResponseStreamEventError struct {
+	// Code is the error code.
+	Code string `json:"code,omitempty"`
+	// Message is the error message.
+	Message string `json:"message"`
+	// Param is the error parameter.
+	Param string `json:"param,omitempty"`
+}
+
+func (ResponseStreamEventError) isResponseStreamEvent() {}
+
+// ResponseStreamEventUnknown is an event of a type that is not modeled.
+type ResponseStreamEventUnknown struct {
+	// Raw is the raw JSON of the event.
+	Raw json.RawMessage `json:"-"`
+}
+
+func (ResponseStreamEventUnknown) isResponseStreamEvent() {}
+
+// doneData is the data sent by some servers to signal the end of a stream.
+var doneData = []byte("[DONE]")
+
+// DecodeResponseStream transforms a sequence of server-sent events into a
+// sequence of response stream events.
+func DecodeResponseStream(events iter.Seq2[prompts.Event, error]) iter.Seq2[*ResponseStreamEvent, error] {
+	return DecodeStream[ResponseStreamEvent](events, nil)
+}
+
+// DecodeStream transforms a sequence of server-sent events into a sequence of
+// JSON values of type T, skipping empty events and the [DONE] sentinel. The
+// optional decode function is called with the data of each event after it is
+// unmarshaled, e.g. to translate fields of a provider specific format.
+//
+//	chunks := openai.DecodeStream[perplexity.ChatCompletionChunk](events, nil)
+func DecodeStream[T any](events iter.Seq2[prompts.Event, error], decode func(data []byte, v *T) error) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for e, err := range events {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if len(e.Data) == 0 || bytes.Equal(e.Data, doneData) {
+				continue
+			}
+
+			v := new(T)
+			if err := json.Unmarshal(e.Data, v); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if decode != nil {
+				if err := decode(e.Data, v); err != nil {
+					yield(nil, err)
+					return
+				}
+			}
+
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ResponseStreamBuilder builds the events of a response stream for providers
+// that translate another streaming format into response stream events. It
+// numbers the events and places the message after the reasoning item.
+// The zero value is ready to use.
+type ResponseStreamBuilder struct {
+	seq       int
+	reasoning bool
+}
+
+// Created returns the event that starts the stream of a response for the model.
+func (b *ResponseStreamBuilder) Created(model string) *ResponseStreamEvent {
+	return b.event(ResponseStreamEventTypeCreated, ResponseStreamEventResponse{
+		Response: Response{Object: "response", Model: model, Status: ResponseStatusInProgress},
+	})
+}
+
+// ReasoningDelta returns the event of a reasoning text delta.
+func (b *ResponseStreamBuilder) ReasoningDelta(delta string) *ResponseStreamEvent {
+	b.reasoning = true
+
+	return b.event(ResponseStreamEventTypeReasoningTextDelta, ResponseStreamEventTextDelta{Delta: delta})
+}
+
+// TextDelta returns the event of an output text delta.
+func (b *ResponseStreamBuilder) TextDelta(delta string) *ResponseStreamEvent {
+	return b.event(ResponseStreamEventTypeOutputTextDelta, ResponseStreamEventTextDelta{OutputIndex: b.messageIndex(), Delta: delta})
+}
+
+// Done returns the terminal event of the response, which is completed,
+// incomplete or failed depending on its status.
+func (b *ResponseStreamBuilder) Done(res *Response) *ResponseStreamEvent {
+	t := ResponseStreamEventTypeCompleted
+
+	switch res.Status {
+	case ResponseStatusIncomplete:
+		t = ResponseStreamEventTypeIncomplete
+	case ResponseStatusFailed:
+		t = ResponseStreamEventTypeFailed
+	}
+
+	return b.event(t, ResponseStreamEventResponse{Response: *res})
+}
+
+// messageIndex returns the output index of the message, which follows the reasoning item.
+func (b *ResponseStreamBuilder) messageIndex() int {
+	if b.reasoning {
+		return 1
+	}
+
+	return 0
+}
+
+func (b *ResponseStreamBuilder) event(t ResponseStreamEventType, event isResponseStreamEvent) *ResponseStreamEvent {
+	b.seq++
+
+	return &ResponseStreamEvent{Type: t, SequenceNumber: b.seq, Event: event}
+}
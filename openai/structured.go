@@ -0,0 +1,122 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/pkg/utilx"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/schema"
+)
+
+// ErrEmptyOutput is returned when a response has no output text to decode.
+var ErrEmptyOutput = errors.New("openai: response has no output text")
+
+// RefusalError is returned when the model refused to answer.
+type RefusalError struct {
+	// Refusal is the refusal explanation of the model.
+	Refusal string
+}
+
+// Error returns the error message.
+func (e *RefusalError) Error() string {
+	return "openai: model refused to respond: " + e.Refusal
+}
+
+// invalidNameChars matches characters not allowed in a response format name.
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// NewJSONSchemaFormat creates a strict structured outputs format from the
+// JSON Schema of the type T. If no name is given, it is derived from T.
+func NewJSONSchemaFormat[T any](name string) (ResponseTextFormat, error) {
+	s, err := schema.For[T](schema.WithStrict())
+	if err != nil {
+		return ResponseTextFormat{}, err
+	}
+
+	return newJSONSchemaFormat[T](name, s)
+}
+
+// newJSONSchemaFormat creates a strict structured outputs format from the schema of the type T.
+func newJSONSchemaFormat[T any](name string, s *schema.Schema) (ResponseTextFormat, error) {
+	raw, err := s.Raw()
+	if err != nil {
+		return ResponseTextFormat{}, err
+	}
+
+	if name == "" {
+		name = invalidNameChars.ReplaceAllString(reflect.TypeFor[T]().Name(), "_")
+	}
+
+	return ResponseTextFormat{
+		Type:   ResponseTextFormatJSONSchema,
+		Name:   utilx.IfElse(name == "", "response", name),
+		Schema: raw,
+		Strict: cast.Ptr(true),
+	}, nil
+}
+
+// RespondAs sends the request with a strict JSON Schema derived from the type T
+// as text format and decodes the output text into T. The output is validated
+// against the schema and any validation error is returned along with the response.
+//
+//	type Horoscope struct {
+//		Sign  string `json:"sign" jsonschema:"enum=aries,enum=taurus"`
+//		Today string `json:"today" jsonschema:"description=The horoscope for today"`
+//	}
+//
+//	h, res, err := openai.RespondAs[Horoscope](ctx, perplexity.New(client), req)
+func RespondAs[T any](ctx context.Context, r prompts.Responder[*ResponseRequest, *Response], req *ResponseRequest) (T, *Response, error) {
+	var out T
+
+	s, err := schema.For[T](schema.WithStrict())
+	if err != nil {
+		return out, nil, err
+	}
+
+	format, err := newJSONSchemaFormat[T]("", s)
+	if err != nil {
+		return out, nil, err
+	}
+
+	body := *req
+	text := ResponseText{}
+	if req.Text != nil {
+		text = *req.Text
+	}
+	text.Format = &format
+	body.Text = &text
+
+	res, err := r.Respond(ctx, &body)
+	if err != nil {
+		return out, res, err
+	}
+
+	if err := res.Err(); err != nil {
+		return out, res, err
+	}
+
+	if refusal, ok := res.Refusal(); ok {
+		return out, res, &RefusalError{Refusal: refusal}
+	}
+
+	data := []byte(res.OutputText())
+	if len(data) == 0 {
+		return out, res, ErrEmptyOutput
+	}
+
+	if err := s.ValidateJSON(data); err != nil {
+		return out, res, fmt.Errorf("openai: invalid structured output: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, res, err
+	}
+
+	return out, res, nil
+}
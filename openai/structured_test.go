@@ -0,0 +1,75 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/katallaxie/prompts/openai"
+	"github.com/stretchr/testify/require"
+)
+
+type responderFunc func(ctx context.Context, req *openai.ResponseRequest) (*openai.Response, error)
+
+func (f responderFunc) Respond(ctx context.Context, req *openai.ResponseRequest) (*openai.Response, error) {
+	return f(ctx, req)
+}
+
+type Sign struct {
+	Name    string `json:"name" jsonschema:"enum=aries,enum=leo"`
+	Element string `json:"element,omitempty"`
+}
+
+func TestRespondAs(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Sign
+		err    string
+	}{
+		{
+			name:   "valid output",
+			output: `{"name":"leo","element":"fire"}`,
+			want:   Sign{Name: "leo", Element: "fire"},
+		},
+		{
+			name:   "invalid output",
+			output: `{"name":"cat","element":null}`,
+			err:    "openai: invalid structured output: $.name: value cat is not one of [aries leo]",
+		},
+		{
+			name:   "empty output",
+			output: ``,
+			err:    openai.ErrEmptyOutput.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := responderFunc(func(_ context.Context, req *openai.ResponseRequest) (*openai.Response, error) {
+				require.Equal(t, openai.ResponseTextFormatJSONSchema, req.Text.Format.Type)
+				require.Equal(t, "Sign", req.Text.Format.Name)
+
+				res := &openai.Response{}
+				data, _ := json.Marshal(map[string]any{
+					"output": []any{
+						map[string]any{"type": "message", "role": "assistant", "content": []any{
+							map[string]any{"type": "output_text", "text": tt.output},
+						}},
+					},
+				})
+
+				return res, json.Unmarshal(data, res)
+			})
+
+			got, _, err := openai.RespondAs[Sign](context.Background(), r, openai.NewResponseRequest())
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package perplexity
+
+import (
+	"context"
+	"iter"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+type ChatCompletionMessage = openai.ChatCompletionMessage
+
+// ChatCompletionRequest is a chat completion request with the search options of Perplexity.
+type ChatCompletionRequest struct {
+	openai.ChatCompletionRequest
+	SearchOptions
+}
+
+// ChatCompletionResponse is a chat completion with the sources of the response.
+type ChatCompletionResponse struct {
+	openai.ChatCompletionResponse
+	Sources
+}
+
+// ChatCompletionChunk is a chunk of a streamed chat completion with the sources of the response.
+type ChatCompletionChunk struct {
+	openai.ChatCompletionChunk
+	Sources
+}
+
+var (
+	_ openai.ChatCompletionRequestUnwrapper  = (*ChatCompletionRequest)(nil)
+	_ openai.ChatCompletionResponseUnwrapper = (*ChatCompletionResponse)(nil)
+	_ openai.ChatCompletionChunkUnwrapper    = (*ChatCompletionChunk)(nil)
+)
+
+// Unwrap returns the embedded chat completion request.
+func (r *ChatCompletionRequest) Unwrap() *openai.ChatCompletionRequest {
+	if r == nil {
+		return nil
+	}
+
+	return &r.ChatCompletionRequest
+}
+
+// Unwrap returns the embedded chat completion.
+func (r *ChatCompletionResponse) Unwrap() *openai.ChatCompletionResponse {
+	if r == nil {
+		return nil
+	}
+
+	return &r.ChatCompletionResponse
+}
+
+// Unwrap returns the embedded chunk.
+func (c *ChatCompletionChunk) Unwrap() *openai.ChatCompletionChunk {
+	if c == nil {
+		return nil
+	}
+
+	return &c.ChatCompletionChunk
+}
+
+// DefaultChatCompletionsURL is the default endpoint for the Chat Completions API of Perplexity.
+const DefaultChatCompletionsURL = "https://api.perplexity.ai/"
+
+// Chat is a struct that implements the Prompter interface for the Chat Completions API of Perplexity.
+type Chat[I *ChatCompletionRequest, O *ChatCompletionResponse] struct {
+	client *prompts.Client
+}
+
+var _ prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] = (*Chat[*ChatCompletionRequest, *ChatCompletionResponse])(nil)
+
+// NewChatCompletions creates a new Prompter for the Chat Completions API of Perplexity.
+//
+//	req := &perplexity.ChatCompletionRequest{SearchOptions: perplexity.SearchOptions{SearchRecencyFilter: perplexity.SearchRecencyWeek}}
+//	req.Model = "sonar"
+//	req.Messages = []perplexity.ChatCompletionMessage{openai.NewChatCompletionMessage(openai.RoleUser, "What's new in Go?")}
+//
+//	res, err := perplexity.NewChatCompletions(client).Respond(ctx, req)
+//	for _, r := range res.SearchResults {
+//		fmt.Println(r.Title, r.URL)
+//	}
+func NewChatCompletions(client *prompts.Client) prompts.Prompter[*ChatCompletionRequest, *ChatCompletionResponse] {
+	return newChat(client)
+}
+
+// NewChatCompletionsStreamer creates a new Streamer for the Chat Completions API of Perplexity.
+func NewChatCompletionsStreamer(client *prompts.Client) prompts.Streamer[*ChatCompletionRequest, *ChatCompletionChunk] {
+	return newChat(client)
+}
+
+func newChat(client *prompts.Client) *Chat[*ChatCompletionRequest, *ChatCompletionResponse] {
+	return &Chat[*ChatCompletionRequest, *ChatCompletionResponse]{client: client.New().Base(DefaultChatCompletionsURL)}
+}
+
+// ProviderName returns the name of the provider.
+func (p *Chat[I, O]) ProviderName() string {
+	return providerName
+}
+
+// Respond sends a chat completion request and returns the response.
+func (p *Chat[I, O]) Respond(ctx context.Context, req I) (O, error) {
+	res := &ChatCompletionResponse{}
+
+	body := *req
+	body.Stream = false
+	body.StreamOptions = nil
+
+	_, err := p.client.New().Post("chat/completions").BodyJSON(&body).ReceiveSuccess(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Stream sends a chat completion request and returns the stream of chunks.
+func (p *Chat[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ChatCompletionChunk, error] {
+	return func(yield func(*ChatCompletionChunk, error) bool) {
+		body := *req
+		body.Stream = true
+
+		c := p.client.New().Post("chat/completions").BodyJSON(&body)
+
+		for chunk, err := range openai.DecodeStream[ChatCompletionChunk](prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder()), nil) {
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package perplexity_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/katallaxie/prompts/perplexity"
+	"github.com/stretchr/testify/require"
+)
+
+type doer func(*http.Request) (*http.Response, error)
+
+func (d doer) Do(r *http.Request) (*http.Response, error) {
+	return d(r)
+}
+
+func TestChatCompletions(t *testing.T) {
+	client := prompts.NewClient().Doer(doer(func(r *http.Request) (*http.Response, error) {
+		require.Equal(t, "https://api.perplexity.ai/chat/completions", r.URL.String())
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"model": "sonar",
+			"messages": [{"role": "user", "content": "What's new in Go?"}],
+			"search_domain_filter": ["go.dev"],
+			"search_recency_filter": "week",
+			"search_mode": "academic",
+			"return_related_questions": true,
+			"web_search_options": {"search_context_size": "high"}
+		}`, string(b))
+
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: -1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`{
+				"id": "1",
+				"choices": [{"index": 0, "message": {"role": "assistant", "content": "Go 1.26 was released [1]."}, "finish_reason": "stop"}],
+				"citations": ["https://go.dev/blog/go1.26"],
+				"search_results": [{"title": "Go 1.26 is released", "url": "https://go.dev/blog/go1.26", "date": "2026-02-10"}],
+				"related_questions": ["What changed in Go 1.26?"]
+			}`)),
+		}, nil
+	}))
+
+	req := &perplexity.ChatCompletionRequest{}
+	req.Model = "sonar"
+	req.Messages = []perplexity.ChatCompletionMessage{openai.NewChatCompletionMessage(openai.RoleUser, "What's new in Go?")}
+	req.SearchOptions = perplexity.SearchOptions{
+		SearchDomainFilter:     []string{"go.dev"},
+		SearchRecencyFilter:    perplexity.SearchRecencyWeek,
+		SearchMode:             perplexity.SearchModeAcademic,
+		ReturnRelatedQuestions: true,
+		WebSearchOptions:       &perplexity.WebSearchOptions{SearchContextSize: perplexity.SearchContextSizeHigh},
+	}
+
+	res, err := perplexity.NewChatCompletions(client).Respond(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "Go 1.26 was released [1].", res.Content())
+	require.Equal(t, []string{"https://go.dev/blog/go1.26"}, res.Citations)
+	require.Equal(t, []perplexity.SearchResult{{Title: "Go 1.26 is released", URL: "https://go.dev/blog/go1.26", Date: "2026-02-10"}}, res.SearchResults)
+	require.Equal(t, []string{"What changed in Go 1.26?"}, res.RelatedQuestions)
+}
+
+func TestRespond(t *testing.T) {
+	client := prompts.NewClient().Doer(doer(func(r *http.Request) (*http.Response, error) {
+		require.Equal(t, "https://api.perplexity.ai/v1/responses", r.URL.String())
+
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"model": "sonar",
+			"input": [{"role": "user", "content": [{"type": "input_text", "text": "What's new in Go?"}]}],
+			"search_mode": "academic",
+			"return_related_questions": true
+		}`, string(b))
+
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: -1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`{
+				"id": "resp_1",
+				"status": "completed",
+				"output": [{"type": "message", "role": "assistant", "content": [{"type": "output_text", "text": "Go 1.26 was released [1]."}]}],
+				"citations": ["https://go.dev/blog/go1.26"],
+				"search_results": [{"title": "Go 1.26 is released", "url": "https://go.dev/blog/go1.26", "date": "2026-02-10"}],
+				"related_questions": ["What changed in Go 1.26?"]
+			}`)),
+		}, nil
+	}))
+
+	req := openai.NewResponseRequest(
+		openai.WithInput(openai.NewInputMessage(openai.RoleUser, "What's new in Go?")),
+		perplexity.WithSearchOptions(perplexity.SearchOptions{SearchMode: perplexity.SearchModeAcademic, ReturnRelatedQuestions: true}),
+	)
+	req.Model = "sonar"
+
+	res, err := perplexity.New(client).Respond(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "Go 1.26 was released [1].", res.OutputText())
+	require.Equal(t, openai.ResponseStatusCompleted, res.Status)
+
+	sources, ok := openai.ExtensionFor[perplexity.Sources](res.Extensions)
+	require.True(t, ok)
+	require.Equal(t, perplexity.Sources{
+		Citations:        []string{"https://go.dev/blog/go1.26"},
+		SearchResults:    []perplexity.SearchResult{{Title: "Go 1.26 is released", URL: "https://go.dev/blog/go1.26", Date: "2026-02-10"}},
+		RelatedQuestions: []string{"What changed in Go 1.26?"},
+	}, sources)
+}
@@ -2,23 +2,27 @@ package perplexity
 
 import (
 	"context"
+	"iter"
 
 	"github.com/katallaxie/prompts"
 	"github.com/katallaxie/prompts/openai"
 )
 
 type (
-	ResponseRequest            = openai.ResponseRequest
-	Response                   = openai.Response
-	ResponseInput              = openai.ResponseInput
-	ResponseTool               = openai.ResponseTool
-	ResponseMessageContent     = openai.ResponseMessageContent
-	ResponseMessageContentText = openai.ResponseMessageContentText
-	ResponseFunctionTool       = openai.ResponseFunctionTool
-	ResponseFunctionDefinition = openai.ResponseFunctionDefinition
-	ResponseFunctionParameters = openai.ResponseFunctionParameters
-	ResponseFunctionProperties = openai.ResponseFunctionProperties
-	Role                       = openai.Role
+	ResponseRequest             = openai.ResponseRequest
+	Response                    = openai.Response
+	ResponseInput               = openai.ResponseInput
+	ResponseTool                = openai.ResponseTool
+	ResponseMessageContent      = openai.ResponseMessageContent
+	ResponseMessageContentText  = openai.ResponseMessageContentText
+	ResponseMessageContentImage = openai.ResponseMessageContentImage
+	ResponseMessageContentFile  = openai.ResponseMessageContentFile
+	ResponseFunctionTool        = openai.ResponseFunctionTool
+	ResponseFunctionDefinition  = openai.ResponseFunctionDefinition
+	ResponseFunctionParameters  = openai.ResponseFunctionParameters
+	ResponseFunctionProperties  = openai.ResponseFunctionProperties
+	ResponseStreamEvent         = openai.ResponseStreamEvent
+	Role                        = openai.Role
 )
 
 // Role constants for the Perplexity API.
@@ -36,161 +40,109 @@ type Perplexity[I *ResponseRequest, O *Response] struct {
 	client *prompts.Client
 }
 
+var _ prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] = (*Perplexity[*ResponseRequest, *Response])(nil)
+
 // New creates a new Perplexity with the given client.
 func New(client *prompts.Client) prompts.Prompter[*ResponseRequest, *Response] {
+	return newPerplexity(client)
+}
+
+// NewStreamer creates a new Perplexity with the given client that streams responses.
+func NewStreamer(client *prompts.Client) prompts.Streamer[*ResponseRequest, *ResponseStreamEvent] {
+	return newPerplexity(client)
+}
+
+func newPerplexity(client *prompts.Client) *Perplexity[*ResponseRequest, *Response] {
 	base := client.New().Base(DefaultURL)
 
 	return &Perplexity[*ResponseRequest, *Response]{client: base}
 }
 
+// ProviderName returns the name of the provider.
+func (p *Perplexity[I, O]) ProviderName() string {
+	return providerName
+}
+
 // Respond sends a chat completion request and returns the response.
+// The search options of the request are sent with the request and the
+// sources of the response are attached to it as Sources.
 func (p *Perplexity[I, O]) Respond(ctx context.Context, req I) (O, error) {
-	res := &Response{}
+	body := responseBody(req)
+	if err := body.Validate(); err != nil {
+		return nil, err
+	}
+	body.Stream = false
 
-	_, err := p.client.New().Post("responses").BodyJSON(req).ReceiveSuccess(ctx, res)
+	res := &response{}
+
+	_, err := p.client.New().Post("responses").BodyJSON(body).ReceiveSuccess(ctx, res)
 	if err != nil {
 		return nil, err
 	}
 
-	return res, nil
+	if !res.Sources.empty() {
+		res.Extensions = append(res.Extensions, res.Sources)
+	}
+
+	return &res.Response, nil
 }
 
-// const maxBufferSize = 512 * 1 * 1000
+// Stream sends a chat completion request and returns the stream of events.
+func (p *Perplexity[I, O]) Stream(ctx context.Context, req I) iter.Seq2[*ResponseStreamEvent, error] {
+	return func(yield func(*ResponseStreamEvent, error) bool) {
+		body := responseBody(req)
+		if err := body.Validate(); err != nil {
+			yield(nil, err)
+			return
+		}
+		body.Stream = true
+
+		c := p.client.New().Post("responses").BodyJSON(body)
+
+		for e, err := range openai.DecodeResponseStream(prompts.ReceiveStream(ctx, c, prompts.NewSSEDecoder())) {
+			if !yield(e, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// responseRequest is a response request with the search options of Perplexity.
+type responseRequest struct {
+	ResponseRequest
+	SearchOptions
+}
+
+// response is a response with the sources of Perplexity.
+type response struct {
+	Response
+	Sources
+}
+
+// responseBody returns a copy of the request with its search options.
+func responseBody(req *ResponseRequest) *responseRequest {
+	body := &responseRequest{ResponseRequest: *req}
+
+	if opts, ok := openai.ExtensionFor[SearchOptions](req.Extensions); ok {
+		body.SearchOptions = opts
+	}
+
+	return body
+}
+
+// NewStore creates a new Store for the stored and background responses of the Perplexity API.
+//
+//	poller := openai.NewPoller(perplexity.New(client), perplexity.NewStore(client))
+//	res, err := poller.Respond(ctx, req)
+func NewStore(client *prompts.Client) openai.Store {
+	return openai.NewStore(client, openai.WithBaseURL(DefaultURL))
+}
+
+// providerName is the name of the provider reported to middleware.
+const providerName = "perplexity"
 
 // DefaultURL is the default endpoint for the Perplexity API.
 const DefaultURL = "https://api.perplexity.ai/v1/"
 
 // DefaultModel is the default model for the Perplexity API.
 const DefaultModel = "anthropic/claude-opus-4-6"
-
-// // Event is the structure of the event received from the server.
-// type Event struct {
-// 	// Type is the type of the event.
-// 	Type string `json:"type"`
-// 	// Data is the data of the event.
-// 	Data []byte `json:"data"`
-// }
-
-// var _ prompts.Transformer[Event] = (*Transformer)(nil)
-
-// // Transformer is a struct that implements the Transformer interface for the Perplexity API.
-// type Transformer struct{}
-
-// // Transform transforms an event into a ChatCompletionResponse.
-// func (t *Transformer) Transform(iter iter.Seq[Event]) prompts.Generator {
-// 	return func(yield func(*prompts.Response, error) bool) {
-// 		for e := range iter {
-// 			var res prompts.Response
-// 			if err := json.Unmarshal(e.Data, &res); err != nil {
-// 				if !yield(nil, err) {
-// 					break
-// 				}
-// 				continue
-// 			}
-
-// 			if !yield(&res, nil) {
-// 				break
-// 			}
-// 		}
-// 	}
-// }
-
-// // NewTransformer creates a new Transformer.
-// func NewTransformer() *Transformer {
-// 	return &Transformer{}
-// }
-
-// var _ prompts.Decoder[Event] = (*Decoder)(nil)
-
-// // Decoder is a struct that implements the Decoder interface for the Perplexity API.
-// type Decoder struct{}
-
-// // Decode decodes the response body into a stream of events.
-// func (d *Decoder) Decode(body io.ReadCloser) iter.Seq[Event] {
-// 	scn := bufio.NewScanner(body)
-// 	scn.Split(bufio.ScanLines)
-// 	scn.Buffer(make([]byte, maxBufferSize), maxBufferSize)
-
-// 	return func(yield func(Event) bool) {
-// 		for scn.Scan() {
-// 			event := cast.Zero[Event]()
-
-// 			b := scn.Bytes()
-
-// 			name, value, _ := bytes.Cut(b, []byte(":"))
-// 			if len(value) > 0 && value[0] == ' ' {
-// 				value = value[1:]
-// 			}
-
-// 			switch string(name) {
-// 			case "":
-// 				continue
-// 			case "event":
-// 				event.Type = string(value)
-// 			case "data":
-// 				event.Data = value
-// 			}
-
-// 			if !yield(event) {
-// 				break
-// 			}
-// 		}
-
-// 		body.Close()
-// 	}
-// }
-
-// // NewDecoder creates a new Decoder.
-// func NewDecoder() *Decoder {
-// 	return &Decoder{}
-// }
-
-// // New creates a new Perplexity prompter with the given options.
-// func NewResponder() prompts.ResponderFactory {
-// 	return func(c *prompts.Client) prompts.Responder {
-// 		return &Perplexity{c: c}
-// 	}
-// }
-
-// // CreateResponse sends a chat completion request and returns the response.
-// func (p *Perplexity) CreateResponse(ctx context.Context, req *prompts.ResponseRequest) (*prompts.Response, error) {
-// 	res := &prompts.Response{}
-
-// 	b, err := json.Marshal(req)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	r, err := http.NewRequestWithContext(ctx, http.MethodPost, p.c.Opts.BaseURL, bytes.NewBuffer(b))
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	r.Header.Set("Content-Type", "application/json")
-// 	r.Header.Set("Authorization", "Bearer "+p.c.Opts.ApiKey)
-// 	r.Header.Set("Accept", "application/json")
-
-// 	resp, err := p.c.Opts.Client.Do(r)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	defer resp.Body.Close()
-
-// 	if resp.StatusCode != http.StatusOK {
-// 		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-// 	}
-
-// 	body, err := io.ReadAll(resp.Body)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	fmt.Println(string(body))
-
-// 	err = json.Unmarshal(body, res)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	return res, nil
-// }
@@ -0,0 +1,30 @@
+package perplexity_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/katallaxie/pkg/cast"
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/katallaxie/prompts/perplexity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsupported(t *testing.T) {
+	client := prompts.NewClient().Doer(doer(func(*http.Request) (*http.Response, error) {
+		t.Error("unexpected request")
+		return nil, http.ErrHandlerTimeout
+	}))
+
+	req := openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "Hello")))
+	req.TopK = cast.Ptr(40)
+
+	_, err := perplexity.New(client).Respond(context.Background(), req)
+	require.ErrorIs(t, err, openai.ErrUnsupported)
+
+	for _, err := range perplexity.NewStreamer(client).Stream(context.Background(), req) {
+		require.ErrorIs(t, err, openai.ErrUnsupported)
+	}
+}
@@ -0,0 +1,122 @@
+package perplexity
+
+import "github.com/katallaxie/prompts/openai"
+
+// SearchResult is a search result used to generate a response.
+type SearchResult = openai.SearchResult
+
+// SearchRecency restricts the search results to the given period.
+type SearchRecency string
+
+const (
+	// SearchRecencyHour restricts the search results to the last hour.
+	SearchRecencyHour SearchRecency = "hour"
+	// SearchRecencyDay restricts the search results to the last day.
+	SearchRecencyDay SearchRecency = "day"
+	// SearchRecencyWeek restricts the search results to the last week.
+	SearchRecencyWeek SearchRecency = "week"
+	// SearchRecencyMonth restricts the search results to the last month.
+	SearchRecencyMonth SearchRecency = "month"
+	// SearchRecencyYear restricts the search results to the last year.
+	SearchRecencyYear SearchRecency = "year"
+)
+
+// SearchMode is the kind of sources to search.
+type SearchMode string
+
+const (
+	// SearchModeWeb searches the web.
+	SearchModeWeb SearchMode = "web"
+	// SearchModeAcademic searches scholarly articles.
+	SearchModeAcademic SearchMode = "academic"
+	// SearchModeSEC searches SEC filings.
+	SearchModeSEC SearchMode = "sec"
+)
+
+// SearchContextSize is the amount of search context retrieved for a response.
+type SearchContextSize string
+
+const (
+	// SearchContextSizeLow retrieves the least search context.
+	SearchContextSizeLow SearchContextSize = "low"
+	// SearchContextSizeMedium retrieves a balanced amount of search context.
+	SearchContextSizeMedium SearchContextSize = "medium"
+	// SearchContextSizeHigh retrieves the most search context.
+	SearchContextSizeHigh SearchContextSize = "high"
+)
+
+// WebSearchOptions are the options of the web search.
+type WebSearchOptions struct {
+	// SearchContextSize is the amount of search context retrieved for a response.
+	SearchContextSize SearchContextSize `json:"search_context_size,omitempty"`
+}
+
+// SearchOptions are the search options of a Perplexity request.
+type SearchOptions struct {
+	// SearchDomainFilter are the domains to search, domains prefixed with "-" are excluded.
+	SearchDomainFilter []string `json:"search_domain_filter,omitempty"`
+	// SearchRecencyFilter restricts the search results to the given period.
+	SearchRecencyFilter SearchRecency `json:"search_recency_filter,omitempty"`
+	// SearchMode is the kind of sources to search (e.g. academic).
+	SearchMode SearchMode `json:"search_mode,omitempty"`
+	// ReturnImages is a flag to return images of the search results.
+	ReturnImages bool `json:"return_images,omitempty"`
+	// ReturnRelatedQuestions is a flag to return questions related to the request.
+	ReturnRelatedQuestions bool `json:"return_related_questions,omitempty"`
+	// WebSearchOptions are the options of the web search.
+	WebSearchOptions *WebSearchOptions `json:"web_search_options,omitempty"`
+}
+
+var _ openai.Extension = SearchOptions{}
+
+// Provider returns the name of the provider of the search options.
+func (SearchOptions) Provider() string {
+	return providerName
+}
+
+// WithSearchOptions sets the search options of a request of the Responses API.
+//
+//	req := openai.NewResponseRequest(perplexity.WithSearchOptions(perplexity.SearchOptions{SearchMode: perplexity.SearchModeAcademic}))
+//	res, err := prompt.Respond(ctx, req)
+func WithSearchOptions(opts SearchOptions) openai.RequestOpt {
+	return openai.WithExtensions(opts)
+}
+
+// Image is an image of the search results.
+type Image struct {
+	// ImageURL is the URL of the image.
+	ImageURL string `json:"image_url"`
+	// OriginURL is the URL of the page the image was found on.
+	OriginURL string `json:"origin_url,omitempty"`
+	// Height is the height of the image in pixels.
+	Height int `json:"height,omitempty"`
+	// Width is the width of the image in pixels.
+	Width int `json:"width,omitempty"`
+}
+
+// Sources are the sources of a response. The Responses API provider attaches
+// them to the response as an extension.
+//
+//	sources, ok := openai.ExtensionFor[perplexity.Sources](res.Extensions)
+type Sources struct {
+	// Citations are the URLs of the cited sources. The n-th URL is referenced as [n+1] in the content.
+	Citations []string `json:"citations,omitempty"`
+	// SearchResults are the search results used to generate the response.
+	SearchResults []SearchResult `json:"search_results,omitempty"`
+	// RelatedQuestions are questions related to the request.
+	RelatedQuestions []string `json:"related_questions,omitempty"`
+	// Images are the images of the search results.
+	Images []Image `json:"images,omitempty"`
+}
+
+var _ openai.Extension = Sources{}
+
+// Provider returns the name of the provider of the sources.
+func (Sources) Provider() string {
+	return providerName
+}
+
+// empty returns true if the response has no sources.
+func (s Sources) empty() bool {
+	return len(s.Citations) == 0 && len(s.SearchResults) == 0 && len(s.RelatedQuestions) == 0 && len(s.Images) == 0
+}
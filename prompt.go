@@ -2,22 +2,9 @@ package prompts
 
 import (
 	"context"
+	"iter"
 )
 
-// // Generator is a type that represents a generator of chat completion responses.
-// // It is an iterator that yields chat completion responses or errors.
-// type Generator iter.Seq2[*Response, error]
-
-// // Decoder is a function type that implements the Decoder interface.
-// type Decoder[E any] interface {
-// 	Decode(io.ReadCloser) iter.Seq[E]
-// }
-
-// // Transformer is a function type that implements the Transformer interface.
-// type Transformer[E any] interface {
-// 	Transform(iter.Seq[E]) Generator
-// }
-
 // Responder is the interface for sending a chat completion request and receiving a response.
 type Responder[I, O any] interface {
 	// Respond sends a chat completion request and returns the response.
@@ -28,3 +15,29 @@ type Responder[I, O any] interface {
 type Prompter[I, O any] interface {
 	Responder[I, O]
 }
+
+// Streamer is the interface for sending a chat completion request and receiving
+// a stream of events as they are generated.
+type Streamer[I, E any] interface {
+	// Stream sends a chat completion request and returns a sequence of events.
+	// Iteration stops after the first error.
+	Stream(ctx context.Context, in I) iter.Seq2[E, error]
+}
+
+// ProviderNamer is implemented by providers that know their name and by
+// middleware that passes the name of the wrapped provider through.
+type ProviderNamer interface {
+	// ProviderName returns the name of the provider (e.g. "openai" or "azure.ai.openai").
+	ProviderName() string
+}
+
+// ProviderName returns the name of the provider or an empty string if it
+// does not implement ProviderNamer. Middleware uses it to name the provider
+// of a wrapped Responder or Streamer.
+func ProviderName(provider any) string {
+	if n, ok := provider.(ProviderNamer); ok {
+		return n.ProviderName()
+	}
+
+	return ""
+}
@@ -0,0 +1,31 @@
+package prompts_test
+
+import (
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+type provider struct{}
+
+func (provider) ProviderName() string { return "acme" }
+
+func TestProviderName(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider any
+		want     string
+	}{
+		{name: "namer", provider: provider{}, want: "acme"},
+		{name: "pointer", provider: &provider{}, want: "acme"},
+		{name: "unnamed", provider: 1},
+		{name: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, prompts.ProviderName(tt.provider))
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package promptstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+// ErrNoReply is returned by the Fake if no scripted turn matches a request.
+var ErrNoReply = errors.New("promptstest: no scripted turn matches the request")
+
+// Matcher reports whether a scripted turn replies to the request.
+type Matcher func(req *openai.ResponseRequest) bool
+
+// Any matches every request.
+func Any() Matcher {
+	return func(*openai.ResponseRequest) bool {
+		return true
+	}
+}
+
+// All matches requests that are matched by all matchers.
+func All(matchers ...Matcher) Matcher {
+	return func(req *openai.ResponseRequest) bool {
+		for _, m := range matchers {
+			if !m(req) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// LastUserMessageContains matches requests whose last user message contains the text.
+func LastUserMessageContains(text string) Matcher {
+	return func(req *openai.ResponseRequest) bool {
+		msg, ok := LastUserMessage(req)
+		return ok && strings.Contains(msg, text)
+	}
+}
+
+// ToolOffered matches requests that offer the function tool with the given name.
+func ToolOffered(name string) Matcher {
+	return func(req *openai.ResponseRequest) bool {
+		for _, tool := range req.Tools {
+			if fn, ok := tool.Tool.(openai.ResponseFunctionTool); ok && fn.Function.Name == name {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// HasFunctionCallOutput matches requests that send the output of a function call.
+func HasFunctionCallOutput() Matcher {
+	return func(req *openai.ResponseRequest) bool {
+		for _, in := range req.Input {
+			if _, ok := in.GetFunctionCallOutput(); ok {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// LastUserMessage returns the text of the last user message of the request.
+func LastUserMessage(req *openai.ResponseRequest) (string, bool) {
+	for i := len(req.Input) - 1; i >= 0; i-- {
+		in := req.Input[i]
+		if in.Item != nil || in.Role != openai.RoleUser {
+			continue
+		}
+
+		var text strings.Builder
+		for _, c := range in.Content {
+			if t, ok := c.GetText(); ok {
+				text.WriteString(t.Text)
+			}
+		}
+
+		return text.String(), true
+	}
+
+	return "", false
+}
+
+// turn is a scripted turn of the Fake.
+type turn struct {
+	match  Matcher
+	reply  Reply
+	repeat bool
+	used   bool
+}
+
+// Fake is an in-memory Prompter for the Responses API. It replies with the
+// first scripted turn that matches a request and records every request.
+// Turns added with On reply once, turns added with Always reply to every
+// matching request. Turns that were added with On but never used fail the
+// test when it finishes.
+//
+//	fake := promptstest.NewFake(t).
+//		On(promptstest.ToolOffered("weather"), promptstest.ToolCalls(promptstest.ToolCall{Name: "weather", Arguments: `{"city":"Berlin"}`})).
+//		On(promptstest.HasFunctionCallOutput(), promptstest.Text("It is sunny."))
+type Fake struct {
+	t        testing.TB
+	mu       sync.Mutex
+	turns    []*turn
+	requests []*openai.ResponseRequest
+}
+
+var _ prompts.Prompter[*openai.ResponseRequest, *openai.Response] = (*Fake)(nil)
+
+// NewFake creates a new Fake without scripted turns.
+func NewFake(t testing.TB) *Fake {
+	t.Helper()
+
+	f := &Fake{t: t}
+	t.Cleanup(f.AssertExpectations)
+
+	return f
+}
+
+// On adds a turn that replies once to the first request matched by the matcher.
+func (f *Fake) On(match Matcher, reply Reply) *Fake {
+	return f.add(&turn{match: match, reply: reply})
+}
+
+// Always adds a turn that replies to every request matched by the matcher.
+func (f *Fake) Always(match Matcher, reply Reply) *Fake {
+	return f.add(&turn{match: match, reply: reply, repeat: true})
+}
+
+// Respond replies with the first scripted turn that matches the request.
+// Injected errors are returned as a *prompts.PromptError.
+func (f *Fake) Respond(ctx context.Context, req *openai.ResponseRequest) (*openai.Response, error) {
+	reply, n, ok := f.next(req)
+	if !ok {
+		f.t.Errorf("promptstest: no scripted turn matches request %d", n)
+		return nil, ErrNoReply
+	}
+
+	select {
+	case <-time.After(reply.Latency):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if reply.StatusCode != 0 {
+		err := &prompts.PromptError{StatusCode: reply.StatusCode}
+		err.JSON.Message = reply.Error
+		err.JSON.Type = http.StatusText(reply.StatusCode)
+
+		return nil, err
+	}
+
+	return response(req, reply.Response, n), nil
+}
+
+// Requests returns the requests received by the fake.
+func (f *Fake) Requests() []*openai.ResponseRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*openai.ResponseRequest{}, f.requests...)
+}
+
+// AssertExpectations fails the test if a turn added with On was never used.
+func (f *Fake) AssertExpectations() {
+	f.t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, t := range f.turns {
+		if !t.repeat && !t.used {
+			f.t.Errorf("promptstest: scripted turn %d was never used", i+1)
+		}
+	}
+}
+
+// AssertNumberOfRequests fails the test if the fake did not receive n requests.
+func (f *Fake) AssertNumberOfRequests(n int) bool {
+	f.t.Helper()
+
+	if got := len(f.Requests()); got != n {
+		f.t.Errorf("promptstest: got %d requests, want %d", got, n)
+		return false
+	}
+
+	return true
+}
+
+// AssertRequest fails the test if the i-th request (starting at 0) is not matched by the matcher.
+func (f *Fake) AssertRequest(i int, match Matcher) bool {
+	f.t.Helper()
+
+	reqs := f.Requests()
+	if i < 0 || i >= len(reqs) {
+		f.t.Errorf("promptstest: got %d requests, want request %d", len(reqs), i)
+		return false
+	}
+
+	if !match(reqs[i]) {
+		f.t.Errorf("promptstest: request %d does not match: %s", i, describe(reqs[i]))
+		return false
+	}
+
+	return true
+}
+
+func (f *Fake) add(t *turn) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.turns = append(f.turns, t)
+
+	return f
+}
+
+// next records the request and returns the reply of the first matching turn and the number of the request.
+func (f *Fake) next(req *openai.ResponseRequest) (Reply, int, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r := *req
+	f.requests = append(f.requests, &r)
+	n := len(f.requests)
+
+	for _, t := range f.turns {
+		if t.used || !t.match(&r) {
+			continue
+		}
+
+		if !t.repeat {
+			t.used = true
+		}
+
+		return t.reply, n, true
+	}
+
+	return Reply{}, n, false
+}
+
+// describe returns a short description of the request for failure messages.
+func describe(req *openai.ResponseRequest) string {
+	msg, _ := LastUserMessage(req)
+
+	tools := make([]string, 0, len(req.Tools))
+	for _, tool := range req.Tools {
+		if fn, ok := tool.Tool.(openai.ResponseFunctionTool); ok {
+			tools = append(tools, fn.Function.Name)
+		}
+	}
+
+	return fmt.Sprintf("last user message %q, tools %v", msg, tools)
+}
@@ -0,0 +1,100 @@
+package promptstest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/katallaxie/prompts/promptstest"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherArgs struct {
+	City string `json:"city"`
+}
+
+func TestFake(t *testing.T) {
+	tool, err := openai.NewFunctionTool[weatherArgs]("weather", "Get the weather.", false)
+	require.NoError(t, err)
+
+	fake := promptstest.NewFake(t).
+		On(promptstest.HasFunctionCallOutput(), promptstest.Text("It is sunny.")).
+		On(promptstest.All(promptstest.ToolOffered("weather"), promptstest.LastUserMessageContains("Berlin")),
+			promptstest.ToolCalls(promptstest.ToolCall{Name: "weather", Arguments: `{"city":"Berlin"}`})).
+		Always(promptstest.Any(), promptstest.Text("I do not know."))
+
+	res, err := fake.Respond(context.Background(), openai.NewResponseRequest(
+		openai.WithTools(tool),
+		openai.WithInput(openai.NewInputMessage(openai.RoleUser, "What is the weather in Berlin?")),
+	))
+	require.NoError(t, err)
+
+	calls := res.FunctionCalls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "weather", calls[0].Name)
+
+	res, err = fake.Respond(context.Background(), openai.NewResponseRequest(
+		openai.WithTools(tool),
+		openai.WithInput(openai.NewFunctionCallOutput(calls[0].CallID, `{"weather":"sunny"}`)),
+	))
+	require.NoError(t, err)
+	require.Equal(t, "It is sunny.", res.OutputText())
+
+	for range 2 {
+		res, err = fake.Respond(context.Background(), openai.NewResponseRequest(
+			openai.WithInput(openai.NewInputMessage(openai.RoleUser, "What is the weather in Paris?")),
+		))
+		require.NoError(t, err)
+		require.Equal(t, "I do not know.", res.OutputText())
+	}
+
+	fake.AssertNumberOfRequests(4)
+	fake.AssertRequest(0, promptstest.ToolOffered("weather"))
+	fake.AssertRequest(3, promptstest.LastUserMessageContains("Paris"))
+}
+
+func TestFake_Error(t *testing.T) {
+	fake := promptstest.NewFake(t).On(promptstest.Any(), promptstest.Error(http.StatusTooManyRequests, "rate limit exceeded"))
+
+	_, err := fake.Respond(context.Background(), openai.NewResponseRequest())
+
+	var perr *prompts.PromptError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, http.StatusTooManyRequests, perr.StatusCode)
+	require.Equal(t, "rate limit exceeded", perr.Error())
+}
+
+func TestLastUserMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []openai.ResponseInput
+		want  string
+		ok    bool
+	}{
+		{
+			name: "no input",
+		},
+		{
+			name: "last user message",
+			input: []openai.ResponseInput{
+				openai.NewInputMessage(openai.RoleUser, "first"),
+				openai.NewInputMessage(openai.RoleAssistant, "answer"),
+				openai.NewInputMessage(openai.RoleUser, "second"),
+				openai.NewFunctionCallOutput("call_1", "{}"),
+			},
+			want: "second",
+			ok:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := promptstest.LastUserMessage(&openai.ResponseRequest{Input: tt.input})
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
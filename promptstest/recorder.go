@@ -0,0 +1,298 @@
+package promptstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay replays the exchanges of the cassette and fails on requests that were not recorded.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests and records the exchanges to the cassette.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records it otherwise.
+	ModeAuto
+)
+
+// Redacted replaces the values of scrubbed headers.
+const Redacted = "REDACTED"
+
+// ErrNoInteraction is returned when a request does not match the next recorded interaction.
+var ErrNoInteraction = errors.New("promptstest: no recorded interaction")
+
+// DefaultScrubbedHeaders are the headers carrying API keys and other secrets.
+var DefaultScrubbedHeaders = []string{
+	"Authorization",
+	"Api-Key",
+	"X-Api-Key",
+	"X-Goog-Api-Key",
+	"Openai-Organization",
+	"Openai-Project",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Interaction is a recorded exchange.
+type Interaction struct {
+	// Request is the recorded request.
+	Request RecordedRequest `json:"request"`
+	// Response is the recorded response.
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request.
+type RecordedRequest struct {
+	// Method is the method of the request.
+	Method string `json:"method"`
+	// URL is the URL of the request.
+	URL string `json:"url"`
+	// Header is the header of the request.
+	Header http.Header `json:"header,omitempty"`
+	// Body is the body of the request.
+	Body string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response.
+type RecordedResponse struct {
+	// StatusCode is the status code of the response.
+	StatusCode int `json:"status_code"`
+	// Header is the header of the response.
+	Header http.Header `json:"header,omitempty"`
+	// Body is the body of the response.
+	Body string `json:"body,omitempty"`
+}
+
+// Cassette is the file format of the recorded interactions.
+type Cassette struct {
+	// Interactions are the recorded interactions in order.
+	Interactions []Interaction `json:"interactions"`
+}
+
+// RecorderOpts are the options of the Recorder.
+type RecorderOpts struct {
+	// Mode is the mode of the recorder.
+	Mode Mode
+	// Doer sends the requests in record mode.
+	Doer prompts.Doer
+	// ScrubbedHeaders are the headers whose values are not recorded.
+	ScrubbedHeaders []string
+}
+
+// RecorderOpt is a function type for configuring the Recorder.
+type RecorderOpt func(*RecorderOpts)
+
+// WithMode sets the mode of the recorder.
+func WithMode(mode Mode) RecorderOpt {
+	return func(o *RecorderOpts) {
+		o.Mode = mode
+	}
+}
+
+// WithDoer sets the Doer that sends the requests in record mode.
+func WithDoer(doer prompts.Doer) RecorderOpt {
+	return func(o *RecorderOpts) {
+		o.Doer = doer
+	}
+}
+
+// WithScrubbedHeaders adds headers whose values are not recorded.
+func WithScrubbedHeaders(headers ...string) RecorderOpt {
+	return func(o *RecorderOpts) {
+		o.ScrubbedHeaders = append(o.ScrubbedHeaders, headers...)
+	}
+}
+
+// Recorder is a Doer that records exchanges to a cassette file and replays
+// them deterministically. Interactions are replayed in order and matched by
+// method and URL.
+//
+//	rec := promptstest.NewRecorder(t, "testdata/weather.json", promptstest.WithMode(promptstest.ModeAuto))
+//	prompt := perplexity.New(prompts.NewClient().Doer(rec).APIKey(os.Getenv("PPLX_API_KEY")))
+type Recorder struct {
+	path     string
+	opts     *RecorderOpts
+	mu       sync.Mutex
+	cassette Cassette
+	next     int
+}
+
+var _ prompts.Doer = (*Recorder)(nil)
+
+// NewRecorder creates a new Recorder for the cassette at the given path.
+// In record mode the cassette is saved when the test finishes.
+func NewRecorder(t testing.TB, path string, opts ...RecorderOpt) *Recorder {
+	t.Helper()
+
+	o := &RecorderOpts{Mode: ModeReplay, Doer: http.DefaultClient, ScrubbedHeaders: DefaultScrubbedHeaders}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	r := &Recorder{path: path, opts: o}
+
+	b, err := os.ReadFile(path)
+
+	switch {
+	case o.Mode == ModeAuto && errors.Is(err, fs.ErrNotExist):
+		o.Mode = ModeRecord
+	case o.Mode == ModeAuto:
+		o.Mode = ModeReplay
+	default:
+	}
+
+	if o.Mode == ModeReplay {
+		if err != nil {
+			t.Fatalf("promptstest: read cassette: %v", err)
+		}
+
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			t.Fatalf("promptstest: decode cassette %s: %v", path, err)
+		}
+	}
+
+	if o.Mode == ModeRecord {
+		t.Cleanup(func() {
+			if err := r.Save(); err != nil {
+				t.Errorf("promptstest: save cassette: %v", err)
+			}
+		})
+	}
+
+	return r
+}
+
+// Do records or replays the exchange of the request.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.opts.Mode == ModeRecord {
+		return r.record(req, body)
+	}
+
+	return r.replay(req)
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, b, 0o600)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	res, err := r.opts.Doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.scrub(req.Header),
+			Body:   string(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     r.scrub(res.Header),
+			Body:       string(b),
+		},
+	})
+	r.mu.Unlock()
+
+	res.Body = io.NopCloser(bytes.NewReader(b))
+
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.next >= len(r.cassette.Interactions) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL)
+	}
+
+	i := r.cassette.Interactions[r.next]
+	if i.Request.Method != req.Method || i.Request.URL != req.URL.String() {
+		return nil, fmt.Errorf("%w: %s %s, next is %s %s", ErrNoInteraction, req.Method, req.URL, i.Request.Method, i.Request.URL)
+	}
+	r.next++
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+		StatusCode:    i.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        i.Response.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(i.Response.Body)),
+		ContentLength: int64(len(i.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// scrub returns a copy of the header with the values of the scrubbed headers redacted.
+func (r *Recorder) scrub(h http.Header) http.Header {
+	h = h.Clone()
+
+	for _, k := range r.opts.ScrubbedHeaders {
+		if _, ok := h[http.CanonicalHeaderKey(k)]; ok {
+			h.Set(k, Redacted)
+		}
+	}
+
+	return h
+}
+
+// readBody reads the body of the request and restores it.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+
+	req.Body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
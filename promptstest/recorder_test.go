@@ -0,0 +1,45 @@
+package promptstest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/katallaxie/prompts/promptstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	t.Run("record", func(t *testing.T) {
+		srv := promptstest.NewServer(t, promptstest.Text("It is sunny."))
+
+		rec := promptstest.NewRecorder(t, path, promptstest.WithMode(promptstest.ModeAuto), promptstest.WithDoer(srv.Doer()))
+		client := prompts.NewClient().Doer(rec).APIKey("sk-secret")
+
+		res, err := openai.New(client).Respond(context.Background(), openai.NewResponseRequest())
+		require.NoError(t, err)
+		require.Equal(t, "It is sunny.", res.OutputText())
+	})
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "sk-secret")
+	require.Contains(t, string(b), promptstest.Redacted)
+
+	t.Run("replay", func(t *testing.T) {
+		rec := promptstest.NewRecorder(t, path, promptstest.WithMode(promptstest.ModeAuto))
+		client := prompts.NewClient().Doer(rec)
+
+		res, err := openai.New(client).Respond(context.Background(), openai.NewResponseRequest())
+		require.NoError(t, err)
+		require.Equal(t, "It is sunny.", res.OutputText())
+
+		_, err = openai.New(client).Respond(context.Background(), openai.NewResponseRequest())
+		require.ErrorIs(t, err, promptstest.ErrNoInteraction)
+	})
+}
@@ -0,0 +1,328 @@
+// Package promptstest provides utilities for testing code built on prompts
+// without network access: a stand-in server for the Responses API, an
+// in-memory fake Prompter with scripted turns, and a Doer that records real
+// exchanges to cassette files and replays them.
+package promptstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+// Reply is a scripted reply of the Server.
+type Reply struct {
+	// Response is the response sent to the request. If the request is
+	// streamed, it is sent as a sequence of server-sent events.
+	Response *openai.Response
+	// Events are the events sent to a streamed request instead of the events of Response.
+	Events []openai.ResponseStreamEvent
+	// StatusCode is the status code of an injected error.
+	StatusCode int
+	// Error is the message of an injected error.
+	Error string
+	// Latency is the delay before the reply is sent.
+	Latency time.Duration
+}
+
+// Text returns a reply with a message of the given text.
+func Text(text string) Reply {
+	return Reply{Response: &openai.Response{
+		Status: openai.ResponseStatusCompleted,
+		Output: []openai.ResponseOutput{{Output: openai.ResponseOutputMessage{
+			Role:   openai.RoleAssistant,
+			Status: openai.ResponseStatusCompleted,
+			ResponseOutputMessageContent: []openai.ResponseOutputMessageContent{
+				{Content: openai.ResponseOutputMessageContentText{Text: text}},
+			},
+		}}},
+	}}
+}
+
+// ToolCall is a function call of a scripted reply.
+type ToolCall struct {
+	// Name is the name of the function.
+	Name string
+	// Arguments are the JSON encoded arguments of the function.
+	Arguments string
+}
+
+// ToolCalls returns a reply with the given function calls.
+func ToolCalls(calls ...ToolCall) Reply {
+	res := &openai.Response{Status: openai.ResponseStatusCompleted}
+
+	for i, c := range calls {
+		res.Output = append(res.Output, openai.ResponseOutput{Output: openai.ResponseOutputFunctionCall{
+			ID:        fmt.Sprintf("fc_%d", i+1),
+			CallID:    fmt.Sprintf("call_%d", i+1),
+			Status:    openai.ResponseStatusCompleted,
+			Name:      c.Name,
+			Arguments: c.Arguments,
+		}})
+	}
+
+	return Reply{Response: res}
+}
+
+// Error returns a reply with an injected error of the given status code.
+func Error(statusCode int, message string) Reply {
+	return Reply{StatusCode: statusCode, Error: message}
+}
+
+// WithLatency returns a copy of the reply that is delayed by d.
+func (r Reply) WithLatency(d time.Duration) Reply {
+	r.Latency = d
+	return r
+}
+
+// Server is a stand-in server for the Responses API. It sends the scripted
+// replies in order and records the requests it receives.
+//
+//	srv := promptstest.NewServer(t,
+//		promptstest.ToolCalls(promptstest.ToolCall{Name: "weather", Arguments: `{"city":"Berlin"}`}),
+//		promptstest.Text("It is sunny."),
+//	)
+//	prompt := perplexity.New(srv.Client())
+type Server struct {
+	*httptest.Server
+
+	t        testing.TB
+	mu       sync.Mutex
+	replies  []Reply
+	handler  func(*openai.ResponseRequest) Reply
+	requests []*openai.ResponseRequest
+}
+
+// NewServer starts a new Server with the scripted replies. It is closed when the test finishes.
+func NewServer(t testing.TB, replies ...Reply) *Server {
+	t.Helper()
+
+	s := &Server{t: t, replies: replies}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Add appends scripted replies.
+func (s *Server) Add(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replies = append(s.replies, replies...)
+}
+
+// HandleFunc sets a function that replies to requests once the scripted replies are used up.
+func (s *Server) HandleFunc(fn func(req *openai.ResponseRequest) Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handler = fn
+}
+
+// Requests returns the requests received by the server.
+func (s *Server) Requests() []*openai.ResponseRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*openai.ResponseRequest{}, s.requests...)
+}
+
+// Client returns a new client that sends all requests to the server,
+// regardless of the base URL set by a provider.
+func (s *Server) Client() *prompts.Client {
+	return prompts.NewClient().Doer(s.Doer())
+}
+
+// Doer returns a Doer that sends all requests to the server, keeping their path.
+func (s *Server) Doer() prompts.Doer {
+	u, _ := url.Parse(s.URL)
+
+	return doerFunc(func(req *http.Request) (*http.Response, error) {
+		r := req.Clone(req.Context())
+		r.URL.Scheme = u.Scheme
+		r.URL.Host = u.Host
+		r.Host = u.Host
+
+		return s.Server.Client().Do(r)
+	})
+}
+
+// next returns the reply to the request and the number of the request.
+func (s *Server) next(req *openai.ResponseRequest) (Reply, int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+	n := len(s.requests)
+
+	if len(s.replies) > 0 {
+		r := s.replies[0]
+		s.replies = s.replies[1:]
+
+		return r, n, true
+	}
+
+	if s.handler != nil {
+		return s.handler(req), n, true
+	}
+
+	return Reply{}, n, false
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/responses") {
+		s.t.Errorf("promptstest: unexpected request %s %s", r.Method, r.URL.Path)
+		writeError(w, http.StatusNotFound, "not found")
+
+		return
+	}
+
+	req := &openai.ResponseRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		s.t.Errorf("promptstest: decode request: %v", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	reply, n, ok := s.next(req)
+	if !ok {
+		s.t.Errorf("promptstest: no reply left for request %d", n)
+		writeError(w, http.StatusInternalServerError, "no reply left")
+
+		return
+	}
+
+	select {
+	case <-time.After(reply.Latency):
+	case <-r.Context().Done():
+		return
+	}
+
+	switch {
+	case reply.StatusCode != 0:
+		writeError(w, reply.StatusCode, reply.Error)
+	case req.Stream:
+		writeEvents(w, events(req, reply, n))
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response(req, reply.Response, n))
+	}
+}
+
+// response returns a copy of the response with the ID and model set.
+func response(req *openai.ResponseRequest, res *openai.Response, n int) *openai.Response {
+	r := openai.Response{Status: openai.ResponseStatusCompleted}
+	if res != nil {
+		r = *res
+	}
+
+	r.Object = "response"
+	if r.ID == "" {
+		r.ID = fmt.Sprintf("resp_%d", n)
+	}
+
+	if r.Model == "" {
+		r.Model = req.Model
+	}
+
+	return &r
+}
+
+// events returns the events of a streamed reply. The response is sent as
+// created event, text deltas and the completed event.
+func events(req *openai.ResponseRequest, reply Reply, n int) []openai.ResponseStreamEvent {
+	if len(reply.Events) > 0 {
+		return reply.Events
+	}
+
+	res := response(req, reply.Response, n)
+
+	created := *res
+	created.Status = openai.ResponseStatusInProgress
+	created.Output = nil
+
+	events := []openai.ResponseStreamEvent{
+		{Type: openai.ResponseStreamEventTypeCreated, Event: openai.ResponseStreamEventResponse{Response: created}},
+	}
+
+	for i, o := range res.Output {
+		if msg, ok := o.GetMessage(); ok {
+			for _, word := range strings.SplitAfter(msg.Text(), " ") {
+				events = append(events, openai.ResponseStreamEvent{
+					Type:  openai.ResponseStreamEventTypeOutputTextDelta,
+					Event: openai.ResponseStreamEventTextDelta{ItemID: msg.ID, OutputIndex: i, Delta: word},
+				})
+			}
+		}
+
+		events = append(events, openai.ResponseStreamEvent{
+			Type:  openai.ResponseStreamEventTypeOutputItemDone,
+			Event: openai.ResponseStreamEventOutputItem{OutputIndex: i, Item: o},
+		})
+	}
+
+	t := openai.ResponseStreamEventTypeCompleted
+	switch res.Status {
+	case openai.ResponseStatusIncomplete:
+		t = openai.ResponseStreamEventTypeIncomplete
+	case openai.ResponseStatusFailed:
+		t = openai.ResponseStreamEventTypeFailed
+	default:
+	}
+
+	events = append(events, openai.ResponseStreamEvent{Type: t, Event: openai.ResponseStreamEventResponse{Response: *res}})
+
+	for i := range events {
+		events[i].SequenceNumber = i + 1
+	}
+
+	return events
+}
+
+// writeEvents writes the events as server-sent events.
+func writeEvents(w http.ResponseWriter, events []openai.ResponseStreamEvent) {
+	w.Header().Set("Content-Type", "text/event-stream")
+
+	f, _ := w.(http.Flusher)
+
+	for _, e := range events {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, b)
+
+		if f != nil {
+			f.Flush()
+		}
+	}
+}
+
+// writeError writes an error in the format of the OpenAI API.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	body := map[string]any{"error": map[string]any{"message": message, "type": http.StatusText(statusCode)}}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// doerFunc is an adapter to use a function as a Doer.
+type doerFunc func(*http.Request) (*http.Response, error)
+
+// Do sends the request.
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
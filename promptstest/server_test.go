@@ -0,0 +1,119 @@
+package promptstest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/katallaxie/prompts/promptstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ToolCalls(t *testing.T) {
+	srv := promptstest.NewServer(t,
+		promptstest.ToolCalls(promptstest.ToolCall{Name: "weather", Arguments: `{"city":"Berlin"}`}),
+		promptstest.Text("It is sunny."),
+	)
+
+	conv := openai.NewConversation(openai.New(srv.Client()), openai.WithInstructions("Be brief."))
+
+	res, err := conv.Send(context.Background(), "What is the weather in Berlin?")
+	require.NoError(t, err)
+	require.Equal(t, "resp_1", res.ID)
+
+	calls := res.FunctionCalls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "weather", calls[0].Name)
+	require.JSONEq(t, `{"city":"Berlin"}`, calls[0].Arguments)
+
+	res, err = conv.Next(context.Background(), openai.NewFunctionCallOutput(calls[0].CallID, `{"weather":"sunny"}`))
+	require.NoError(t, err)
+	require.Equal(t, "It is sunny.", res.OutputText())
+
+	reqs := srv.Requests()
+	require.Len(t, reqs, 2)
+	require.Equal(t, "Be brief.", reqs[0].Instructions)
+	require.Equal(t, "resp_1", reqs[1].PreviousResponseID)
+}
+
+func TestServer_Stream(t *testing.T) {
+	srv := promptstest.NewServer(t, promptstest.Text("It is sunny."))
+
+	var deltas []string
+	var completed *openai.Response
+
+	for e, err := range openai.NewStreamer(srv.Client()).Stream(context.Background(), openai.NewResponseRequest()) {
+		require.NoError(t, err)
+
+		switch ev := e.Event.(type) {
+		case openai.ResponseStreamEventTextDelta:
+			deltas = append(deltas, ev.Delta)
+		case openai.ResponseStreamEventResponse:
+			if e.Type == openai.ResponseStreamEventTypeCompleted {
+				completed = &ev.Response
+			}
+		default:
+		}
+	}
+
+	require.Equal(t, []string{"It ", "is ", "sunny."}, deltas)
+	require.NotNil(t, completed)
+	require.Equal(t, "It is sunny.", completed.OutputText())
+}
+
+func TestServer_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply promptstest.Reply
+		code  int
+	}{
+		{
+			name:  "rate limit",
+			reply: promptstest.Error(http.StatusTooManyRequests, "rate limit exceeded"),
+			code:  http.StatusTooManyRequests,
+		},
+		{
+			name:  "server error",
+			reply: promptstest.Error(http.StatusInternalServerError, "internal error"),
+			code:  http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := promptstest.NewServer(t, tt.reply)
+
+			_, err := openai.New(srv.Client()).Respond(context.Background(), openai.NewResponseRequest())
+
+			var perr *prompts.PromptError
+			require.True(t, errors.As(err, &perr))
+			require.Equal(t, tt.code, perr.StatusCode)
+		})
+	}
+}
+
+func TestServer_Latency(t *testing.T) {
+	srv := promptstest.NewServer(t, promptstest.Text("late").WithLatency(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := openai.New(srv.Client()).Respond(ctx, openai.NewResponseRequest())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestServer_HandleFunc(t *testing.T) {
+	srv := promptstest.NewServer(t)
+	srv.HandleFunc(func(req *openai.ResponseRequest) promptstest.Reply {
+		return promptstest.Text(strings.ToUpper(req.Instructions))
+	})
+
+	res, err := openai.New(srv.Client()).Respond(context.Background(), openai.NewResponseRequest(openai.WithInstructions("echo")))
+	require.NoError(t, err)
+	require.Equal(t, "ECHO", res.OutputText())
+}
@@ -0,0 +1,314 @@
+package prompts
+
+import (
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned when a request would exceed the rate limit and
+// the caller is not willing to wait for it.
+var ErrRateLimited = errors.New("prompts: rate limit exceeded")
+
+// bytesPerToken is the rough number of bytes per token used to estimate
+// the token count of a request.
+const bytesPerToken = 4
+
+// DefaultRateLimitIdleTimeout is the default time after which the budget of an
+// unused API key is forgotten. Budgets refill within a minute, so forgetting
+// them afterwards only drops the bookkeeping.
+const DefaultRateLimitIdleTimeout = 10 * time.Minute
+
+var _ Doer = (*RateLimitDoer)(nil)
+
+// TokenEstimator estimates the number of tokens consumed by a request.
+type TokenEstimator func(req *http.Request) int
+
+// RateLimitOpt is a function type for configuring the RateLimitDoer.
+type RateLimitOpt func(*RateLimitDoer)
+
+// RateLimitDoer is a Doer that limits the requests and tokens per minute
+// sent with each API key. The budget adapts to the x-ratelimit-remaining-*
+// and x-ratelimit-reset-* headers sent by the provider. The budgets of API
+// keys that are idle for longer than the idle timeout are evicted.
+type RateLimitDoer struct {
+	doer        Doer
+	rpm         int
+	tpm         int
+	estimator   TokenEstimator
+	failFast    bool
+	idleTimeout time.Duration
+
+	mu    sync.Mutex
+	keys  map[[sha256.Size]byte]*rateLimits
+	swept time.Time
+}
+
+// rateLimits are the buckets of a single API key.
+type rateLimits struct {
+	requests *bucket
+	tokens   *bucket
+	used     time.Time
+}
+
+// NewRateLimitDoer wraps the given Doer with a rate limit. If a nil doer
+// is given, the http.DefaultClient will be used. Limits of zero are not
+// enforced locally but still honour the provider headers.
+//
+//	doer := prompts.NewRateLimitDoer(prompts.DefaultClient, prompts.WithRequestsPerMinute(60))
+//	client := prompts.NewClient().Doer(doer)
+func NewRateLimitDoer(doer Doer, opts ...RateLimitOpt) *RateLimitDoer {
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+
+	r := &RateLimitDoer{
+		doer:        doer,
+		estimator:   EstimateTokens,
+		idleTimeout: DefaultRateLimitIdleTimeout,
+		keys:        make(map[[sha256.Size]byte]*rateLimits),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithRequestsPerMinute sets the maximum number of requests per minute per API key.
+func WithRequestsPerMinute(n int) RateLimitOpt {
+	return func(r *RateLimitDoer) {
+		r.rpm = max(n, 0)
+	}
+}
+
+// WithTokensPerMinute sets the maximum number of tokens per minute per API key.
+func WithTokensPerMinute(n int) RateLimitOpt {
+	return func(r *RateLimitDoer) {
+		r.tpm = max(n, 0)
+	}
+}
+
+// WithTokenEstimator sets the estimator for the tokens consumed by a request.
+func WithTokenEstimator(estimator TokenEstimator) RateLimitOpt {
+	return func(r *RateLimitDoer) {
+		if estimator != nil {
+			r.estimator = estimator
+		}
+	}
+}
+
+// WithIdleTimeout sets the time after which the budget of an unused API key
+// is evicted. Defaults to DefaultRateLimitIdleTimeout.
+func WithIdleTimeout(d time.Duration) RateLimitOpt {
+	return func(r *RateLimitDoer) {
+		if d > 0 {
+			r.idleTimeout = d
+		}
+	}
+}
+
+// WithFailFast returns ErrRateLimited instead of waiting for the budget.
+func WithFailFast() RateLimitOpt {
+	return func(r *RateLimitDoer) {
+		r.failFast = true
+	}
+}
+
+// EstimateTokens estimates the tokens of a request from the size of its body.
+func EstimateTokens(req *http.Request) int {
+	if req.ContentLength <= 0 {
+		return 0
+	}
+
+	return int(req.ContentLength / bytesPerToken)
+}
+
+// Do waits for the budget of the API key and sends the request. It fails fast
+// with ErrRateLimited if the wait exceeds the deadline of the request context.
+func (r *RateLimitDoer) Do(req *http.Request) (*http.Response, error) {
+	now := time.Now()
+
+	limits := r.limits(req, now)
+	tokens := float64(r.estimator(req))
+
+	wait := max(limits.requests.reserve(1, now), limits.tokens.reserve(tokens, now))
+
+	if wait > 0 {
+		deadline, ok := req.Context().Deadline()
+		if r.failFast || (ok && now.Add(wait).After(deadline)) {
+			limits.requests.cancel(1)
+			limits.tokens.cancel(tokens)
+
+			return nil, ErrRateLimited
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			limits.requests.cancel(1)
+			limits.tokens.cancel(tokens)
+
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+	}
+
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	now = time.Now()
+	limits.requests.update(resp, "Requests", now)
+	limits.tokens.update(resp, "Tokens", now)
+
+	return resp, err
+}
+
+// limits returns the buckets for the API key of the request.
+func (r *RateLimitDoer) limits(req *http.Request, now time.Time) *rateLimits {
+	key := sha256.Sum256([]byte(apiKey(req)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evict(now)
+
+	l, ok := r.keys[key]
+	if !ok {
+		l = &rateLimits{
+			requests: newBucket(r.rpm),
+			tokens:   newBucket(r.tpm),
+		}
+		r.keys[key] = l
+	}
+	l.used = now
+
+	return l
+}
+
+// evict removes the buckets of API keys that are idle for longer than the
+// idle timeout and not blocked by the provider. The keys are swept at most
+// once per idle timeout. Caller must hold the lock.
+func (r *RateLimitDoer) evict(now time.Time) {
+	if now.Sub(r.swept) < r.idleTimeout {
+		return
+	}
+	r.swept = now
+
+	for key, l := range r.keys {
+		if now.Sub(l.used) >= r.idleTimeout && !l.requests.isBlocked(now) && !l.tokens.isBlocked(now) {
+			delete(r.keys, key)
+		}
+	}
+}
+
+// apiKey returns the credentials of the request.
+func apiKey(req *http.Request) string {
+	for _, h := range []string{"Authorization", "Api-Key", "X-Api-Key", "X-Goog-Api-Key"} {
+		if v := req.Header.Get(h); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// bucket is a token bucket refilled continuously over a minute.
+type bucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	last     time.Time
+	blocked  time.Time
+}
+
+func newBucket(perMinute int) *bucket {
+	return &bucket{
+		capacity: float64(perMinute),
+		tokens:   float64(perMinute),
+	}
+}
+
+// refill adds the tokens accrued since the last refill.
+// Caller must hold the lock.
+func (b *bucket) refill(now time.Time) {
+	if !b.last.IsZero() && b.capacity > 0 {
+		elapsed := now.Sub(b.last).Minutes()
+		b.tokens = min(b.capacity, b.tokens+elapsed*b.capacity)
+	}
+	b.last = now
+}
+
+// reserve takes n tokens from the bucket and returns the time to wait
+// until they are available.
+func (b *bucket) reserve(n float64, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var wait time.Duration
+	if b.blocked.After(now) {
+		wait = b.blocked.Sub(now)
+	}
+
+	if b.capacity <= 0 {
+		return wait
+	}
+
+	b.refill(now)
+	b.tokens -= min(n, b.capacity)
+
+	if b.tokens < 0 {
+		wait = max(wait, time.Duration(-b.tokens/b.capacity*float64(time.Minute)))
+	}
+
+	return wait
+}
+
+// isBlocked returns true if the provider blocked the bucket beyond now.
+func (b *bucket) isBlocked(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.blocked.After(now)
+}
+
+// cancel returns n tokens to the bucket.
+func (b *bucket) cancel(n float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.capacity <= 0 {
+		return
+	}
+
+	b.tokens = min(b.capacity, b.tokens+min(n, b.capacity))
+}
+
+// update adapts the bucket to the x-ratelimit-remaining-* and
+// x-ratelimit-reset-* headers of the response.
+func (b *bucket) update(resp *http.Response, kind string, now time.Time) {
+	remaining, err := strconv.ParseFloat(resp.Header.Get("X-Ratelimit-Remaining-"+kind), 64)
+	if err != nil {
+		return
+	}
+	reset, hasReset := ParseResetHeader(resp.Header.Get("X-Ratelimit-Reset-"+kind), now)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.capacity > 0 {
+		b.refill(now)
+		b.tokens = min(b.tokens, remaining)
+	}
+
+	if remaining <= 0 && hasReset {
+		b.blocked = now.Add(reset)
+	}
+}
@@ -0,0 +1,126 @@
+package prompts_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/katallaxie/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitDoer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	doer := prompts.NewRateLimitDoer(srv.Client(), prompts.WithRequestsPerMinute(1), prompts.WithFailFast())
+	c := prompts.NewClient().Doer(doer).Base(srv.URL)
+
+	_, err := c.New().APIKey("foo").ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+
+	_, err = c.New().APIKey("foo").ReceiveSuccess(context.Background(), nil)
+	require.ErrorIs(t, err, prompts.ErrRateLimited)
+
+	_, err = c.New().APIKey("bar").ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+}
+
+func TestRateLimitDoerDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining-Requests", "0")
+		w.Header().Set("X-Ratelimit-Reset-Requests", "1m0s")
+	}))
+	defer srv.Close()
+
+	doer := prompts.NewRateLimitDoer(srv.Client())
+	c := prompts.NewClient().Doer(doer).Base(srv.URL).APIKey("foo")
+
+	_, err := c.ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err = c.ReceiveSuccess(ctx, nil)
+	require.ErrorIs(t, err, prompts.ErrRateLimited)
+}
+
+func TestRateLimitDoerKeys(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	doer := prompts.NewRateLimitDoer(srv.Client(), prompts.WithRequestsPerMinute(1), prompts.WithFailFast())
+	c := prompts.NewClient().Doer(doer).Base(srv.URL)
+
+	tests := []struct {
+		name string
+		req  *prompts.Client
+		err  error
+	}{
+		{name: "authorization", req: c.New().APIKey("foo")},
+		{name: "same key", req: c.New().APIKey("foo"), err: prompts.ErrRateLimited},
+		{name: "other key", req: c.New().APIKey("bar")},
+		{name: "api key header", req: c.New().Set("Api-Key", "qux")},
+		{name: "x-api-key header", req: c.New().Set("X-Api-Key", "baz")},
+		{name: "anonymous", req: c.New()},
+		{name: "anonymous again", req: c.New(), err: prompts.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.req.ReceiveSuccess(context.Background(), nil)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRateLimitDoerHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining-Requests", "0")
+		w.Header().Set("X-Ratelimit-Reset-Requests", "100ms")
+	}))
+	defer srv.Close()
+
+	doer := prompts.NewRateLimitDoer(srv.Client(), prompts.WithFailFast())
+	c := prompts.NewClient().Doer(doer).Base(srv.URL).APIKey("foo")
+
+	_, err := c.ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+
+	_, err = c.ReceiveSuccess(context.Background(), nil)
+	require.ErrorIs(t, err, prompts.ErrRateLimited)
+
+	time.Sleep(150 * time.Millisecond)
+
+	_, err = c.ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+}
+
+func TestRateLimitDoerIdleTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	doer := prompts.NewRateLimitDoer(srv.Client(),
+		prompts.WithRequestsPerMinute(1),
+		prompts.WithIdleTimeout(50*time.Millisecond),
+		prompts.WithFailFast(),
+	)
+	c := prompts.NewClient().Doer(doer).Base(srv.URL).APIKey("foo")
+
+	_, err := c.ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+
+	// the budget of the idle key is evicted and starts full again.
+	_, err = c.ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,289 @@
+package prompts
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the default number of retries.
+	DefaultMaxRetries = 3
+	// DefaultMinBackoff is the default initial backoff between retries.
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the default maximum backoff between retries.
+	DefaultMaxBackoff = 8 * time.Second
+	// DefaultMaxRetryAfter is the default maximum wait honoured from a Retry-After header.
+	DefaultMaxRetryAfter = 60 * time.Second
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+var _ Doer = (*RetryDoer)(nil)
+
+// RetryPolicy decides whether a request should be retried given the
+// response or error of the last attempt.
+type RetryPolicy func(req *http.Request, resp *http.Response, err error) bool
+
+// RetryOpt is a function type for configuring the RetryDoer.
+type RetryOpt func(*RetryDoer)
+
+// RetryDoer is a Doer that retries failed requests with exponential backoff
+// and jitter. It honours the Retry-After and provider rate-limit headers and
+// rewinds the request body between attempts.
+type RetryDoer struct {
+	doer          Doer
+	maxRetries    int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	maxRetryAfter time.Duration
+	policy        RetryPolicy
+}
+
+// NewRetryDoer wraps the given Doer with retries. If a nil doer is given,
+// the http.DefaultClient will be used.
+//
+//	client := prompts.NewClient().Doer(prompts.NewRetryDoer(prompts.DefaultClient))
+func NewRetryDoer(doer Doer, opts ...RetryOpt) *RetryDoer {
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+
+	r := &RetryDoer{
+		doer:          doer,
+		maxRetries:    DefaultMaxRetries,
+		minBackoff:    DefaultMinBackoff,
+		maxBackoff:    DefaultMaxBackoff,
+		maxRetryAfter: DefaultMaxRetryAfter,
+		policy:        DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithMaxRetries sets the maximum number of retries.
+func WithMaxRetries(n int) RetryOpt {
+	return func(r *RetryDoer) {
+		r.maxRetries = max(n, 0)
+	}
+}
+
+// WithBackoff sets the initial and maximum backoff between retries.
+func WithBackoff(minBackoff, maxBackoff time.Duration) RetryOpt {
+	return func(r *RetryDoer) {
+		r.minBackoff = minBackoff
+		r.maxBackoff = max(minBackoff, maxBackoff)
+	}
+}
+
+// WithMaxRetryAfter sets the maximum wait honoured from a Retry-After header.
+// Responses asking for a longer wait are returned to the caller.
+func WithMaxRetryAfter(d time.Duration) RetryOpt {
+	return func(r *RetryDoer) {
+		r.maxRetryAfter = d
+	}
+}
+
+// WithRetryPolicy sets the policy deciding which attempts are retried.
+func WithRetryPolicy(policy RetryPolicy) RetryOpt {
+	return func(r *RetryDoer) {
+		if policy != nil {
+			r.policy = policy
+		}
+	}
+}
+
+// DefaultRetryPolicy retries transport errors of idempotent requests,
+// timeouts, rate limits (429) and server errors (5XX). A provider may
+// override the decision with the X-Should-Retry header.
+func DefaultRetryPolicy(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil && isIdempotent(req)
+	}
+
+	switch strings.ToLower(resp.Header.Get("X-Should-Retry")) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdempotent returns true if the request can safely be sent again.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get(idempotencyKeyHeader) != ""
+}
+
+// Do sends the request and retries it according to the retry policy.
+func (r *RetryDoer) Do(req *http.Request) (*http.Response, error) {
+	if err := rewindable(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := r.doer.Do(req)
+		if attempt >= r.maxRetries || !r.policy(req, resp, err) {
+			return resp, err
+		}
+
+		wait := r.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryWait(resp, time.Now()); ok {
+				if d > r.maxRetryAfter {
+					return resp, err
+				}
+				wait = d
+			}
+
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+	}
+}
+
+// backoff returns the exponential backoff with jitter for the given attempt.
+func (r *RetryDoer) backoff(attempt int) time.Duration {
+	// the exponent is capped before shifting, so that the backoff never overflows
+	d := r.maxBackoff
+	if r.minBackoff > 0 && attempt < 63 && r.minBackoff <= r.maxBackoff>>attempt {
+		d = r.minBackoff << attempt
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	// equal jitter keeps at least half of the backoff
+	half := d / 2
+
+	return half + rand.N(half+1)
+}
+
+// rewindable makes sure the body of the request can be replayed. Bodies
+// without a GetBody func are buffered in memory.
+func rewindable(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(b))
+
+	return nil
+}
+
+// retryWait returns the wait time requested by the response. Rate limited
+// responses without a Retry-After header fall back to the provider
+// rate-limit reset headers.
+func retryWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if d, ok := RetryAfter(resp.Header, now); ok {
+		return d, true
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	var wait time.Duration
+	var ok bool
+
+	for _, k := range []string{"X-Ratelimit-Reset-Requests", "X-Ratelimit-Reset-Tokens"} {
+		if d, found := ParseResetHeader(resp.Header.Get(k), now); found && d > wait {
+			wait, ok = d, true
+		}
+	}
+
+	return wait, ok
+}
+
+// RetryAfter returns the wait time requested by the Retry-After-Ms or
+// Retry-After headers. Retry-After may be a number of seconds or an HTTP date.
+func RetryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	if v := h.Get("Retry-After-Ms"); v != "" {
+		if ms, err := strconv.ParseFloat(v, 64); err == nil && ms >= 0 {
+			return time.Duration(ms * float64(time.Millisecond)), true
+		}
+	}
+
+	if v := h.Get("Retry-After"); v != "" {
+		if s, err := strconv.ParseFloat(v, 64); err == nil && s >= 0 {
+			return time.Duration(s * float64(time.Second)), true
+		}
+
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseResetHeader parses a rate-limit reset header value. Values can be a
+// duration (e.g. "6m0s", "20ms"), a number of seconds or an RFC 3339 timestamp.
+func ParseResetHeader(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if d, err := time.ParseDuration(v); err == nil {
+		return max(d, 0), true
+	}
+
+	if s, err := strconv.ParseFloat(v, 64); err == nil && s >= 0 {
+		return time.Duration(s * float64(time.Second)), true
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+
+	return 0, false
+}
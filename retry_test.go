@@ -0,0 +1,175 @@
+package prompts_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/katallaxie/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDoer(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		header   http.Header
+		want     int
+		attempts int32
+	}{
+		{
+			name:     "success",
+			statuses: []int{http.StatusOK},
+			want:     http.StatusOK,
+			attempts: 1,
+		},
+		{
+			name:     "rate limited",
+			statuses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After-Ms": []string{"1"}},
+			want:     http.StatusOK,
+			attempts: 3,
+		},
+		{
+			name:     "server error",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:     http.StatusOK,
+			attempts: 2,
+		},
+		{
+			name:     "client error",
+			statuses: []int{http.StatusBadRequest, http.StatusOK},
+			want:     http.StatusBadRequest,
+			attempts: 1,
+		},
+		{
+			name:     "max retries",
+			statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			want:     http.StatusBadGateway,
+			attempts: 3,
+		},
+		{
+			name:     "retry after too long",
+			statuses: []int{http.StatusTooManyRequests, http.StatusOK},
+			header:   http.Header{"Retry-After": []string{"3600"}},
+			want:     http.StatusTooManyRequests,
+			attempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, `{"model":"foo"}`, string(b))
+
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tt.statuses[min(int(n), len(tt.statuses))-1])
+			}))
+			defer srv.Close()
+
+			doer := prompts.NewRetryDoer(srv.Client(), prompts.WithMaxRetries(2), prompts.WithBackoff(time.Millisecond, 2*time.Millisecond))
+			c := prompts.NewClient().Doer(doer).Base(srv.URL).Post("responses").BodyJSON(map[string]string{"model": "foo"})
+
+			resp, _ := c.ReceiveSuccess(context.Background(), nil)
+			require.NotNil(t, resp)
+			require.Equal(t, tt.want, resp.StatusCode)
+			require.Equal(t, tt.attempts, attempts.Load())
+		})
+	}
+}
+
+func TestRetryDoerBodyReplay(t *testing.T) {
+	var attempts atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(b))
+
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	doer := prompts.NewRetryDoer(srv.Client(), prompts.WithBackoff(time.Millisecond, time.Millisecond))
+	c := prompts.NewClient().Doer(doer).Base(srv.URL).Post("upload").Body(io.NopCloser(strings.NewReader("hello")))
+
+	resp, err := c.ReceiveSuccess(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), attempts.Load())
+}
+
+func TestRetryDoerContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	doer := prompts.NewRetryDoer(srv.Client(), prompts.WithBackoff(time.Second, time.Second))
+	_, err := prompts.NewClient().Doer(doer).Base(srv.URL).ReceiveSuccess(ctx, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRetryDoerManyRetries(t *testing.T) {
+	var attempts atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	// the backoff of late attempts stays capped instead of overflowing
+	doer := prompts.NewRetryDoer(srv.Client(), prompts.WithMaxRetries(80), prompts.WithBackoff(time.Microsecond, 50*time.Microsecond))
+
+	start := time.Now()
+	resp, _ := prompts.NewClient().Doer(doer).Base(srv.URL).ReceiveSuccess(context.Background(), nil)
+	require.NotNil(t, resp)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.Equal(t, int32(81), attempts.Load())
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestParseResetHeader(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{name: "empty", value: "", want: 0, ok: false},
+		{name: "duration", value: "6m0s", want: 6 * time.Minute, ok: true},
+		{name: "milliseconds", value: "20ms", want: 20 * time.Millisecond, ok: true},
+		{name: "seconds", value: "1.5", want: 1500 * time.Millisecond, ok: true},
+		{name: "timestamp", value: "2026-01-01T00:00:30Z", want: 30 * time.Second, ok: true},
+		{name: "invalid", value: "soon", want: 0, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := prompts.ParseResetHeader(tt.value, now)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
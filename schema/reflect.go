@@ -0,0 +1,474 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Opt is a function type for configuring the reflection of a schema.
+type Opt func(*reflector)
+
+// WithStrict generates a schema for strict structured outputs. All
+// properties are required, optional properties are nullable and additional
+// properties are disallowed. Maps, interfaces and raw JSON values are not
+// supported in strict mode.
+func WithStrict() Opt {
+	return func(r *reflector) {
+		r.strict = true
+	}
+}
+
+// For generates the schema for the type T.
+//
+//	type Horoscope struct {
+//		Sign string `json:"sign" jsonschema:"description=The zodiac sign,enum=aries,enum=taurus"`
+//		Days int    `json:"days,omitempty" jsonschema:"minimum=1,maximum=7"`
+//	}
+//
+//	s, err := schema.For[Horoscope](schema.WithStrict())
+func For[T any](opts ...Opt) (*Schema, error) {
+	return Reflect(reflect.TypeFor[T](), opts...)
+}
+
+// Reflect generates the schema for the given type. Properties are named by
+// their json tag and described by their jsonschema tag, which is a comma
+// separated list of description, title, enum, format, pattern, minimum,
+// maximum, minLength, maxLength, minItems, maxItems, default, required and
+// optional. Commas in values can be escaped with a backslash.
+func Reflect(t reflect.Type, opts ...Opt) (*Schema, error) {
+	r := &reflector{
+		defs:     make(map[string]*Schema),
+		visiting: make(map[reflect.Type]bool),
+		names:    make(map[reflect.Type]string),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	r.root = t
+
+	s, err := r.reflect(t)
+	if err != nil {
+		return nil, fmt.Errorf("schema: %w", err)
+	}
+
+	if len(r.defs) > 0 {
+		s.Defs = r.defs
+	}
+
+	return s, nil
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	rawType       = reflect.TypeFor[json.RawMessage]()
+	marshalerType = reflect.TypeFor[json.Marshaler]()
+	textType      = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+type reflector struct {
+	strict   bool
+	root     reflect.Type
+	defs     map[string]*Schema
+	visiting map[reflect.Type]bool
+	names    map[reflect.Type]string
+}
+
+func (r *reflector) reflect(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: TypeList{TypeString}, Format: "date-time"}, nil
+	case t == rawType:
+		return r.any(t)
+	case t.Kind() != reflect.Struct && t.Implements(textType) && !t.Implements(marshalerType):
+		return &Schema{Type: TypeList{TypeString}}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: TypeList{TypeBoolean}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: TypeList{TypeInteger}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeList{TypeNumber}}, nil
+	case reflect.String:
+		return &Schema{Type: TypeList{TypeString}}, nil
+	case reflect.Interface:
+		return r.any(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: TypeList{TypeString}, Format: "byte"}, nil
+		}
+
+		items, err := r.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: TypeList{TypeArray}, Items: items}, nil
+	case reflect.Map:
+		if r.strict {
+			return nil, fmt.Errorf("unsupported map type %s in strict mode", t)
+		}
+
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+
+		values, err := r.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: TypeList{TypeObject}, AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return r.reflectStruct(t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+// any returns the empty schema that accepts any value, which has no
+// properties to require and is therefore not supported in strict mode.
+func (r *reflector) any(t reflect.Type) (*Schema, error) {
+	if r.strict {
+		return nil, fmt.Errorf("unsupported type %s in strict mode", t)
+	}
+
+	return &Schema{}, nil
+}
+
+func (r *reflector) reflectStruct(t reflect.Type) (*Schema, error) {
+	if r.visiting[t] {
+		if t == r.root {
+			return &Schema{Ref: "#"}, nil // the root is inlined and referenced as the document
+		}
+
+		return &Schema{Ref: "#/$defs/" + r.name(t)}, nil
+	}
+
+	r.visiting[t] = true
+	defer delete(r.visiting, t)
+
+	s := &Schema{
+		Type:       TypeList{TypeObject},
+		Properties: make(map[string]*Schema),
+	}
+
+	if r.strict {
+		s.AdditionalProperties = False()
+	}
+
+	if err := r.reflectFields(t, s); err != nil {
+		return nil, err
+	}
+
+	// recursive types are referenced from the definitions
+	if name, ok := r.names[t]; ok {
+		r.defs[name] = s
+		return &Schema{Ref: "#/$defs/" + name}, nil
+	}
+
+	return s, nil
+}
+
+func (r *reflector) reflectFields(t reflect.Type, s *Schema) error {
+	for i := range t.NumField() {
+		f := t.Field(i)
+
+		name, omitempty, skip := jsonName(f)
+		if skip {
+			continue
+		}
+
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				if err := r.reflectFields(ft, s); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		prop, err := r.reflect(f.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		tag, err := parseTag(f.Tag.Get("jsonschema"))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		if err := tag.apply(prop, f.Type); err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+
+		optional := omitempty || f.Type.Kind() == reflect.Pointer
+		if tag.required {
+			optional = false
+		}
+		if tag.optional {
+			optional = true
+		}
+
+		if r.strict && optional {
+			prop = nullable(prop)
+		}
+
+		s.Properties[name] = prop
+
+		if r.strict || !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return nil
+}
+
+// name returns the definition name for a recursive type.
+func (r *reflector) name(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if name == "" {
+		name = "T"
+	}
+
+	base := name
+	for i := 2; ; i++ {
+		if _, ok := r.defs[name]; !ok && !r.hasName(name) {
+			break
+		}
+		name = base + strconv.Itoa(i)
+	}
+	r.names[t] = name
+
+	return name
+}
+
+func (r *reflector) hasName(name string) bool {
+	for _, n := range r.names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nullable allows null values for the schema.
+func nullable(s *Schema) *Schema {
+	switch {
+	case s.Ref != "":
+		return &Schema{AnyOf: []*Schema{s, {Type: TypeList{TypeNull}}}}
+	case len(s.Type) == 0 || s.Type.Has(TypeNull):
+		return s
+	}
+
+	s.Type = append(s.Type, TypeNull)
+	if len(s.Enum) > 0 {
+		s.Enum = append(s.Enum, nil)
+	}
+
+	return s
+}
+
+// jsonName returns the name of the field from its json tag.
+func jsonName(f reflect.StructField) (name string, omitempty, skip bool) {
+	tag, ok := f.Tag.Lookup("json")
+	if !ok {
+		return "", false, false
+	}
+
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+
+	return name, omitempty, false
+}
+
+// tag is the parsed jsonschema tag of a field.
+type tag struct {
+	values   map[string][]string
+	required bool
+	optional bool
+}
+
+// parseTag parses a jsonschema tag (e.g. "description=The sign,enum=aries,enum=leo").
+func parseTag(s string) (tag, error) {
+	t := tag{values: make(map[string][]string)}
+	if s == "" {
+		return t, nil
+	}
+
+	for _, part := range splitTag(s) {
+		key, value, ok := strings.Cut(part, "=")
+		switch {
+		case !ok && key == "required":
+			t.required = true
+		case !ok && key == "optional":
+			t.optional = true
+		case !ok:
+			return t, fmt.Errorf("invalid jsonschema tag %q", part)
+		default:
+			t.values[key] = append(t.values[key], value)
+		}
+	}
+
+	return t, nil
+}
+
+// splitTag splits a tag by commas that are not escaped by a backslash.
+func splitTag(s string) []string {
+	var parts []string
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == ',':
+			sb.WriteByte(',')
+			i++
+		case s[i] == ',':
+			parts = append(parts, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return append(parts, sb.String())
+}
+
+// apply applies the tag to the schema of a field of the given type.
+func (t tag) apply(s *Schema, ft reflect.Type) error {
+	for ft.Kind() == reflect.Pointer {
+		ft = ft.Elem()
+	}
+
+	for key, values := range t.values {
+		value := values[len(values)-1]
+
+		var err error
+
+		switch key {
+		case "description":
+			s.Description = value
+		case "title":
+			s.Title = value
+		case "format":
+			s.Format = value
+		case "pattern":
+			s.Pattern = value
+		case "enum":
+			target, et := s, ft
+			if s.Items != nil && (ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array) {
+				target, et = s.Items, ft.Elem() // enum of the items of an array
+			}
+
+			target.Enum = make([]any, 0, len(values))
+			for _, v := range values {
+				ev, err := parseValue(v, et)
+				if err != nil {
+					return err
+				}
+				target.Enum = append(target.Enum, ev)
+			}
+		case "default":
+			s.Default, err = parseValue(value, ft)
+		case "minimum":
+			s.Minimum, err = parseFloat(value)
+		case "maximum":
+			s.Maximum, err = parseFloat(value)
+		case "minLength":
+			s.MinLength, err = parseInt(value)
+		case "maxLength":
+			s.MaxLength, err = parseInt(value)
+		case "minItems":
+			s.MinItems, err = parseInt(value)
+		case "maxItems":
+			s.MaxItems, err = parseInt(value)
+		default:
+			return fmt.Errorf("unknown jsonschema tag key %q", key)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseValue parses a tag value into a value of the kind of the given type.
+func parseValue(v string, t reflect.Type) (any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(v, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(v, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(v)
+	default:
+		return v, nil
+	}
+}
+
+func parseFloat(v string) (*float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func parseInt(v string) (*int, error) {
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
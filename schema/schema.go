@@ -0,0 +1,151 @@
+// Package schema generates and validates JSON Schemas from Go types.
+package schema
+
+import (
+	"encoding/json"
+)
+
+// Available JSON types.
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+	TypeNull    = "null"
+)
+
+// TypeList is the list of types of a schema. A single type is encoded as a
+// string and multiple types as an array (e.g. ["string", "null"]).
+type TypeList []string
+
+// MarshalJSON marshals the type list into JSON.
+func (t TypeList) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON unmarshals the type list from JSON.
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*t = multi
+
+	return nil
+}
+
+// Has returns true if the type list contains the given type.
+func (t TypeList) Has(typ string) bool {
+	for _, v := range t {
+		if v == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Schema is a JSON Schema.
+// See https://json-schema.org/understanding-json-schema for details.
+//
+//nolint:tagliatelle
+type Schema struct {
+	// Ref is the reference to a definition.
+	Ref string `json:"$ref,omitempty"`
+	// Defs are the definitions of recursive types.
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+	// Type is the type of the value.
+	Type TypeList `json:"type,omitempty"`
+	// Title is the title of the value.
+	Title string `json:"title,omitempty"`
+	// Description is the description of the value.
+	Description string `json:"description,omitempty"`
+	// Enum is the list of allowed values.
+	Enum []any `json:"enum,omitempty"`
+	// Default is the default value.
+	Default any `json:"default,omitempty"`
+	// Format is the format of a string (e.g. "date-time").
+	Format string `json:"format,omitempty"`
+	// Pattern is the regular expression a string must match.
+	Pattern string `json:"pattern,omitempty"`
+	// MinLength is the minimum length of a string.
+	MinLength *int `json:"minLength,omitempty"`
+	// MaxLength is the maximum length of a string.
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Minimum is the minimum of a number.
+	Minimum *float64 `json:"minimum,omitempty"`
+	// Maximum is the maximum of a number.
+	Maximum *float64 `json:"maximum,omitempty"`
+	// Items is the schema of the items of an array.
+	Items *Schema `json:"items,omitempty"`
+	// MinItems is the minimum number of items of an array.
+	MinItems *int `json:"minItems,omitempty"`
+	// MaxItems is the maximum number of items of an array.
+	MaxItems *int `json:"maxItems,omitempty"`
+	// Properties are the properties of an object.
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	// Required are the required properties of an object.
+	Required []string `json:"required,omitempty"`
+	// AnyOf is the list of schemas of which at least one must match.
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	// AdditionalProperties is the schema of additional properties of an
+	// object. A schema without type and properties is encoded as false.
+	AdditionalProperties *Schema `json:"additionalProperties,omitempty"`
+}
+
+// False returns a schema that matches nothing. It is used to disallow
+// additional properties.
+func False() *Schema {
+	return &Schema{Type: TypeList{}}
+}
+
+// MarshalJSON marshals the schema into JSON.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	if s.isFalse() {
+		return []byte("false"), nil
+	}
+
+	type alias Schema
+
+	return json.Marshal((*alias)(s))
+}
+
+// UnmarshalJSON unmarshals the schema from JSON.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		if !b {
+			*s = Schema{Type: TypeList{}}
+		} else {
+			*s = Schema{}
+		}
+
+		return nil
+	}
+
+	type alias Schema
+
+	return json.Unmarshal(data, (*alias)(s))
+}
+
+// isFalse returns true if the schema is the false schema.
+func (s *Schema) isFalse() bool {
+	return s != nil && s.Type != nil && len(s.Type) == 0
+}
+
+// Raw returns the JSON encoding of the schema.
+func (s *Schema) Raw() (json.RawMessage, error) {
+	return json.Marshal(s)
+}
@@ -0,0 +1,228 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/katallaxie/prompts/schema"
+	"github.com/stretchr/testify/require"
+)
+
+type Horoscope struct {
+	Sign   string   `json:"sign" jsonschema:"description=The zodiac sign,enum=aries,enum=leo"`
+	Days   int      `json:"days,omitempty" jsonschema:"minimum=1,maximum=7"`
+	Topics []string `json:"topics" jsonschema:"enum=love,enum=work"`
+	Note   *string  `json:"note"`
+	Hidden string   `json:"-"`
+}
+
+type Node struct {
+	Name     string  `json:"name"`
+	Children []*Node `json:"children"`
+}
+
+type Tree struct {
+	Root Node `json:"root"`
+}
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(opts ...schema.Opt) (*schema.Schema, error)
+		want string
+	}{
+		{
+			name: "struct",
+			fn:   schema.For[Horoscope],
+			want: `{
+				"type": "object",
+				"properties": {
+					"sign": {"type": "string", "description": "The zodiac sign", "enum": ["aries", "leo"]},
+					"days": {"type": "integer", "minimum": 1, "maximum": 7},
+					"topics": {"type": "array", "items": {"type": "string", "enum": ["love", "work"]}},
+					"note": {"type": "string"}
+				},
+				"required": ["sign", "topics"]
+			}`,
+		},
+		{
+			name: "strict struct",
+			fn: func(...schema.Opt) (*schema.Schema, error) {
+				return schema.For[Horoscope](schema.WithStrict())
+			},
+			want: `{
+				"type": "object",
+				"properties": {
+					"sign": {"type": "string", "description": "The zodiac sign", "enum": ["aries", "leo"]},
+					"days": {"type": ["integer", "null"], "minimum": 1, "maximum": 7},
+					"topics": {"type": "array", "items": {"type": "string", "enum": ["love", "work"]}},
+					"note": {"type": ["string", "null"]}
+				},
+				"required": ["sign", "days", "topics", "note"],
+				"additionalProperties": false
+			}`,
+		},
+		{
+			name: "recursive struct",
+			fn:   schema.For[Node],
+			want: `{
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#"}}
+				},
+				"required": ["name", "children"]
+			}`,
+		},
+		{
+			name: "nested recursive struct",
+			fn:   schema.For[Tree],
+			want: `{
+				"type": "object",
+				"properties": {
+					"root": {"$ref": "#/$defs/Node"}
+				},
+				"required": ["root"],
+				"$defs": {
+					"Node": {
+						"type": "object",
+						"properties": {
+							"name": {"type": "string"},
+							"children": {"type": "array", "items": {"$ref": "#/$defs/Node"}}
+						},
+						"required": ["name", "children"]
+					}
+				}
+			}`,
+		},
+		{
+			name: "map",
+			fn:   schema.For[map[string]float64],
+			want: `{"type": "object", "additionalProperties": {"type": "number"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.fn()
+			require.NoError(t, err)
+
+			b, err := json.Marshal(s)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(b))
+		})
+	}
+}
+
+func TestFor_Strict(t *testing.T) {
+	type Scores struct {
+		Scores map[string]float64 `json:"scores"`
+	}
+
+	type Nested struct {
+		Inner Scores `json:"inner"`
+	}
+
+	type Any struct {
+		Value any `json:"value"`
+	}
+
+	type Raw struct {
+		Value json.RawMessage `json:"value"`
+	}
+
+	tests := []struct {
+		name string
+		fn   func(opts ...schema.Opt) (*schema.Schema, error)
+		err  string
+	}{
+		{
+			name: "map",
+			fn:   schema.For[Scores],
+			err:  "schema: field Scores: unsupported map type map[string]float64 in strict mode",
+		},
+		{
+			name: "nested map",
+			fn:   schema.For[Nested],
+			err:  "schema: field Inner: field Scores: unsupported map type map[string]float64 in strict mode",
+		},
+		{
+			name: "any",
+			fn:   schema.For[Any],
+			err:  "schema: field Value: unsupported type interface {} in strict mode",
+		},
+		{
+			name: "raw message",
+			fn:   schema.For[Raw],
+			err:  fmt.Sprintf("schema: field Value: unsupported type %s in strict mode", reflect.TypeFor[json.RawMessage]()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.fn(schema.WithStrict())
+			require.EqualError(t, err, tt.err)
+
+			_, err = tt.fn()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidate_Recursive(t *testing.T) {
+	s, err := schema.For[Node](schema.WithStrict())
+	require.NoError(t, err)
+
+	require.NoError(t, s.ValidateJSON([]byte(`{"name": "a", "children": [{"name": "b", "children": []}]}`)))
+	require.EqualError(t, s.ValidateJSON([]byte(`{"name": "a", "children": [{"name": 1, "children": []}]}`)), "$.children[0].name: expected string, got number")
+}
+
+func TestValidate(t *testing.T) {
+	s, err := schema.For[Horoscope](schema.WithStrict())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "valid",
+			data: `{"sign": "leo", "days": 3, "topics": ["love"], "note": null}`,
+		},
+		{
+			name: "invalid enum",
+			data: `{"sign": "cat", "days": null, "topics": [], "note": null}`,
+			err:  `$.sign: value cat is not one of [aries leo]`,
+		},
+		{
+			name: "missing property",
+			data: `{"sign": "leo", "days": 3, "topics": []}`,
+			err:  `$: missing required property "note"`,
+		},
+		{
+			name: "additional property",
+			data: `{"sign": "leo", "days": 3, "topics": [], "note": null, "foo": 1}`,
+			err:  `$.foo: value is not allowed`,
+		},
+		{
+			name: "wrong type",
+			data: `{"sign": "leo", "days": 3.5, "topics": ["work", 1], "note": null}`,
+			err:  "$.days: expected integer or null, got number\n$.topics[1]: expected string, got number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateJSON([]byte(tt.data))
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationError is an error of a value not matching a schema.
+type ValidationError struct {
+	// Path is the JSON path of the invalid value (e.g. "$.items[0].name").
+	Path string
+	// Message is the reason why the value is invalid.
+	Message string
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidateJSON validates the JSON encoded data against the schema.
+// All validation errors are joined into the returned error.
+func (s *Schema) ValidateJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	return s.Validate(v)
+}
+
+// Validate validates a decoded JSON value against the schema. All
+// validation errors are joined into the returned error.
+func (s *Schema) Validate(v any) error {
+	var errs []error
+	s.validate(s, "$", v, &errs)
+
+	return errors.Join(errs...)
+}
+
+func (s *Schema) validate(root *Schema, path string, v any, errs *[]error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.isFalse() {
+		fail("value is not allowed")
+		return
+	}
+
+	if s.Ref != "" {
+		ref, ok := root, s.Ref == "#"
+		if !ok {
+			ref, ok = root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		}
+		if !ok {
+			fail("unknown reference %q", s.Ref)
+			return
+		}
+		ref.validate(root, path, v, errs)
+	}
+
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			var subErrs []error
+			sub.validate(root, path, v, &subErrs)
+			if len(subErrs) == 0 {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			fail("value does not match any schema")
+		}
+	}
+
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return isType(v, t) }) {
+		fail("expected %s, got %s", strings.Join(s.Type, " or "), typeOf(v))
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return equal(e, v) }) {
+		fail("value %v is not one of %v", v, s.Enum)
+	}
+
+	switch v := v.(type) {
+	case string:
+		if s.MinLength != nil && utf8.RuneCountInString(v) < *s.MinLength {
+			fail("string is shorter than %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && utf8.RuneCountInString(v) > *s.MaxLength {
+			fail("string is longer than %d", *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(v) {
+				fail("string does not match pattern %q", s.Pattern)
+			}
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("number is less than %v", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			fail("number is greater than %v", *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("array has fewer than %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("array has more than %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(root, fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			switch prop, ok := s.Properties[k]; {
+			case ok:
+				prop.validate(root, path+"."+k, v[k], errs)
+			case s.AdditionalProperties != nil:
+				s.AdditionalProperties.validate(root, path+"."+k, v[k], errs)
+			}
+		}
+	}
+}
+
+// isType returns true if the decoded JSON value is of the given type.
+func isType(v any, t string) bool {
+	switch t {
+	case TypeObject:
+		_, ok := v.(map[string]any)
+		return ok
+	case TypeArray:
+		_, ok := v.([]any)
+		return ok
+	case TypeString:
+		_, ok := v.(string)
+		return ok
+	case TypeNumber:
+		_, ok := v.(float64)
+		return ok
+	case TypeInteger:
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case TypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case TypeNull:
+		return v == nil
+	default:
+		return true
+	}
+}
+
+// typeOf returns the JSON type of the decoded value.
+func typeOf(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return TypeObject
+	case []any:
+		return TypeArray
+	case string:
+		return TypeString
+	case float64:
+		return TypeNumber
+	case bool:
+		return TypeBoolean
+	case nil:
+		return TypeNull
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// equal compares an enum value with a decoded JSON value. Numbers are
+// compared by value.
+func equal(e, v any) bool {
+	if f, ok := v.(float64); ok {
+		switch e := e.(type) {
+		case int64:
+			return float64(e) == f
+		case float64:
+			return e == f
+		}
+	}
+
+	return reflect.DeepEqual(e, v)
+}
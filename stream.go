@@ -0,0 +1,211 @@
+package prompts
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+)
+
+const (
+	acceptHeader           = "Accept"
+	eventStreamContentType = "text/event-stream"
+	ndjsonContentType      = "application/x-ndjson"
+)
+
+// maxBufferSize is the maximum size of a single line in a stream.
+const maxBufferSize = 512 * 1 * 1000
+
+// Event is the structure of a server-sent event received from the server.
+type Event struct {
+	// Type is the type of the event.
+	Type string `json:"type"`
+	// ID is the identifier of the event.
+	ID string `json:"id,omitempty"`
+	// Data is the data of the event.
+	Data []byte `json:"data"`
+}
+
+// Decoder decodes a streamed response body into a sequence of values.
+type Decoder[E any] interface {
+	// Decode decodes the body into a sequence of values. The body is closed
+	// when the sequence is exhausted or the consumer stops.
+	Decode(body io.ReadCloser) iter.Seq2[E, error]
+}
+
+var _ Decoder[Event] = (*SSEDecoder)(nil)
+
+// SSEDecoder is a decoder for server-sent events.
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html for details.
+type SSEDecoder struct{}
+
+// NewSSEDecoder creates a new SSEDecoder.
+func NewSSEDecoder() *SSEDecoder {
+	return &SSEDecoder{}
+}
+
+// Decode decodes the response body into a stream of events. Multiple data
+// lines of an event are joined by a newline and comments are skipped.
+func (d *SSEDecoder) Decode(body io.ReadCloser) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		defer body.Close()
+
+		scn := bufio.NewScanner(body)
+		scn.Split(bufio.ScanLines)
+		scn.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxBufferSize)
+
+		var event Event
+		var data bytes.Buffer
+		var pending bool
+
+		for scn.Scan() {
+			b := scn.Bytes()
+
+			if len(b) == 0 {
+				if !pending {
+					continue
+				}
+
+				event.Data = bytes.Clone(data.Bytes())
+				if !yield(event, nil) {
+					return
+				}
+
+				event = Event{}
+				data.Reset()
+				pending = false
+
+				continue
+			}
+
+			name, value, _ := bytes.Cut(b, []byte(":"))
+			if len(value) > 0 && value[0] == ' ' {
+				value = value[1:]
+			}
+
+			switch string(name) {
+			case "":
+				continue // comment
+			case "event":
+				event.Type = string(value)
+			case "id":
+				event.ID = string(value)
+			case "data":
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.Write(value)
+			default:
+				continue
+			}
+
+			pending = true
+		}
+
+		if err := scn.Err(); err != nil {
+			yield(Event{}, err)
+			return
+		}
+
+		if pending {
+			event.Data = bytes.Clone(data.Bytes())
+			yield(event, nil)
+		}
+	}
+}
+
+var _ Decoder[any] = (*NDJSONDecoder[any])(nil)
+
+// NDJSONDecoder is a decoder for newline delimited JSON, as streamed by
+// Ollama. Every non-empty line is unmarshaled into a value of E.
+// See https://github.com/ndjson/ndjson-spec for details.
+type NDJSONDecoder[E any] struct{}
+
+// NewNDJSONDecoder creates a new NDJSONDecoder.
+func NewNDJSONDecoder[E any]() *NDJSONDecoder[E] {
+	return &NDJSONDecoder[E]{}
+}
+
+// Decode decodes the response body into a stream of values.
+func (d *NDJSONDecoder[E]) Decode(body io.ReadCloser) iter.Seq2[E, error] {
+	return func(yield func(E, error) bool) {
+		defer body.Close()
+
+		scn := bufio.NewScanner(body)
+		scn.Split(bufio.ScanLines)
+		scn.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxBufferSize)
+
+		for scn.Scan() {
+			b := bytes.TrimSpace(scn.Bytes())
+			if len(b) == 0 {
+				continue
+			}
+
+			var e E
+			if err := json.Unmarshal(b, &e); err != nil {
+				yield(e, err)
+				return
+			}
+
+			if !yield(e, nil) {
+				return
+			}
+		}
+
+		if err := scn.Err(); err != nil {
+			var zero E
+			yield(zero, err)
+		}
+	}
+}
+
+// ReceiveStream creates a new HTTP request and decodes the streamed response
+// body with the given decoder. Any error creating the request, sending it or
+// decoding the body is yielded. Non-2XX responses are yielded as a *PromptError.
+// The response body is closed when the consumer stops iterating.
+func ReceiveStream[E any](ctx context.Context, client *Client, decoder Decoder[E]) iter.Seq2[E, error] {
+	return func(yield func(E, error) bool) {
+		var zero E
+
+		req, err := client.Request(ctx)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		if req.Header.Get(acceptHeader) == "" {
+			req.Header.Set(acceptHeader, acceptContentType(decoder))
+		}
+
+		resp, err := client.httpClient.Do(req)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		if !isSuccess(resp.StatusCode) {
+			defer resp.Body.Close()
+			defer io.Copy(io.Discard, resp.Body)
+
+			yield(zero, NewPromptError(resp))
+			return
+		}
+
+		for e, err := range decoder.Decode(resp.Body) {
+			if !yield(e, err) {
+				return
+			}
+		}
+	}
+}
+
+// acceptContentType returns the content type accepted by the decoder.
+func acceptContentType[E any](decoder Decoder[E]) string {
+	if _, ok := decoder.(*NDJSONDecoder[E]); ok {
+		return ndjsonContentType
+	}
+
+	return eventStreamContentType
+}
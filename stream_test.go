@@ -0,0 +1,104 @@
+package prompts_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEDecoder(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []prompts.Event
+	}{
+		{
+			name: "empty data",
+			data: "",
+			want: nil,
+		},
+		{
+			name: "single event",
+			data: "event: response.created\ndata: {\"type\":\"response.created\"}\n\n",
+			want: []prompts.Event{
+				{Type: "response.created", Data: []byte(`{"type":"response.created"}`)},
+			},
+		},
+		{
+			name: "comments and multiline data",
+			data: ": keep-alive\n\nid: 1\ndata: foo\ndata: bar\n\ndata: baz",
+			want: []prompts.Event{
+				{ID: "1", Data: []byte("foo\nbar")},
+				{Data: []byte("baz")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []prompts.Event
+
+			body := io.NopCloser(strings.NewReader(tt.data))
+			for e, err := range prompts.NewSSEDecoder().Decode(body) {
+				require.NoError(t, err)
+				got = append(got, e)
+			}
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	type chunk struct {
+		Content string `json:"content"`
+		Done    bool   `json:"done"`
+	}
+
+	body := io.NopCloser(strings.NewReader("{\"content\":\"Hel\"}\n\n{\"content\":\"lo\"}\n{\"done\":true}"))
+
+	var got []chunk
+	for e, err := range prompts.NewNDJSONDecoder[chunk]().Decode(body) {
+		require.NoError(t, err)
+		got = append(got, e)
+	}
+
+	require.Equal(t, []chunk{{Content: "Hel"}, {Content: "lo"}, {Done: true}}, got)
+
+	body = io.NopCloser(strings.NewReader("{\"content\":\"Hel\"}\n{invalid"))
+
+	var errs int
+	for _, err := range prompts.NewNDJSONDecoder[chunk]().Decode(body) {
+		if err != nil {
+			errs++
+		}
+	}
+
+	require.Equal(t, 1, errs)
+}
+
+func TestReceiveStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: one\n\ndata: two\n\n")
+	}))
+	defer srv.Close()
+
+	c := prompts.NewClient().Base(srv.URL).Post("responses")
+
+	var got []string
+	for e, err := range prompts.ReceiveStream(context.Background(), c, prompts.NewSSEDecoder()) {
+		require.NoError(t, err)
+		got = append(got, string(e.Data))
+	}
+
+	require.Equal(t, []string{"one", "two"}, got)
+}
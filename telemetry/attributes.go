@@ -0,0 +1,240 @@
+package telemetry
+
+import (
+	"encoding/json"
+
+	"github.com/katallaxie/prompts/openai"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// request are the recorded fields of a request.
+type request struct {
+	model   string
+	attrs   []attribute.KeyValue
+	content any
+}
+
+// newRequest returns the recorded fields of the requests of the Responses and Chat Completions APIs.
+func newRequest(in any) request {
+	switch req := in.(type) {
+	case *openai.ResponseRequest:
+		r := request{model: req.Model, content: req.Input}
+		r.attrs = requestAttributes(req.Model, req.Temperature, req.TopP, req.TopK, req.MaxTokens, nil, nil)
+
+		return r
+	case *openai.ChatCompletionRequest:
+		maxTokens := req.MaxCompletionTokens
+		if maxTokens == nil {
+			maxTokens = req.MaxTokens
+		}
+
+		r := request{model: req.Model, content: req.Messages}
+		r.attrs = requestAttributes(req.Model, req.Temperature, req.TopP, nil, maxTokens, req.Seed, req.Stop)
+
+		return r
+	case openai.ChatCompletionRequestUnwrapper:
+		return newRequest(req.Unwrap())
+	default:
+		return request{}
+	}
+}
+
+func requestAttributes(model string, temperature *float32, topP *float64, topK, maxTokens, seed *int, stop []string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{}
+
+	if model != "" {
+		attrs = append(attrs, semconv.GenAIRequestModelKey.String(model))
+	}
+
+	if temperature != nil {
+		attrs = append(attrs, semconv.GenAIRequestTemperatureKey.Float64(float64(*temperature)))
+	}
+
+	if topP != nil {
+		attrs = append(attrs, semconv.GenAIRequestTopPKey.Float64(*topP))
+	}
+
+	if topK != nil {
+		attrs = append(attrs, semconv.GenAIRequestTopKKey.Int(*topK))
+	}
+
+	if maxTokens != nil {
+		attrs = append(attrs, semconv.GenAIRequestMaxTokensKey.Int(*maxTokens))
+	}
+
+	if seed != nil {
+		attrs = append(attrs, semconv.GenAIRequestSeedKey.Int(*seed))
+	}
+
+	if len(stop) > 0 {
+		attrs = append(attrs, semconv.GenAIRequestStopSequencesKey.StringSlice(stop))
+	}
+
+	return attrs
+}
+
+// response are the recorded fields of a response.
+type response struct {
+	id            string
+	model         string
+	finishReasons []string
+	usage         bool
+	inputTokens   int
+	outputTokens  int
+	content       any
+}
+
+// newResponse returns the recorded fields of the responses and stream events
+// of the Responses and Chat Completions APIs.
+func newResponse(out any) response {
+	switch res := out.(type) {
+	case *openai.Response:
+		if res == nil {
+			return response{}
+		}
+
+		r := response{id: res.ID, model: res.Model, content: res.Output}
+		if reason := finishReason(res); reason != "" {
+			r.finishReasons = []string{reason}
+		}
+
+		if res.Usage != nil {
+			r.usage, r.inputTokens, r.outputTokens = true, res.Usage.InputTokens, res.Usage.OutputTokens
+		}
+
+		return r
+	case *openai.ResponseStreamEvent:
+		if ev, ok := res.Event.(openai.ResponseStreamEventResponse); ok && ev.Response.Status.Done() {
+			return newResponse(&ev.Response)
+		}
+
+		return response{}
+	case *openai.ChatCompletionResponse:
+		if res == nil {
+			return response{}
+		}
+
+		r := response{id: res.ID, model: res.Model, content: res.Choices}
+		for _, c := range res.Choices {
+			r.finishReasons = append(r.finishReasons, string(c.FinishReason))
+		}
+
+		r.setUsage(res.Usage)
+
+		return r
+	case *openai.ChatCompletionChunk:
+		if res == nil {
+			return response{}
+		}
+
+		r := response{id: res.ID, model: res.Model}
+		for _, c := range res.Choices {
+			if c.FinishReason != "" {
+				r.finishReasons = append(r.finishReasons, string(c.FinishReason))
+			}
+		}
+
+		r.setUsage(res.Usage)
+
+		return r
+	case openai.ChatCompletionResponseUnwrapper:
+		return newResponse(res.Unwrap())
+	case openai.ChatCompletionChunkUnwrapper:
+		return newResponse(res.Unwrap())
+	default:
+		return response{}
+	}
+}
+
+func (r *response) setUsage(usage *openai.CompletionUsage) {
+	if usage != nil {
+		r.usage, r.inputTokens, r.outputTokens = true, usage.PromptTokens, usage.CompletionTokens
+	}
+}
+
+// merge merges the fields of a later chunk of a stream.
+func (r *response) merge(o response) {
+	if o.id != "" {
+		r.id = o.id
+	}
+
+	if o.model != "" {
+		r.model = o.model
+	}
+
+	r.finishReasons = append(r.finishReasons, o.finishReasons...)
+
+	if o.usage {
+		r.usage, r.inputTokens, r.outputTokens = true, o.inputTokens, o.outputTokens
+	}
+
+	if o.content != nil {
+		r.content = o.content
+	}
+}
+
+func (r *response) attributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{}
+
+	if r.id != "" {
+		attrs = append(attrs, semconv.GenAIResponseIDKey.String(r.id))
+	}
+
+	if r.model != "" {
+		attrs = append(attrs, semconv.GenAIResponseModelKey.String(r.model))
+	}
+
+	if len(r.finishReasons) > 0 {
+		attrs = append(attrs, semconv.GenAIResponseFinishReasonsKey.StringSlice(r.finishReasons))
+	}
+
+	if r.usage {
+		attrs = append(attrs,
+			semconv.GenAIUsageInputTokensKey.Int(r.inputTokens),
+			semconv.GenAIUsageOutputTokensKey.Int(r.outputTokens),
+		)
+	}
+
+	return attrs
+}
+
+// finishReason returns the finish reason of a response of the Responses API in
+// the terms of the Chat Completions API.
+func finishReason(res *openai.Response) string {
+	switch res.Status {
+	case openai.ResponseStatusCompleted:
+		if len(res.FunctionCalls()) > 0 {
+			return string(openai.FinishReasonToolCalls)
+		}
+
+		return string(openai.FinishReasonStop)
+	case openai.ResponseStatusIncomplete:
+		if res.IncompleteDetails != nil && res.IncompleteDetails.Reason == openai.IncompleteReasonMaxOutputTokens {
+			return string(openai.FinishReasonLength)
+		}
+
+		if res.IncompleteDetails != nil {
+			return string(res.IncompleteDetails.Reason)
+		}
+
+		return string(res.Status)
+	default:
+		return string(res.Status)
+	}
+}
+
+// content returns the attributes of the prompt and completion event.
+func content(input, output any) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{}
+
+	if b, err := json.Marshal(input); err == nil && input != nil {
+		attrs = append(attrs, semconv.GenAIInputMessagesKey.String(string(b)))
+	}
+
+	if b, err := json.Marshal(output); err == nil && output != nil {
+		attrs = append(attrs, semconv.GenAIOutputMessagesKey.String(string(b)))
+	}
+
+	return attrs
+}
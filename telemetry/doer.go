@@ -0,0 +1,94 @@
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/katallaxie/prompts"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ prompts.Doer = (*Doer)(nil)
+
+// Doer is a Doer that records a HTTP client span for every request and
+// injects the trace context into the request headers.
+type Doer struct {
+	doer prompts.Doer
+	inst *instruments
+}
+
+// NewDoer wraps the given Doer with tracing. If a nil doer is given, the
+// http.DefaultClient will be used. The gen_ai.provider.name is only recorded
+// if it is set with WithProviderName.
+//
+//	client := prompts.NewClient().Doer(telemetry.NewDoer(prompts.DefaultClient))
+func NewDoer(doer prompts.Doer, opts ...Opt) *Doer {
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+
+	return &Doer{doer: doer, inst: newInstruments(nil, opts...)}
+}
+
+// Do sends the request with the wrapped Doer.
+func (d *Doer) Do(req *http.Request) (*http.Response, error) {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLFull(redactedURL(req)),
+		semconv.ServerAddress(req.URL.Hostname()),
+	}
+
+	if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+
+	if d.inst.opts.ProviderName != "" {
+		attrs = append(attrs, semconv.GenAIProviderNameKey.String(d.inst.opts.ProviderName))
+	}
+
+	ctx, span := d.inst.tracer.Start(req.Context(), req.Method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	r := req.Clone(ctx)
+	d.inst.opts.Propagator.Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	res, err := d.doer.Do(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(semconv.ErrorTypeKey.String(errorType(err)))
+
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(res.StatusCode))
+
+	if res.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(res.StatusCode))
+		span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(res.StatusCode)))
+	}
+
+	return res, nil
+}
+
+// redactedURL returns the URL of the request without credentials. Some
+// providers (e.g. Gemini) accept the API key as query parameter.
+func redactedURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+
+	q := u.Query()
+	for _, k := range []string{"key", "api_key", "api-key"} {
+		if q.Has(k) {
+			q.Set(k, "REDACTED")
+		}
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
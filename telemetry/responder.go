@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"context"
+	"iter"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+)
+
+// Responder is a Responder that records a span and metrics for every call.
+type Responder[I, O any] struct {
+	next prompts.Responder[I, O]
+	inst *instruments
+}
+
+var _ prompts.Prompter[*openai.ResponseRequest, *openai.Response] = (*Responder[*openai.ResponseRequest, *openai.Response])(nil)
+
+// NewResponder wraps the given Responder with tracing and metrics.
+//
+//	prompt := telemetry.NewResponder(perplexity.New(client))
+func NewResponder[I, O any](next prompts.Responder[I, O], opts ...Opt) *Responder[I, O] {
+	return &Responder[I, O]{next: next, inst: newInstruments(next, opts...)}
+}
+
+// ProviderName returns the gen_ai.provider.name of the wrapped Responder.
+func (r *Responder[I, O]) ProviderName() string {
+	return r.inst.opts.ProviderName
+}
+
+// Respond sends the request with the wrapped Responder.
+func (r *Responder[I, O]) Respond(ctx context.Context, in I) (O, error) {
+	ctx, op := r.inst.start(ctx, in)
+
+	out, err := r.next.Respond(ctx, in)
+	if err == nil {
+		op.observe(out)
+	}
+
+	op.end(ctx, err)
+
+	return out, err
+}
+
+// Streamer is a Streamer that records a span and metrics for every stream.
+type Streamer[I, E any] struct {
+	next prompts.Streamer[I, E]
+	inst *instruments
+}
+
+var _ prompts.Streamer[*openai.ResponseRequest, *openai.ResponseStreamEvent] = (*Streamer[*openai.ResponseRequest, *openai.ResponseStreamEvent])(nil)
+
+// NewStreamer wraps the given Streamer with tracing and metrics. The span
+// ends when the iteration of the stream stops.
+func NewStreamer[I, E any](next prompts.Streamer[I, E], opts ...Opt) *Streamer[I, E] {
+	return &Streamer[I, E]{next: next, inst: newInstruments(next, opts...)}
+}
+
+// ProviderName returns the gen_ai.provider.name of the wrapped Streamer.
+func (s *Streamer[I, E]) ProviderName() string {
+	return s.inst.opts.ProviderName
+}
+
+// Stream streams the request with the wrapped Streamer.
+func (s *Streamer[I, E]) Stream(ctx context.Context, in I) iter.Seq2[E, error] {
+	return func(yield func(E, error) bool) {
+		ctx, op := s.inst.start(ctx, in)
+
+		var err error
+		defer func() { op.end(ctx, err) }()
+
+		for e, eerr := range s.next.Stream(ctx, in) {
+			if eerr != nil {
+				err = eerr
+			} else {
+				op.observe(e)
+			}
+
+			if !yield(e, eerr) {
+				return
+			}
+		}
+	}
+}
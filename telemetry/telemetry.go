@@ -0,0 +1,216 @@
+// Package telemetry instruments prompts with OpenTelemetry. Responders and
+// Streamers are wrapped with spans and metrics following the semantic
+// conventions of generative AI (gen_ai.*), Doers with HTTP client spans that
+// propagate the trace context to the provider.
+//
+//	client := prompts.NewClient().Doer(telemetry.NewDoer(prompts.DefaultClient))
+//	prompt := telemetry.NewResponder(perplexity.New(client))
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/katallaxie/prompts"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/semconv/v1.40.0/genaiconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the tracer and meter.
+const ScopeName = "github.com/katallaxie/prompts/telemetry"
+
+// EventInferenceDetails is the name of the span event that carries the
+// prompt and completion if capturing content is enabled.
+const EventInferenceDetails = "gen_ai.client.inference.operation.details"
+
+// Opts are the options of the instrumentation.
+type Opts struct {
+	// TracerProvider is the provider of the tracer (default: the global provider).
+	TracerProvider trace.TracerProvider
+	// MeterProvider is the provider of the meter (default: the global provider).
+	MeterProvider metric.MeterProvider
+	// Propagator injects the trace context into requests (default: the global propagator).
+	Propagator propagation.TextMapPropagator
+	// ProviderName is the gen_ai.provider.name (formerly gen_ai.system). By
+	// default it is the name reported by the wrapped provider (e.g. "openai").
+	ProviderName string
+	// CaptureContent records the prompt and completion as span event.
+	CaptureContent bool
+}
+
+// Opt is a function type for configuring the instrumentation.
+type Opt func(*Opts)
+
+// WithTracerProvider sets the provider of the tracer.
+func WithTracerProvider(tp trace.TracerProvider) Opt {
+	return func(o *Opts) {
+		o.TracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the provider of the meter.
+func WithMeterProvider(mp metric.MeterProvider) Opt {
+	return func(o *Opts) {
+		o.MeterProvider = mp
+	}
+}
+
+// WithPropagator sets the propagator that injects the trace context into requests.
+func WithPropagator(p propagation.TextMapPropagator) Opt {
+	return func(o *Opts) {
+		o.Propagator = p
+	}
+}
+
+// WithProviderName sets the gen_ai.provider.name.
+func WithProviderName(name string) Opt {
+	return func(o *Opts) {
+		o.ProviderName = name
+	}
+}
+
+// WithCaptureContent records the prompt and completion as span event.
+// They may contain sensitive data and are not recorded by default.
+func WithCaptureContent() Opt {
+	return func(o *Opts) {
+		o.CaptureContent = true
+	}
+}
+
+// instruments are the tracer and metric instruments of a wrapped provider.
+type instruments struct {
+	opts     *Opts
+	tracer   trace.Tracer
+	duration genaiconv.ClientOperationDuration
+	tokens   genaiconv.ClientTokenUsage
+}
+
+func newInstruments(provider any, opts ...Opt) *instruments {
+	o := &Opts{
+		TracerProvider: otel.GetTracerProvider(),
+		MeterProvider:  otel.GetMeterProvider(),
+		Propagator:     otel.GetTextMapPropagator(),
+		ProviderName:   prompts.ProviderName(provider),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	meter := o.MeterProvider.Meter(ScopeName)
+
+	duration, err := genaiconv.NewClientOperationDuration(meter)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	tokens, err := genaiconv.NewClientTokenUsage(meter)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return &instruments{
+		opts:     o,
+		tracer:   o.TracerProvider.Tracer(ScopeName),
+		duration: duration,
+		tokens:   tokens,
+	}
+}
+
+// operation is an instrumented call of a provider.
+type operation struct {
+	inst  *instruments
+	span  trace.Span
+	start time.Time
+	req   request
+	res   response
+}
+
+// start starts the span of the call with the request.
+func (i *instruments) start(ctx context.Context, in any) (context.Context, *operation) {
+	req := newRequest(in)
+
+	name := string(genaiconv.OperationNameChat)
+	if req.model != "" {
+		name += " " + req.model
+	}
+
+	attrs := append([]attribute.KeyValue{
+		semconv.GenAIOperationNameKey.String(string(genaiconv.OperationNameChat)),
+		semconv.GenAIProviderNameKey.String(i.opts.ProviderName),
+	}, req.attrs...)
+
+	ctx, span := i.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+	return ctx, &operation{inst: i, span: span, start: time.Now(), req: req}
+}
+
+// observe records the response (or the chunk of a stream) of the call.
+func (o *operation) observe(out any) {
+	o.res.merge(newResponse(out))
+}
+
+// end ends the span of the call and records the metrics.
+func (o *operation) end(ctx context.Context, err error) {
+	defer o.span.End()
+
+	attrs := []attribute.KeyValue{}
+	if o.req.model != "" {
+		attrs = append(attrs, semconv.GenAIRequestModelKey.String(o.req.model))
+	}
+
+	if o.res.model != "" {
+		attrs = append(attrs, semconv.GenAIResponseModelKey.String(o.res.model))
+	}
+
+	o.span.SetAttributes(o.res.attributes()...)
+
+	if o.inst.opts.CaptureContent {
+		o.span.AddEvent(EventInferenceDetails, trace.WithAttributes(content(o.req.content, o.res.content)...))
+	}
+
+	provider := genaiconv.ProviderNameAttr(o.inst.opts.ProviderName)
+
+	if err != nil {
+		typ := errorType(err)
+
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+		o.span.SetAttributes(semconv.ErrorTypeKey.String(typ))
+
+		attrs = append(attrs, semconv.ErrorTypeKey.String(typ))
+	}
+
+	o.inst.duration.Record(ctx, time.Since(o.start).Seconds(), genaiconv.OperationNameChat, provider, attrs...)
+
+	if o.res.usage {
+		o.inst.tokens.Record(ctx, int64(o.res.inputTokens), genaiconv.OperationNameChat, provider, genaiconv.TokenTypeInput, attrs...)
+		o.inst.tokens.Record(ctx, int64(o.res.outputTokens), genaiconv.OperationNameChat, provider, genaiconv.TokenTypeOutput, attrs...)
+	}
+}
+
+// errorType returns the error.type of the error.
+func errorType(err error) string {
+	var perr *prompts.PromptError
+
+	switch {
+	case errors.As(err, &perr) && perr.StatusCode != 0:
+		return strconv.Itoa(perr.StatusCode)
+	case errors.As(err, &perr) && perr.ErrorCode != "":
+		return perr.ErrorCode
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return string(genaiconv.ErrorTypeOther)
+	}
+}
@@ -0,0 +1,228 @@
+package telemetry_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/katallaxie/prompts"
+	"github.com/katallaxie/prompts/openai"
+	"github.com/katallaxie/prompts/perplexity"
+	"github.com/katallaxie/prompts/promptstest"
+	"github.com/katallaxie/prompts/telemetry"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newProviders(t *testing.T) (*tracetest.SpanRecorder, *sdkmetric.ManualReader, []telemetry.Opt) {
+	t.Helper()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	opts := []telemetry.Opt{
+		telemetry.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		telemetry.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		telemetry.WithProviderName("perplexity"),
+	}
+
+	return spans, reader, opts
+}
+
+func attributes(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := map[attribute.Key]attribute.Value{}
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+
+	return m
+}
+
+func metrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	m := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, mm := range sm.Metrics {
+			m[mm.Name] = mm.Data
+		}
+	}
+
+	return m
+}
+
+func TestResponder(t *testing.T) {
+	spans, reader, opts := newProviders(t)
+
+	reply := promptstest.Text("It is sunny.")
+	reply.Response.Model = "sonar-pro-2025"
+	reply.Response.Usage = &openai.ResponseUsage{InputTokens: 12, OutputTokens: 4}
+
+	fake := promptstest.NewFake(t).On(promptstest.Any(), reply)
+	prompt := telemetry.NewResponder(fake, append(opts, telemetry.WithCaptureContent())...)
+
+	temperature := float32(0.2)
+	maxTokens := 100
+
+	req := openai.NewResponseRequest(openai.WithInput(openai.NewInputMessage(openai.RoleUser, "What is the weather?")))
+	req.Model = "sonar-pro"
+	req.Temperature = &temperature
+	req.MaxTokens = &maxTokens
+
+	_, err := prompt.Respond(context.Background(), req)
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, "chat sonar-pro", ended[0].Name())
+
+	attrs := attributes(ended[0].Attributes())
+	require.Equal(t, "chat", attrs["gen_ai.operation.name"].AsString())
+	require.Equal(t, "perplexity", attrs["gen_ai.provider.name"].AsString())
+	require.Equal(t, "sonar-pro", attrs["gen_ai.request.model"].AsString())
+	require.InDelta(t, 0.2, attrs["gen_ai.request.temperature"].AsFloat64(), 0.001)
+	require.Equal(t, int64(100), attrs["gen_ai.request.max_tokens"].AsInt64())
+	require.Equal(t, "resp_1", attrs["gen_ai.response.id"].AsString())
+	require.Equal(t, "sonar-pro-2025", attrs["gen_ai.response.model"].AsString())
+	require.Equal(t, []string{"stop"}, attrs["gen_ai.response.finish_reasons"].AsStringSlice())
+	require.Equal(t, int64(12), attrs["gen_ai.usage.input_tokens"].AsInt64())
+	require.Equal(t, int64(4), attrs["gen_ai.usage.output_tokens"].AsInt64())
+
+	events := ended[0].Events()
+	require.Len(t, events, 1)
+	require.Equal(t, telemetry.EventInferenceDetails, events[0].Name)
+
+	details := attributes(events[0].Attributes)
+	require.Contains(t, details["gen_ai.input.messages"].AsString(), "What is the weather?")
+	require.Contains(t, details["gen_ai.output.messages"].AsString(), "It is sunny.")
+
+	m := metrics(t, reader)
+
+	duration, ok := m["gen_ai.client.operation.duration"].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 1)
+	require.Equal(t, uint64(1), duration.DataPoints[0].Count)
+
+	tokens, ok := m["gen_ai.client.token.usage"].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, tokens.DataPoints, 2)
+
+	sums := map[string]int64{}
+	for _, dp := range tokens.DataPoints {
+		typ, _ := dp.Attributes.Value("gen_ai.token.type")
+		sums[typ.AsString()] = dp.Sum
+	}
+
+	require.Equal(t, map[string]int64{"input": 12, "output": 4}, sums)
+}
+
+func TestResponder_Error(t *testing.T) {
+	spans, _, opts := newProviders(t)
+
+	fake := promptstest.NewFake(t).On(promptstest.Any(), promptstest.Error(http.StatusTooManyRequests, "rate limit exceeded"))
+
+	_, err := telemetry.NewResponder(fake, opts...).Respond(context.Background(), openai.NewResponseRequest())
+	require.Error(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	require.Equal(t, codes.Error, ended[0].Status().Code)
+	require.Equal(t, "429", attributes(ended[0].Attributes())["error.type"].AsString())
+}
+
+type doer func(*http.Request) (*http.Response, error)
+
+func (d doer) Do(r *http.Request) (*http.Response, error) {
+	return d(r)
+}
+
+func TestResponder_ChatCompletions(t *testing.T) {
+	spans, _, opts := newProviders(t)
+
+	client := prompts.NewClient().Doer(doer(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: -1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`{
+				"id": "1",
+				"model": "sonar",
+				"choices": [{"index": 0, "message": {"role": "assistant", "content": "It is sunny."}, "finish_reason": "stop"}],
+				"usage": {"prompt_tokens": 12, "completion_tokens": 4, "total_tokens": 16},
+				"citations": ["https://example.com"]
+			}`)),
+		}, nil
+	}))
+
+	req := &perplexity.ChatCompletionRequest{}
+	req.Model = "sonar"
+	req.Messages = []perplexity.ChatCompletionMessage{openai.NewChatCompletionMessage(openai.RoleUser, "What is the weather?")}
+
+	_, err := telemetry.NewResponder(perplexity.NewChatCompletions(client), opts...).Respond(context.Background(), req)
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+
+	attrs := attributes(ended[0].Attributes())
+	require.Equal(t, "sonar", attrs["gen_ai.request.model"].AsString())
+	require.Equal(t, "1", attrs["gen_ai.response.id"].AsString())
+	require.Equal(t, int64(12), attrs["gen_ai.usage.input_tokens"].AsInt64())
+	require.Equal(t, int64(4), attrs["gen_ai.usage.output_tokens"].AsInt64())
+}
+
+func TestStreamer(t *testing.T) {
+	spans, _, opts := newProviders(t)
+
+	reply := promptstest.Text("It is sunny.")
+	reply.Response.Usage = &openai.ResponseUsage{InputTokens: 12, OutputTokens: 4}
+
+	srv := promptstest.NewServer(t, reply)
+	stream := telemetry.NewStreamer(openai.NewStreamer(srv.Client()), opts...)
+
+	for _, err := range stream.Stream(context.Background(), openai.NewResponseRequest()) {
+		require.NoError(t, err)
+		require.Empty(t, spans.Ended())
+	}
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+
+	attrs := attributes(ended[0].Attributes())
+	require.Equal(t, "resp_1", attrs["gen_ai.response.id"].AsString())
+	require.Equal(t, int64(4), attrs["gen_ai.usage.output_tokens"].AsInt64())
+}
+
+func TestDoer(t *testing.T) {
+	spans, _, opts := newProviders(t)
+
+	srv := promptstest.NewServer(t, promptstest.Text("It is sunny."))
+
+	doer := telemetry.NewDoer(srv.Doer(), append(opts, telemetry.WithPropagator(propagation.TraceContext{}))...)
+	prompt := telemetry.NewResponder(openai.New(prompts.NewClient().Doer(doer)), opts...)
+
+	_, err := prompt.Respond(context.Background(), openai.NewResponseRequest())
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+
+	client, chat := ended[0], ended[1]
+	require.Equal(t, http.MethodPost, client.Name())
+	require.Equal(t, chat.SpanContext().SpanID(), client.Parent().SpanID())
+
+	attrs := attributes(client.Attributes())
+	require.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+	require.Equal(t, "api.openai.com", attrs["server.address"].AsString())
+}